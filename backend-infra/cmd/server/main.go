@@ -46,6 +46,11 @@ func main() {
 	routes.SetupProfileRoutes(app, jwtManager)
 	routes.SetupFileRoutes(app, jwtManager)
 	routes.SetupPurchaseRoutes(app, jwtManager)
+	routes.SetupSellerRoutes(app, jwtManager)
+	routes.SetupCartRoutes(app, jwtManager)
+	routes.SetupReviewRoutes(app, jwtManager)
+	routes.SetupDisputeRoutes(app, jwtManager)
+	routes.SetupProductRoutes(app, jwtManager)
 
 	// Run server
 	port := v.GetString("SERVER_PORT")
@@ -0,0 +1,44 @@
+package dtos
+
+// Cart API Request DTOs
+type AddCartItemRequest struct {
+	ProductID string `json:"productId" validate:"required"`
+	Qty       int    `json:"qty" validate:"required,min=1"`
+}
+
+type UpdateCartItemRequest struct {
+	Qty int `json:"qty" validate:"required,min=1"`
+}
+
+type CheckoutRequest struct {
+	SenderName          string            `json:"senderName" validate:"required,min=4,max=55"`
+	SenderContactType   string            `json:"senderContactType" validate:"required,oneof=email phone"`
+	SenderContactDetail string            `json:"senderContactDetail" validate:"required"`
+	VoucherCode         string            `json:"voucherCode" validate:"omitempty,max=32"`
+	ShippingAddressID   string            `json:"shippingAddressId" validate:"omitempty,uuid"`
+	Shipping            []ShippingRequest `json:"shipping" validate:"omitempty,dive"`
+}
+
+// Cart API Response DTOs
+type CartResponse struct {
+	Items      []CartItemResponse `json:"items"`
+	TotalPrice float64            `json:"totalPrice"`
+	Currency   string             `json:"currency,omitempty"`
+	UpdatedAt  string             `json:"updatedAt,omitempty"`
+}
+
+type CartItemResponse struct {
+	ProductID        string  `json:"productId"`
+	SellerID         string  `json:"sellerId"`
+	Name             string  `json:"name"`
+	Category         string  `json:"category"`
+	SKU              string  `json:"sku"`
+	FileThumbnailURI string  `json:"fileThumbnailUri"`
+	Qty              int     `json:"qty"`
+	Price            float64 `json:"price"`
+	SavedPrice       float64 `json:"savedPrice"`
+	PriceChanged     bool    `json:"priceChanged"`
+	LineTotal        float64 `json:"lineTotal"`
+	Stock            int     `json:"stock"`
+	Available        bool    `json:"available"`
+}
@@ -0,0 +1,56 @@
+package dtos
+
+// Dispute API Request DTOs
+
+// DisputeMessageRequest opens a dispute or adds to its thread. FileIds are
+// evidence the sender uploaded to the file store.
+type DisputeMessageRequest struct {
+	Message string   `json:"message" validate:"required,min=1,max=2000"`
+	FileIds []string `json:"fileIds" validate:"omitempty,max=5,dive,required,uuid"`
+}
+
+// ResolveDisputeRequest closes a dispute. Outcome cancel or complete forces
+// the purchase into that status, none leaves it as it is.
+type ResolveDisputeRequest struct {
+	Outcome string `json:"outcome" validate:"required,oneof=cancel complete none"`
+	Note    string `json:"note" validate:"required,min=1,max=1000"`
+}
+
+// Dispute API Response DTOs
+type DisputeResponse struct {
+	DisputeID      string                   `json:"disputeId"`
+	PurchaseID     string                   `json:"purchaseId"`
+	OpenedBy       string                   `json:"openedBy"`
+	OpenedByRole   string                   `json:"openedByRole"`
+	Status         string                   `json:"status"`
+	Outcome        string                   `json:"outcome,omitempty"`
+	ResolutionNote string                   `json:"resolutionNote,omitempty"`
+	ResolvedBy     *string                  `json:"resolvedBy,omitempty"`
+	EscalatedAt    *string                  `json:"escalatedAt,omitempty"`
+	ResolvedAt     *string                  `json:"resolvedAt,omitempty"`
+	Messages       []DisputeMessageResponse `json:"messages,omitempty"`
+	CreatedAt      string                   `json:"createdAt"`
+	UpdatedAt      string                   `json:"updatedAt"`
+}
+
+type DisputeMessageResponse struct {
+	MessageID  string            `json:"messageId"`
+	SenderID   string            `json:"senderId"`
+	SenderRole string            `json:"senderRole"`
+	Message    string            `json:"message"`
+	Evidence   []DisputeEvidence `json:"evidence"`
+	CreatedAt  string            `json:"createdAt"`
+}
+
+type DisputeEvidence struct {
+	FileID           string `json:"fileId"`
+	FileURI          string `json:"fileUri"`
+	FileThumbnailURI string `json:"fileThumbnailUri"`
+}
+
+type ListDisputesResponse struct {
+	Disputes []DisputeResponse `json:"disputes"`
+	Total    int               `json:"total"`
+	Page     int               `json:"page"`
+	Limit    int               `json:"limit"`
+}
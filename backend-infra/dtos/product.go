@@ -0,0 +1,35 @@
+package dtos
+
+// Product API Request DTOs
+type ProductRequest struct {
+	Name     string  `json:"name" validate:"required,min=4,max=32"`
+	Category string  `json:"category" validate:"required,oneof=Food Beverage Clothes Furniture Tools"`
+	Qty      int     `json:"qty" validate:"min=0"`
+	Price    float64 `json:"price" validate:"required,min=100"`
+	Currency string  `json:"currency" validate:"omitempty,len=3,uppercase"`
+	SKU      string  `json:"sku" validate:"required,max=32"`
+	FileID   string  `json:"fileId" validate:"required,uuid"`
+}
+
+// Product API Response DTOs
+type ProductResponse struct {
+	ID               string  `json:"productId"`
+	Name             string  `json:"name"`
+	Category         string  `json:"category"`
+	Qty              int     `json:"qty"`
+	Price            float64 `json:"price"`
+	Currency         string  `json:"currency"`
+	SKU              string  `json:"sku"`
+	FileID           string  `json:"fileId"`
+	FileURI          string  `json:"fileUri"`
+	FileThumbnailURI string  `json:"fileThumbnailUri"`
+	SellerID         string  `json:"sellerId"`
+	CreatedAt        string  `json:"createdAt"`
+	UpdatedAt        string  `json:"updatedAt"`
+}
+
+type ListProductsResponse struct {
+	Products []ProductResponse `json:"products"`
+	Limit    int               `json:"limit"`
+	Offset   int               `json:"offset"`
+}
@@ -14,3 +14,16 @@ type UpdateUserRequest struct {
 	BankAccountHolder string `gorm:"not null;column:bankAccountHolder" json:"bankAccountHolder" validate:"required,min=4,max=32"`
 	BankAccountNumber string `gorm:"not null;column:bankAccountNumber" json:"bankAccountNumber" validate:"required,min=4,max=32"`
 }
+
+type AddressRequest struct {
+	Label         string `json:"label" validate:"max=32"`
+	RecipientName string `json:"recipientName" validate:"required,min=2,max=64"`
+	Phone         string `json:"phone" validate:"required,min=8,max=20"`
+	AddressLine1  string `json:"addressLine1" validate:"required,max=255"`
+	AddressLine2  string `json:"addressLine2" validate:"max=255"`
+	City          string `json:"city" validate:"required,max=64"`
+	Province      string `json:"province" validate:"required,max=64"`
+	PostalCode    string `json:"postalCode" validate:"required,numeric,min=3,max=10"`
+	Country       string `json:"country" validate:"omitempty,len=2,uppercase"`
+	IsDefault     bool   `json:"isDefault"`
+}
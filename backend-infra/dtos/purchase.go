@@ -6,6 +6,15 @@ type CreatePurchaseRequest struct {
 	SenderName     string                `json:"senderName" validate:"required,min=4,max=55"`
 	SenderContactType    string          `json:"senderContactType" validate:"required,oneof=email phone"`
 	SenderContactDetail  string          `json:"senderContactDetail" validate:"required"`
+	VoucherCode          string          `json:"voucherCode" validate:"omitempty,max=32"`
+	ShippingAddressID    string          `json:"shippingAddressId" validate:"omitempty,uuid"`
+	Shipping             []ShippingRequest `json:"shipping" validate:"omitempty,dive"`
+}
+
+// ShippingRequest picks the shipping method for one seller's items
+type ShippingRequest struct {
+	SellerID string `json:"sellerId" validate:"required,uuid"`
+	Method   string `json:"method" validate:"required,max=32"`
 }
 
 type PurchaseItemRequest struct {
@@ -14,34 +23,64 @@ type PurchaseItemRequest struct {
 }
 
 type PaymentProofRequest struct {
-	FileIds []string `json:"fileIds" validate:"required,min=1,dive,required"`
+	SellerID string   `json:"sellerId" validate:"omitempty,uuid"`
+	FileIds  []string `json:"fileIds" validate:"required,min=1,max=10,dive,required,uuid"`
+}
+
+type CancelPurchaseRequest struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+type RefundRequest struct {
+	Reason string `json:"reason" validate:"required,min=1,max=255"`
+}
+
+type ConfirmReceiptRequest struct {
+	SellerID string `json:"sellerId" validate:"omitempty,uuid"`
 }
 
 // Purchase API Response DTOs
 type PurchaseResponse struct {
 	PurchaseID     string                `json:"purchaseId"`
+	Status         string                `json:"status"`
 	PurchasedItems []PurchaseItemResponse `json:"purchasedItems"`
 	TotalPrice     float64               `json:"totalPrice"`
+	Discount       float64               `json:"discount"`
+	VoucherCode    string                `json:"voucherCode,omitempty"`
+	ShippingCost   float64               `json:"shippingCost"`
+	Currency       string                `json:"currency"`
 	PaymentDetails []PaymentDetail       `json:"paymentDetails"`
+	ShippingAddress *ShippingAddress     `json:"shippingAddress,omitempty"`
+	Shipments      []ShipmentResponse    `json:"shipments"`
 }
 
 type GetPurchaseResponse struct {
 	PurchaseID       string                `json:"purchaseId"`
 	UserID           string                `json:"userId"`
+	Status           string                `json:"status"`
+	StatusHistory    StatusTimestamps      `json:"statusHistory"`
+	StatusReason     string                `json:"statusReason,omitempty"`
 	PaymentProofIds  []string              `json:"paymentProofIds"`
+	PaymentProofs    []PaymentProofFile    `json:"paymentProofs"`
 	PurchasedItems   []PurchaseItemResponse `json:"purchasedItems"`
 	TotalPrice       float64               `json:"totalPrice"`
+	Discount         float64               `json:"discount"`
+	VoucherCode      string                `json:"voucherCode,omitempty"`
+	ShippingCost     float64               `json:"shippingCost"`
+	Currency         string                `json:"currency"`
 	PaymentDetails   []PaymentDetail       `json:"paymentDetails"`
+	ShippingAddress  *ShippingAddress      `json:"shippingAddress,omitempty"`
+	Shipments        []ShipmentResponse    `json:"shipments"`
+	Refund           *RefundResponse       `json:"refund,omitempty"`
 	SenderInfo       SenderInfo            `json:"senderInfo"`
 	CreatedAt        string                `json:"createdAt"`
 	UpdatedAt        string                `json:"updatedAt"`
 }
 
 type ListPurchasesResponse struct {
-	Purchases []GetPurchaseResponse `json:"purchases"`
-	Total     int                   `json:"total"`
-	Page      int                   `json:"page"`
-	Limit     int                   `json:"limit"`
+	Purchases  []GetPurchaseResponse `json:"purchases"`
+	NextCursor string                `json:"nextCursor,omitempty"`
+	Limit      int                   `json:"limit"`
 }
 
 type PurchaseItemResponse struct {
@@ -50,6 +89,7 @@ type PurchaseItemResponse struct {
 	Category          string  `json:"category"`
 	Qty               int     `json:"qty"`
 	Price             float64 `json:"price"`
+	LineTotal         float64 `json:"lineTotal"`
 	SKU               string  `json:"sku"`
 	FileID            string  `json:"fileId"`
 	FileURI           string  `json:"fileUri"`
@@ -59,10 +99,38 @@ type PurchaseItemResponse struct {
 }
 
 type PaymentDetail struct {
+	SellerID          string  `json:"sellerId"`
 	BankAccountName   string  `json:"bankAccountName"`
 	BankAccountHolder string  `json:"bankAccountHolder"`
 	BankAccountNumber string  `json:"bankAccountNumber"`
 	TotalPrice        float64 `json:"totalPrice"`
+	Discount          float64 `json:"discount"`
+	Status            string  `json:"status"`
+	StatusReason      string  `json:"statusReason,omitempty"`
+	ProofUploadedAt   *string `json:"proofUploadedAt,omitempty"`
+	ConfirmedAt       *string `json:"confirmedAt,omitempty"`
+}
+
+type ShippingAddress struct {
+	AddressID     string `json:"addressId"`
+	RecipientName string `json:"recipientName"`
+	Phone         string `json:"phone"`
+	AddressLine1  string `json:"addressLine1"`
+	AddressLine2  string `json:"addressLine2,omitempty"`
+	City          string `json:"city"`
+	Province      string `json:"province"`
+	PostalCode    string `json:"postalCode"`
+	Country       string `json:"country"`
+}
+
+type ShipmentResponse struct {
+	SellerID       string  `json:"sellerId"`
+	Method         string  `json:"method"`
+	Cost           float64 `json:"cost"`
+	TrackingNumber string  `json:"trackingNumber,omitempty"`
+	Status         string  `json:"status"`
+	ShippedAt      *string `json:"shippedAt,omitempty"`
+	ReceivedAt     *string `json:"receivedAt,omitempty"`
 }
 
 type SenderInfo struct {
@@ -70,3 +138,36 @@ type SenderInfo struct {
 	SenderContactType   string `json:"senderContactType"`
 	SenderContactDetail string `json:"senderContactDetail"`
 }
+
+type StatusTimestamps struct {
+	ProofUploadedAt   *string `json:"proofUploadedAt,omitempty"`
+	ConfirmedAt       *string `json:"confirmedAt,omitempty"`
+	CompletedAt       *string `json:"completedAt,omitempty"`
+	CancelledAt       *string `json:"cancelledAt,omitempty"`
+	ExpiredAt         *string `json:"expiredAt,omitempty"`
+	RefundRequestedAt *string `json:"refundRequestedAt,omitempty"`
+	RefundedAt        *string `json:"refundedAt,omitempty"`
+}
+
+type RefundResponse struct {
+	Status           string  `json:"status"`
+	Reason           string  `json:"reason"`
+	ResolutionReason string  `json:"resolutionReason,omitempty"`
+	RequestedAt      string  `json:"requestedAt"`
+	ResolvedAt       *string `json:"resolvedAt,omitempty"`
+}
+
+type NotificationResponse struct {
+	NotificationID string `json:"notificationId"`
+	PurchaseID     string `json:"purchaseId"`
+	Type           string `json:"type"`
+	Message        string `json:"message"`
+	CreatedAt      string `json:"createdAt"`
+}
+
+type ListNotificationsResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	Total         int                    `json:"total"`
+	Page          int                    `json:"page"`
+	Limit         int                    `json:"limit"`
+}
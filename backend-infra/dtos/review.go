@@ -0,0 +1,53 @@
+package dtos
+
+// Review API Request DTOs
+
+// CreateReviewRequest rates a product of a completed purchase
+type CreateReviewRequest struct {
+	ProductID string   `json:"productId" validate:"required"`
+	Rating    int      `json:"rating" validate:"required,min=1,max=5"`
+	Comment   string   `json:"comment" validate:"max=2000"`
+	FileIds   []string `json:"fileIds" validate:"omitempty,max=5,dive,required,uuid"`
+}
+
+// Review API Response DTOs
+type ReviewResponse struct {
+	ReviewID    string        `json:"reviewId"`
+	PurchaseID  string        `json:"purchaseId"`
+	ProductID   string        `json:"productId"`
+	ProductName string        `json:"productName"`
+	SellerID    string        `json:"sellerId"`
+	UserID      string        `json:"userId"`
+	Rating      int           `json:"rating"`
+	Comment     string        `json:"comment"`
+	Images      []ReviewImage `json:"images"`
+	CreatedAt   string        `json:"createdAt"`
+}
+
+type ReviewImage struct {
+	FileID           string `json:"fileId"`
+	FileURI          string `json:"fileUri"`
+	FileThumbnailURI string `json:"fileThumbnailUri"`
+}
+
+type ListReviewsResponse struct {
+	Reviews []ReviewResponse `json:"reviews"`
+	Total   int              `json:"total"`
+	Page    int              `json:"page"`
+	Limit   int              `json:"limit"`
+}
+
+// SellerRatingResponse is the average of all the ratings a seller received
+type SellerRatingResponse struct {
+	SellerID      string  `json:"sellerId"`
+	AverageRating float64 `json:"averageRating"`
+	ReviewCount   int     `json:"reviewCount"`
+}
+
+type ListSellerReviewsResponse struct {
+	Rating  SellerRatingResponse `json:"rating"`
+	Reviews []ReviewResponse     `json:"reviews"`
+	Total   int                  `json:"total"`
+	Page    int                  `json:"page"`
+	Limit   int                  `json:"limit"`
+}
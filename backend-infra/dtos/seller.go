@@ -0,0 +1,136 @@
+package dtos
+
+// Seller order API Request DTOs
+type RejectPaymentRequest struct {
+	Reason string `json:"reason" validate:"required,min=1,max=255"`
+}
+
+type RejectRefundRequest struct {
+	Reason string `json:"reason" validate:"required,min=1,max=255"`
+}
+
+type ShipOrderRequest struct {
+	TrackingNumber string `json:"trackingNumber" validate:"required,max=64"`
+}
+
+// CreateVoucherRequest creates a discount code for the seller's items, or for
+// whole purchases when an admin creates a platform voucher
+type CreateVoucherRequest struct {
+	Code         string   `json:"code" validate:"required,min=3,max=32,alphanum"`
+	Type         string   `json:"type" validate:"required,oneof=percentage fixed"`
+	Percentage   int      `json:"percentage" validate:"omitempty,min=1,max=100"`
+	Amount       float64  `json:"amount"`
+	MaxDiscount  *float64 `json:"maxDiscount"`
+	Currency     string   `json:"currency"`
+	MinSpend     float64  `json:"minSpend"`
+	UsageLimit   *int     `json:"usageLimit"`
+	PerUserLimit *int     `json:"perUserLimit"`
+	StartsAt     *string  `json:"startsAt"`
+	EndsAt       *string  `json:"endsAt"`
+}
+
+// Seller order API Response DTOs
+type SellerOrderResponse struct {
+	PurchaseID      string                 `json:"purchaseId"`
+	BuyerID         string                 `json:"buyerId"`
+	Status          string                 `json:"status"`
+	StatusHistory   StatusTimestamps       `json:"statusHistory"`
+	StatusReason    string                 `json:"statusReason,omitempty"`
+	PurchasedItems  []PurchaseItemResponse `json:"purchasedItems"`
+	TotalPrice      float64                `json:"totalPrice"`
+	Discount        float64                `json:"discount"`
+	Currency        string                 `json:"currency"`
+	PaymentDetail   *PaymentDetail         `json:"paymentDetail"`
+	PaymentProofs   []PaymentProofFile     `json:"paymentProofs"`
+	Refund          *RefundResponse        `json:"refund,omitempty"`
+	SenderInfo      SenderInfo             `json:"senderInfo"`
+	ShippingAddress *ShippingAddress       `json:"shippingAddress,omitempty"`
+	Shipment        *ShipmentResponse      `json:"shipment,omitempty"`
+	CreatedAt       string                 `json:"createdAt"`
+	UpdatedAt       string                 `json:"updatedAt"`
+}
+
+type ListSellerOrdersResponse struct {
+	Orders []SellerOrderResponse `json:"orders"`
+	Total  int                   `json:"total"`
+	Page   int                   `json:"page"`
+	Limit  int                   `json:"limit"`
+}
+
+type PaymentProofFile struct {
+	FileID           string  `json:"fileId"`
+	FileURI          string  `json:"fileUri"`
+	FileThumbnailURI string  `json:"fileThumbnailUri"`
+	SellerID         string  `json:"sellerId,omitempty"`
+	Status           string  `json:"status"`
+	UploadedAt       string  `json:"uploadedAt"`
+	ReviewedAt       *string `json:"reviewedAt,omitempty"`
+}
+
+// SellerAnalyticsResponse summarizes a seller's sales over a date range
+type SellerAnalyticsResponse struct {
+	From              string             `json:"from"`
+	To                string             `json:"to"`
+	Interval          string             `json:"interval"`
+	Currency          string             `json:"currency"`
+	Revenue           float64            `json:"revenue"`
+	Orders            int                `json:"orders"`
+	ItemsSold         int                `json:"itemsSold"`
+	AverageOrderValue float64            `json:"averageOrderValue"`
+	Conversion        ConversionResponse `json:"conversion"`
+	RevenueByPeriod   []RevenuePeriod    `json:"revenueByPeriod"`
+	TopProducts       []TopProduct       `json:"topProducts"`
+	TopCategories     []TopCategory      `json:"topCategories"`
+}
+
+type RevenuePeriod struct {
+	Period    string  `json:"period"`
+	Revenue   float64 `json:"revenue"`
+	Orders    int     `json:"orders"`
+	ItemsSold int     `json:"itemsSold"`
+}
+
+type TopProduct struct {
+	ProductID string  `json:"productId"`
+	Name      string  `json:"name"`
+	QtySold   int     `json:"qtySold"`
+	Revenue   float64 `json:"revenue"`
+}
+
+type TopCategory struct {
+	Category string  `json:"category"`
+	QtySold  int     `json:"qtySold"`
+	Revenue  float64 `json:"revenue"`
+}
+
+type ConversionResponse struct {
+	Created   int     `json:"created"`
+	Confirmed int     `json:"confirmed"`
+	Rate      float64 `json:"rate"`
+}
+
+type VoucherResponse struct {
+	VoucherID    string   `json:"voucherId"`
+	Code         string   `json:"code"`
+	SellerID     string   `json:"sellerId,omitempty"`
+	Type         string   `json:"type"`
+	Percentage   int      `json:"percentage,omitempty"`
+	Amount       float64  `json:"amount,omitempty"`
+	MaxDiscount  *float64 `json:"maxDiscount,omitempty"`
+	Currency     string   `json:"currency"`
+	MinSpend     float64  `json:"minSpend"`
+	UsageLimit   *int     `json:"usageLimit,omitempty"`
+	PerUserLimit *int     `json:"perUserLimit,omitempty"`
+	UsedCount    int      `json:"usedCount"`
+	StartsAt     *string  `json:"startsAt,omitempty"`
+	EndsAt       *string  `json:"endsAt,omitempty"`
+	DisabledAt   *string  `json:"disabledAt,omitempty"`
+	CreatedAt    string   `json:"createdAt"`
+}
+
+type ListVouchersResponse struct {
+	Vouchers []VoucherResponse `json:"vouchers"`
+	Total    int               `json:"total"`
+	Page     int               `json:"page"`
+	Limit    int               `json:"limit"`
+}
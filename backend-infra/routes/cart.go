@@ -0,0 +1,124 @@
+package routes
+
+import (
+	"backend-infra/config"
+	"backend-infra/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupCartRoutes sets up the shopping cart routes, served by the purchase service
+func SetupCartRoutes(app *fiber.App, jwtManager *config.JWTManager) {
+	// Protected routes group - all routes here require JWT authentication
+	protected := app.Group("/v1/cart", middleware.JWTProtected(jwtManager))
+
+	// Cart routes
+	protected.Get("/", getCart)
+	protected.Delete("/", clearCart)
+	protected.Post("/items", addCartItem)
+	protected.Put("/items/:productId", updateCartItem)
+	protected.Delete("/items/:productId", removeCartItem)
+	protected.Post("/checkout", checkoutCart)
+}
+
+// @Summary Get cart
+// @Description Get the customer's cart priced with the current product details. Items whose price changed since they were added are flagged, and items that were deleted or lack stock are marked unavailable.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} dtos.CartResponse
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/cart [get]
+func getCart(c *fiber.Ctx) error {
+	return proxyToPurchaseService(c, "GET", "/api/v1/cart")
+}
+
+// @Summary Clear cart
+// @Description Remove every product from the cart
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/cart [delete]
+func clearCart(c *fiber.Ctx) error {
+	return proxyToPurchaseService(c, "DELETE", "/api/v1/cart")
+}
+
+// @Summary Add item to cart
+// @Description Add a product to the cart, on top of the quantity already in it. All products in a cart must share a currency.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dtos.AddCartItemRequest true "Cart item"
+// @Success 200 {object} dtos.CartResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/cart/items [post]
+func addCartItem(c *fiber.Ctx) error {
+	return proxyToPurchaseService(c, "POST", "/api/v1/cart/items")
+}
+
+// @Summary Update cart item quantity
+// @Description Set the quantity of a product in the cart
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param productId path string true "Product ID"
+// @Param request body dtos.UpdateCartItemRequest true "New quantity"
+// @Success 200 {object} dtos.CartResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/cart/items/{productId} [put]
+func updateCartItem(c *fiber.Ctx) error {
+	productID := c.Params("productId")
+	return proxyToPurchaseService(c, "PUT", "/api/v1/cart/items/"+productID)
+}
+
+// @Summary Remove item from cart
+// @Description Take a product out of the cart
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param productId path string true "Product ID"
+// @Success 200 {object} dtos.CartResponse
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/cart/items/{productId} [delete]
+func removeCartItem(c *fiber.Ctx) error {
+	productID := c.Params("productId")
+	return proxyToPurchaseService(c, "DELETE", "/api/v1/cart/items/"+productID)
+}
+
+// @Summary Check out the cart
+// @Description Turn the cart into a purchase. Prices and stock are checked against the product service first: when a price changed, the cart takes the new price and 409 is returned so the customer can review it. The cart is emptied once the purchase is created.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param Idempotency-Key header string false "Unique key to safely retry the request; the original response is replayed"
+// @Param request body dtos.CheckoutRequest true "Sender of the purchase"
+// @Success 201 {object} dtos.PurchaseResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/cart/checkout [post]
+func checkoutCart(c *fiber.Ctx) error {
+	return proxyToPurchaseService(c, "POST", "/api/v1/cart/checkout")
+}
@@ -0,0 +1,154 @@
+package routes
+
+import (
+	"backend-infra/config"
+	"backend-infra/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupDisputeRoutes sets up the admin dispute routes, served by the purchase
+// service, which checks that the caller is an admin. The buyer and seller
+// dispute routes are part of SetupPurchaseRoutes.
+func SetupDisputeRoutes(app *fiber.App, jwtManager *config.JWTManager) {
+	// Protected routes group - all routes here require JWT authentication
+	protected := app.Group("/v1/admin/disputes", middleware.JWTProtected(jwtManager))
+
+	// Admin dispute routes
+	protected.Get("/", listDisputes)
+	protected.Get("/:disputeId", getDisputeByID)
+	protected.Post("/:disputeId/resolve", resolveDispute)
+}
+
+// @Summary Open a dispute
+// @Description The buyer or a seller of a purchase in progress opens a dispute, such as a transfer the seller says never arrived. The message starts the thread and fileIds attach up to 5 evidence files the caller uploaded. A purchase has at most one active dispute, and the other side is notified.
+// @Tags dispute
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.DisputeMessageRequest true "Dispute reason"
+// @Success 201 {object} dtos.DisputeResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/dispute [post]
+func openDispute(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+	return proxyToPurchaseService(c, "POST", "/api/v1/purchase/"+purchaseID+"/dispute")
+}
+
+// @Summary Get the dispute of a purchase
+// @Description Get the latest dispute of a purchase with its message thread and evidence, for the buyer and the sellers of the purchase
+// @Tags dispute
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purchaseId path string true "Purchase ID"
+// @Success 200 {object} dtos.DisputeResponse
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/dispute [get]
+func getDispute(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+	return proxyToPurchaseService(c, "GET", "/api/v1/purchase/"+purchaseID+"/dispute")
+}
+
+// @Summary Reply to a dispute
+// @Description Add a message with optional evidence files to the active dispute of a purchase. The dispute then awaits the other side, unless it was escalated, and the other side is notified.
+// @Tags dispute
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.DisputeMessageRequest true "Message"
+// @Success 201 {object} dtos.DisputeMessageResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/dispute/messages [post]
+func addDisputeMessage(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+	return proxyToPurchaseService(c, "POST", "/api/v1/purchase/"+purchaseID+"/dispute/messages")
+}
+
+// @Summary Escalate a dispute
+// @Description The buyer or a seller hands the active dispute of a purchase over to an admin, who can force the purchase to be cancelled or completed
+// @Tags dispute
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purchaseId path string true "Purchase ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/dispute/escalate [post]
+func escalateDispute(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+	return proxyToPurchaseService(c, "POST", "/api/v1/purchase/"+purchaseID+"/dispute/escalate")
+}
+
+// @Summary List disputes
+// @Description Admins get a paginated list of all disputes, newest first, optionally only those in one status
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param status query string false "Dispute status (open, awaiting_seller, awaiting_buyer, escalated, resolved)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} dtos.ListDisputesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/admin/disputes [get]
+func listDisputes(c *fiber.Ctx) error {
+	return proxyToPurchaseService(c, "GET", "/api/v1/admin/disputes")
+}
+
+// @Summary Get dispute
+// @Description Admins get any dispute with its message thread and evidence
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param disputeId path string true "Dispute ID"
+// @Success 200 {object} dtos.DisputeResponse
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/admin/disputes/{disputeId} [get]
+func getDisputeByID(c *fiber.Ctx) error {
+	disputeID := c.Params("disputeId")
+	return proxyToPurchaseService(c, "GET", "/api/v1/admin/disputes/"+disputeID)
+}
+
+// @Summary Resolve dispute
+// @Description Admins close a dispute with a note. Outcome cancel or complete forces the purchase into that status from any status it is not final in; none leaves the purchase as it is. The buyer and sellers are notified.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param disputeId path string true "Dispute ID"
+// @Param request body dtos.ResolveDisputeRequest true "Resolution"
+// @Success 200 {object} dtos.DisputeResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/admin/disputes/{disputeId}/resolve [post]
+func resolveDispute(c *fiber.Ctx) error {
+	disputeID := c.Params("disputeId")
+	return proxyToPurchaseService(c, "POST", "/api/v1/admin/disputes/"+disputeID+"/resolve")
+}
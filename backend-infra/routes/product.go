@@ -0,0 +1,166 @@
+package routes
+
+import (
+	"backend-infra/config"
+	"backend-infra/middleware"
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupProductRoutes sets up product-related routes
+func SetupProductRoutes(app *fiber.App, jwtManager *config.JWTManager) {
+	// Listing is public
+	app.Get("/v1/product", listProducts)
+
+	// Protected routes group - all routes here require JWT authentication
+	protected := app.Group("/v1/product", middleware.JWTProtected(jwtManager))
+
+	// Product routes
+	protected.Post("/", createProduct)
+	protected.Put("/:productId", updateProduct)
+	protected.Delete("/:productId", deleteProduct)
+}
+
+// @Summary List products
+// @Description Get a paginated, filterable list of products
+// @Tags product
+// @Produce json
+// @Param limit query int false "Items per page" default(5)
+// @Param offset query int false "Items to skip" default(0)
+// @Param productId query string false "Filter by product ID"
+// @Param sellerId query string false "Filter by seller ID"
+// @Param sku query string false "Filter by SKU"
+// @Param category query string false "Filter by category"
+// @Param sortBy query string false "newest, oldest, cheapest or expensive"
+// @Success 200 {object} dtos.ListProductsResponse
+// @Failure 500 {object} map[string]string
+// @Router /v1/product [get]
+func listProducts(c *fiber.Ctx) error {
+	return proxyToProductService(c, "GET", "/api/v1/product")
+}
+
+// @Summary Create a new product
+// @Description Seller can add a product to their catalogue
+// @Tags product
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dtos.ProductRequest true "Product request"
+// @Success 201 {object} dtos.ProductResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/product [post]
+func createProduct(c *fiber.Ctx) error {
+	return proxyToProductService(c, "POST", "/api/v1/product")
+}
+
+// @Summary Update a product
+// @Description Seller can update one of their products
+// @Tags product
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param productId path string true "Product ID"
+// @Param request body dtos.ProductRequest true "Product request"
+// @Success 200 {object} dtos.ProductResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/product/{productId} [put]
+func updateProduct(c *fiber.Ctx) error {
+	productID := c.Params("productId")
+	return proxyToProductService(c, "PUT", "/api/v1/product/"+productID)
+}
+
+// @Summary Delete a product
+// @Description Seller can delete one of their products
+// @Tags product
+// @Produce json
+// @Security BearerAuth
+// @Param productId path string true "Product ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/product/{productId} [delete]
+func deleteProduct(c *fiber.Ctx) error {
+	productID := c.Params("productId")
+	return proxyToProductService(c, "DELETE", "/api/v1/product/"+productID)
+}
+
+// proxyToProductService forwards requests to the product service
+func proxyToProductService(c *fiber.Ctx, method string, endpoint string) error {
+	// Get request body
+	body := c.Body()
+
+	// Create request to product service, keeping the query string for filters
+	product_service_url := c.Locals("service_urls").(*config.ServiceURLs).ProductServiceURL
+	url := product_service_url + endpoint
+	if query := string(c.Request().URI().QueryString()); query != "" {
+		url += "?" + query
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create request",
+		})
+	}
+
+	// Copy headers
+	req.Header.Set("Content-Type", "application/json")
+	for key, values := range c.GetReqHeaders() {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	// Add user context headers (for protected routes)
+	if userID := c.Locals("user_id"); userID != nil {
+		req.Header.Set("X-User-ID", userID.(string))
+		req.Header.Set("X-Auth-Gateway", "backend-infra")
+
+		// Add internal secret for secure communication
+		req.Header.Set("X-Secret", "backend-infra-internal-secret") // TODO: Make configurable
+	}
+
+	// Make request to product service
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Product service unavailable",
+		})
+	}
+	defer resp.Body.Close()
+
+	// Read response
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to read response",
+		})
+	}
+
+	// Set response status and headers
+	c.Status(resp.StatusCode)
+	for key, values := range resp.Header {
+		for _, value := range values {
+			c.Set(key, value)
+		}
+	}
+
+	// Parse and return JSON response
+	var jsonResp any
+	if err := json.Unmarshal(respBody, &jsonResp); err != nil {
+		return c.SendString(string(respBody))
+	}
+
+	return c.JSON(jsonResp)
+}
@@ -24,6 +24,13 @@ func SetupProfileRoutes(app *fiber.App, jwtManager *config.JWTManager) {
 
 	protected.Post("/link/email", UpdateEmail)
 	protected.Post("/link/phone", UpdatePhone)
+
+	// Address book routes
+	protected.Get("/addresses", ListAddresses)
+	protected.Post("/addresses", CreateAddress)
+	protected.Put("/addresses/:id", UpdateAddress)
+	protected.Delete("/addresses/:id", DeleteAddress)
+	protected.Post("/addresses/:id/default", SetDefaultAddress)
 }
 
 // @Summary Get current user profile
@@ -88,6 +95,88 @@ func UpdateProfile(c *fiber.Ctx) error {
 	return proxyToProfileService(c, "PUT", "/api/v1/user")
 }
 
+// ListAddresses is handler/controller which lists the addresses of current user
+// @Summary      List addresses
+// @Description  List the current user's shipping addresses, the default first
+// @Tags         Profile
+// @Accept       json
+// @Produce      json
+// @Security BearerAuth
+// @Success      200   {object}  map[string]interface{}
+// @Failure      401   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /v1/user/addresses [get]
+func ListAddresses(c *fiber.Ctx) error {
+	return proxyToProfileService(c, "GET", "/api/v1/user/addresses")
+}
+
+// CreateAddress is handler/controller which adds an address of current user
+// @Summary      Create address
+// @Description  Add a shipping address. The first address, or one sent with isDefault, becomes the default.
+// @Tags         Profile
+// @Accept       json
+// @Produce      json
+// @Security BearerAuth
+// @Param        address  body      dtos.AddressRequest   true  "Address"
+// @Success      201   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /v1/user/addresses [post]
+func CreateAddress(c *fiber.Ctx) error {
+	return proxyToProfileService(c, "POST", "/api/v1/user/addresses")
+}
+
+// UpdateAddress is handler/controller which replaces an address of current user
+// @Summary      Update address
+// @Description  Replace the fields of a shipping address. Sending isDefault makes it the default.
+// @Tags         Profile
+// @Accept       json
+// @Produce      json
+// @Security BearerAuth
+// @Param        id       path      string                true  "Address ID"
+// @Param        address  body      dtos.AddressRequest   true  "Address"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /v1/user/addresses/{id} [put]
+func UpdateAddress(c *fiber.Ctx) error {
+	return proxyToProfileService(c, "PUT", "/api/v1/user/addresses/"+c.Params("id"))
+}
+
+// DeleteAddress is handler/controller which removes an address of current user
+// @Summary      Delete address
+// @Description  Remove a shipping address. When it was the default, the oldest remaining address becomes the default.
+// @Tags         Profile
+// @Accept       json
+// @Produce      json
+// @Security BearerAuth
+// @Param        id   path      string  true  "Address ID"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /v1/user/addresses/{id} [delete]
+func DeleteAddress(c *fiber.Ctx) error {
+	return proxyToProfileService(c, "DELETE", "/api/v1/user/addresses/"+c.Params("id"))
+}
+
+// SetDefaultAddress is handler/controller which makes an address the default of current user
+// @Summary      Set default address
+// @Description  Make a shipping address the default, replacing the previous default
+// @Tags         Profile
+// @Accept       json
+// @Produce      json
+// @Security BearerAuth
+// @Param        id   path      string  true  "Address ID"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /v1/user/addresses/{id}/default [post]
+func SetDefaultAddress(c *fiber.Ctx) error {
+	return proxyToProfileService(c, "POST", "/api/v1/user/addresses/"+c.Params("id")+"/default")
+}
+
 func proxyToProfileService(c *fiber.Ctx, method string, endpoint string) error {
 	// Get request body
 	body := c.Body()
@@ -19,8 +19,19 @@ func SetupPurchaseRoutes(app *fiber.App, jwtManager *config.JWTManager) {
 	// Purchase routes
 	protected.Post("/", createPurchase)
 	protected.Get("/", listPurchases)
+	protected.Get("/notifications", listPurchaseNotifications)
+	protected.Get("/export", exportPurchases)
 	protected.Get("/:purchaseId", getPurchaseByID)
 	protected.Post("/:purchaseId", uploadPaymentProof)
+	protected.Get("/:purchaseId/invoice", getPurchaseInvoice)
+	protected.Post("/:purchaseId/cancel", cancelPurchase)
+	protected.Post("/:purchaseId/refund", requestRefund)
+	protected.Post("/:purchaseId/received", confirmReceipt)
+	protected.Post("/:purchaseId/reviews", createReview)
+	protected.Post("/:purchaseId/dispute", openDispute)
+	protected.Get("/:purchaseId/dispute", getDispute)
+	protected.Post("/:purchaseId/dispute/messages", addDisputeMessage)
+	protected.Post("/:purchaseId/dispute/escalate", escalateDispute)
 }
 
 // @Summary Create a new purchase
@@ -29,32 +40,125 @@ func SetupPurchaseRoutes(app *fiber.App, jwtManager *config.JWTManager) {
 // @Accept json
 // @Produce json
 // @Security BearerAuth
+// @Param Idempotency-Key header string false "Unique key to safely retry the request; the original response is replayed"
 // @Param request body dtos.CreatePurchaseRequest true "Purchase request"
 // @Success 201 {object} dtos.PurchaseResponse
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
 // @Router /v1/purchase [post]
 func createPurchase(c *fiber.Ctx) error {
 	return proxyToPurchaseService(c, "POST", "/api/v1/purchase")
 }
 
 // @Summary Upload payment proof for a purchase
-// @Description Customer can upload their payment proof photo here. After payment, decreases the real product quantity.
+// @Description Customer can upload their payment proof photo here. Each proof is for the transfer to one seller; sellerId is required when the purchase has several sellers. Stock reserved at checkout is committed once the seller confirms the payment.
 // @Tags purchase
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param purchaseId path string true "Purchase ID"
+// @Param Idempotency-Key header string false "Unique key to safely retry the request; the original response is replayed"
 // @Param request body dtos.PaymentProofRequest true "Payment proof request"
 // @Success 201 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
 // @Router /v1/purchase/{purchaseId} [post]
 func uploadPaymentProof(c *fiber.Ctx) error {
 	purchaseID := c.Params("purchaseId")
 	return proxyToPurchaseService(c, "POST", "/api/v1/purchase/"+purchaseID)
 }
 
+// @Summary Cancel a purchase
+// @Description Customer can cancel a purchase until a seller confirms their payment. The reserved stock is released and the sellers are notified.
+// @Tags purchase
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.CancelPurchaseRequest false "Cancel purchase request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/cancel [post]
+func cancelPurchase(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+	return proxyToPurchaseService(c, "POST", "/api/v1/purchase/"+purchaseID+"/cancel")
+}
+
+// @Summary Request a refund
+// @Description Customer can ask for their money back once the payment is confirmed. The sellers are notified and approve or reject the request.
+// @Tags purchase
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.RefundRequest true "Refund request"
+// @Success 201 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/refund [post]
+func requestRefund(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+	return proxyToPurchaseService(c, "POST", "/api/v1/purchase/"+purchaseID+"/refund")
+}
+
+// @Summary Confirm receipt of a shipment
+// @Description Customer confirms they received the parcel of one seller, who is notified. sellerId is required when the purchase has several sellers. The purchase is completed once every parcel is received.
+// @Tags purchase
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.ConfirmReceiptRequest false "Confirm receipt request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/received [post]
+func confirmReceipt(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+	return proxyToPurchaseService(c, "POST", "/api/v1/purchase/"+purchaseID+"/received")
+}
+
+// @Summary Review a purchased product
+// @Description Customer rates a product of one of their completed purchases with 1 to 5 stars, an optional comment and up to 5 images they uploaded. Each product can be reviewed once per purchase, and the seller is notified.
+// @Tags review
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.CreateReviewRequest true "Review"
+// @Success 201 {object} dtos.ReviewResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/reviews [post]
+func createReview(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+	return proxyToPurchaseService(c, "POST", "/api/v1/purchase/"+purchaseID+"/reviews")
+}
+
 // @Summary Get purchase by ID
 // @Description Get a specific purchase by its ID
 // @Tags purchase
@@ -64,6 +168,7 @@ func uploadPaymentProof(c *fiber.Ctx) error {
 // @Param purchaseId path string true "Purchase ID"
 // @Success 200 {object} dtos.GetPurchaseResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /v1/purchase/{purchaseId} [get]
@@ -72,14 +177,37 @@ func getPurchaseByID(c *fiber.Ctx) error {
 	return proxyToPurchaseService(c, "GET", "/api/v1/purchase/"+purchaseID)
 }
 
+// @Summary Get purchase invoice
+// @Description Download the invoices of a purchase as a PDF, one page per seller with its own sequential invoice number. The buyer gets every seller's invoice, a seller only their own.
+// @Tags purchase
+// @Produce application/pdf
+// @Security BearerAuth
+// @Param purchaseId path string true "Purchase ID"
+// @Success 200 {file} file
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/invoice [get]
+func getPurchaseInvoice(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+	return proxyToPurchaseService(c, "GET", "/api/v1/purchase/"+purchaseID+"/invoice")
+}
+
 // @Summary List user's purchases
-// @Description Get a paginated list of user's purchases
+// @Description Get the user's purchases, newest first. Pass the returned nextCursor to get the following page.
 // @Tags purchase
 // @Accept json
 // @Produce json
 // @Security BearerAuth
-// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Cursor returned by the previous page"
 // @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by status (pending_payment, proof_uploaded, confirmed, completed, cancelled, expired, refund_requested, refunded)"
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC3339 or YYYY-MM-DD, a date includes the whole day)"
+// @Param sellerId query string false "Only purchases containing items from this seller"
+// @Param productId query string false "Only purchases containing this product"
+// @Param minTotal query number false "Minimum total price"
+// @Param maxTotal query number false "Maximum total price"
 // @Success 200 {object} dtos.ListPurchasesResponse
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
@@ -88,38 +216,48 @@ func listPurchases(c *fiber.Ctx) error {
 	return proxyToPurchaseService(c, "GET", "/api/v1/purchase")
 }
 
+// @Summary List purchase notifications
+// @Description Get a paginated list of notifications about the user's purchases, such as confirmed or rejected payments
+// @Tags purchase
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} dtos.ListNotificationsResponse
+// @Failure 500 {object} map[string]string
+// @Router /v1/purchase/notifications [get]
+func listPurchaseNotifications(c *fiber.Ctx) error {
+	return proxyToPurchaseService(c, "GET", "/api/v1/purchase/notifications")
+}
+
+// @Summary Export user's purchases
+// @Description Download the user's purchases as CSV or XLSX with one row per purchased item, oldest first. Rows are streamed, so exports of any size are supported.
+// @Tags purchase
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Param format query string false "File format (csv, xlsx)" default(csv)
+// @Param status query string false "Filter by status (pending_payment, proof_uploaded, confirmed, completed, cancelled, expired, refund_requested, refunded)"
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC3339 or YYYY-MM-DD, a date includes the whole day)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/purchase/export [get]
+func exportPurchases(c *fiber.Ctx) error {
+	return streamFromPurchaseService(c, "/api/v1/purchase/export")
+}
+
 // proxyToPurchaseService forwards requests to the purchase service
 func proxyToPurchaseService(c *fiber.Ctx, method string, endpoint string) error {
-	// Get request body
-	body := c.Body()
-
-	// Create request to purchase service
-	purchase_service_url := c.Locals("service_urls").(*config.ServiceURLs).PurchaseServiceURL
-	url := purchase_service_url + endpoint
-	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	req, err := newPurchaseServiceRequest(c, method, endpoint)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": "Failed to create request",
 		})
 	}
 
-	// Copy headers
-	req.Header.Set("Content-Type", "application/json")
-	for key, values := range c.GetReqHeaders() {
-		for _, value := range values {
-			req.Header.Add(key, value)
-		}
-	}
-
-	// Add user context headers (for protected routes)
-	if userID := c.Locals("user_id"); userID != nil {
-		req.Header.Set("X-User-ID", userID.(string))
-		req.Header.Set("X-Auth-Gateway", "backend-infra")
-
-		// Add internal secret for secure communication
-		req.Header.Set("X-Secret", "backend-infra-internal-secret") // TODO: Make configurable
-	}
-
 	// Make request to purchase service
 	client := &http.Client{}
 	resp, err := client.Do(req)
@@ -149,8 +287,73 @@ func proxyToPurchaseService(c *fiber.Ctx, method string, endpoint string) error
 	// Parse and return JSON response
 	var jsonResp any
 	if err := json.Unmarshal(respBody, &jsonResp); err != nil {
-		return c.SendString(string(respBody))
+		return c.Send(respBody)
 	}
 
 	return c.JSON(jsonResp)
 }
+
+// streamFromPurchaseService forwards a GET request to the purchase service and
+// passes the response body through as it arrives, for downloads too large to
+// buffer. The body is closed once it has been sent.
+func streamFromPurchaseService(c *fiber.Ctx, endpoint string) error {
+	req, err := newPurchaseServiceRequest(c, "GET", endpoint)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create request",
+		})
+	}
+
+	client := &http.Client{}
+	resp, err := client.Do(req)
+	if err != nil {
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{
+			"error": "Purchase service unavailable",
+		})
+	}
+
+	c.Status(resp.StatusCode)
+	for key, values := range resp.Header {
+		for _, value := range values {
+			c.Set(key, value)
+		}
+	}
+	return c.SendStream(resp.Body)
+}
+
+// newPurchaseServiceRequest builds a request to the purchase service carrying
+// the client's body, query string and headers plus the authenticated user
+func newPurchaseServiceRequest(c *fiber.Ctx, method string, endpoint string) (*http.Request, error) {
+	// Get request body
+	body := c.Body()
+
+	// Create request to purchase service, keeping the query string for filters
+	purchase_service_url := c.Locals("service_urls").(*config.ServiceURLs).PurchaseServiceURL
+	url := purchase_service_url + endpoint
+	if query := string(c.Request().URI().QueryString()); query != "" {
+		url += "?" + query
+	}
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	// Copy headers
+	req.Header.Set("Content-Type", "application/json")
+	for key, values := range c.GetReqHeaders() {
+		for _, value := range values {
+			req.Header.Add(key, value)
+		}
+	}
+
+	// Add user context headers (for protected routes)
+	if userID := c.Locals("user_id"); userID != nil {
+		req.Header.Set("X-User-ID", userID.(string))
+		req.Header.Set("X-Auth-Gateway", "backend-infra")
+
+		// Add internal secret for secure communication
+		req.Header.Set("X-Secret", "backend-infra-internal-secret") // TODO: Make configurable
+	}
+
+	return req, nil
+}
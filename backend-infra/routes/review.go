@@ -0,0 +1,70 @@
+package routes
+
+import (
+	"backend-infra/config"
+	"backend-infra/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupReviewRoutes sets up the product review and seller rating routes, served by the purchase service
+func SetupReviewRoutes(app *fiber.App, jwtManager *config.JWTManager) {
+	// Protected routes group - all routes here require JWT authentication
+	protected := app.Group("/v1/reviews", middleware.JWTProtected(jwtManager))
+
+	// Review routes
+	protected.Get("/product/:productId", listProductReviews)
+	protected.Get("/seller/:sellerId", listSellerReviews)
+	protected.Get("/seller/:sellerId/rating", getSellerRating)
+}
+
+// @Summary List product reviews
+// @Description Get a paginated list of the reviews of a product, newest first
+// @Tags review
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param productId path string true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} dtos.ListReviewsResponse
+// @Failure 500 {object} map[string]string
+// @Router /v1/reviews/product/{productId} [get]
+func listProductReviews(c *fiber.Ctx) error {
+	productID := c.Params("productId")
+	return proxyToPurchaseService(c, "GET", "/api/v1/reviews/product/"+productID)
+}
+
+// @Summary List seller reviews
+// @Description Get a paginated list of the reviews of all of a seller's products, newest first, along with the seller's average rating
+// @Tags review
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param sellerId path string true "Seller ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} dtos.ListSellerReviewsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/reviews/seller/{sellerId} [get]
+func listSellerReviews(c *fiber.Ctx) error {
+	sellerID := c.Params("sellerId")
+	return proxyToPurchaseService(c, "GET", "/api/v1/reviews/seller/"+sellerID)
+}
+
+// @Summary Get seller rating
+// @Description Get the average rating of a seller over all their reviews, rounded to two decimals. Sellers without reviews have an average of 0.
+// @Tags review
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param sellerId path string true "Seller ID"
+// @Success 200 {object} dtos.SellerRatingResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/reviews/seller/{sellerId}/rating [get]
+func getSellerRating(c *fiber.Ctx) error {
+	sellerID := c.Params("sellerId")
+	return proxyToPurchaseService(c, "GET", "/api/v1/reviews/seller/"+sellerID+"/rating")
+}
@@ -0,0 +1,288 @@
+package routes
+
+import (
+	"backend-infra/config"
+	"backend-infra/middleware"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupSellerRoutes sets up the seller order inbox, voucher and analytics routes, and the admin platform voucher
+// routes, served by the purchase service, which checks that the caller of the admin routes is an admin
+func SetupSellerRoutes(app *fiber.App, jwtManager *config.JWTManager) {
+	// Protected routes group - all routes here require JWT authentication
+	protected := app.Group("/v1/seller/orders", middleware.JWTProtected(jwtManager))
+
+	// Seller order routes
+	protected.Get("/", listSellerOrders)
+	protected.Get("/export", exportSellerOrders)
+	protected.Get("/:purchaseId", getSellerOrder)
+	protected.Post("/:purchaseId/confirm", confirmPayment)
+	protected.Post("/:purchaseId/reject", rejectPayment)
+	protected.Post("/:purchaseId/ship", shipOrder)
+	protected.Post("/:purchaseId/refund/approve", approveRefund)
+	protected.Post("/:purchaseId/refund/reject", rejectRefund)
+
+	// Seller voucher routes
+	vouchers := app.Group("/v1/seller/vouchers", middleware.JWTProtected(jwtManager))
+	vouchers.Post("/", createVoucher)
+	vouchers.Get("/", listVouchers)
+	vouchers.Post("/:voucherId/disable", disableVoucher)
+
+	// Admin platform voucher routes
+	adminVouchers := app.Group("/v1/admin/vouchers", middleware.JWTProtected(jwtManager))
+	adminVouchers.Post("/", createPlatformVoucher)
+	adminVouchers.Post("/:voucherId/disable", disablePlatformVoucher)
+
+	app.Get("/v1/seller/analytics", middleware.JWTProtected(jwtManager), getSellerAnalytics)
+}
+
+// @Summary List seller's orders
+// @Description Get a paginated list of purchases containing the seller's products
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by status (pending_payment, proof_uploaded, confirmed, completed, cancelled, expired, refund_requested, refunded)"
+// @Success 200 {object} dtos.ListSellerOrdersResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/orders [get]
+func listSellerOrders(c *fiber.Ctx) error {
+	return proxyToPurchaseService(c, "GET", "/api/v1/seller/orders")
+}
+
+// @Summary Get seller's sales analytics
+// @Description Revenue per day, week or month, top products and categories, average order value and the conversion from created to confirmed purchases. Sales are the seller's items whose payment they confirmed, excluding refunded purchases, attributed to the date the purchase was created.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD), defaults to 30 days before to"
+// @Param to query string false "Created before (RFC3339 or YYYY-MM-DD, a date includes the whole day), defaults to now"
+// @Param interval query string false "Revenue interval (day, week, month)" default(day)
+// @Param currency query string false "Currency of the purchases to include" default(IDR)
+// @Param limit query int false "Number of top products and categories" default(5)
+// @Success 200 {object} dtos.SellerAnalyticsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/analytics [get]
+func getSellerAnalytics(c *fiber.Ctx) error {
+	return proxyToPurchaseService(c, "GET", "/api/v1/seller/analytics")
+}
+
+// @Summary Export seller's orders
+// @Description Download the seller's items across all purchases as CSV or XLSX, oldest first, with the state of the buyer's payment to the seller for reconciling transfers. Rows are streamed, so exports of any size are supported.
+// @Tags seller
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Security BearerAuth
+// @Param format query string false "File format (csv, xlsx)" default(csv)
+// @Param status query string false "Filter by status (pending_payment, proof_uploaded, confirmed, completed, cancelled, expired, refund_requested, refunded)"
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC3339 or YYYY-MM-DD, a date includes the whole day)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/orders/export [get]
+func exportSellerOrders(c *fiber.Ctx) error {
+	return streamFromPurchaseService(c, "/api/v1/seller/orders/export")
+}
+
+// @Summary Get seller's order
+// @Description Get the seller's items of a purchase together with the buyer's payment proof files
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purchaseId path string true "Purchase ID"
+// @Success 200 {object} dtos.SellerOrderResponse
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/orders/{purchaseId} [get]
+func getSellerOrder(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+	return proxyToPurchaseService(c, "GET", "/api/v1/seller/orders/"+purchaseID)
+}
+
+// @Summary Confirm payment
+// @Description Seller confirms the buyer's payment proof for their own items. Their reserved stock is committed and the buyer is notified. The purchase is confirmed once every seller has confirmed.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purchaseId path string true "Purchase ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/seller/orders/{purchaseId}/confirm [post]
+func confirmPayment(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+	return proxyToPurchaseService(c, "POST", "/api/v1/seller/orders/"+purchaseID+"/confirm")
+}
+
+// @Summary Reject payment
+// @Description Seller rejects the buyer's payment proof for their own items with a reason. That payment goes back to awaiting payment and the buyer is notified.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.RejectPaymentRequest true "Reject payment request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/orders/{purchaseId}/reject [post]
+func rejectPayment(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+	return proxyToPurchaseService(c, "POST", "/api/v1/seller/orders/"+purchaseID+"/reject")
+}
+
+// @Summary Ship order
+// @Description Seller marks their items of a confirmed purchase as shipped with the courier's tracking number. The buyer is notified.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.ShipOrderRequest true "Ship order request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/orders/{purchaseId}/ship [post]
+func shipOrder(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+	return proxyToPurchaseService(c, "POST", "/api/v1/seller/orders/"+purchaseID+"/ship")
+}
+
+// @Summary Approve refund
+// @Description Seller approves the buyer's refund request. The purchase is marked refunded and the buyer is notified.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purchaseId path string true "Purchase ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/orders/{purchaseId}/refund/approve [post]
+func approveRefund(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+	return proxyToPurchaseService(c, "POST", "/api/v1/seller/orders/"+purchaseID+"/refund/approve")
+}
+
+// @Summary Reject refund
+// @Description Seller rejects the buyer's refund request with a reason. The purchase goes back to confirmed and the buyer is notified.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.RejectRefundRequest true "Reject refund request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/orders/{purchaseId}/refund/reject [post]
+func rejectRefund(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+	return proxyToPurchaseService(c, "POST", "/api/v1/seller/orders/"+purchaseID+"/refund/reject")
+}
+
+// @Summary Create voucher
+// @Description Create a discount code for the seller's items, either a percentage off (optionally capped by maxDiscount) or a fixed amount off. Codes are case-insensitive and unique across all sellers.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dtos.CreateVoucherRequest true "Voucher"
+// @Success 201 {object} dtos.VoucherResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/vouchers [post]
+func createVoucher(c *fiber.Ctx) error {
+	return proxyToPurchaseService(c, "POST", "/api/v1/seller/vouchers")
+}
+
+// @Summary List seller's vouchers
+// @Description Get a paginated list of the seller's vouchers with how often each was used, newest first
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} dtos.ListVouchersResponse
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/vouchers [get]
+func listVouchers(c *fiber.Ctx) error {
+	return proxyToPurchaseService(c, "GET", "/api/v1/seller/vouchers")
+}
+
+// @Summary Disable voucher
+// @Description Stop a voucher from being redeemed. Purchases that already used it keep their discount.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param voucherId path string true "Voucher ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/vouchers/{voucherId}/disable [post]
+func disableVoucher(c *fiber.Ctx) error {
+	voucherID := c.Params("voucherId")
+	return proxyToPurchaseService(c, "POST", "/api/v1/seller/vouchers/"+voucherID+"/disable")
+}
+
+// @Summary Create platform voucher
+// @Description Admins create a discount code for whole purchases, either a percentage off (optionally capped by maxDiscount) or a fixed amount off, split across the sellers in proportion to their items. Codes are case-insensitive and unique across all vouchers.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body dtos.CreateVoucherRequest true "Voucher"
+// @Success 201 {object} dtos.VoucherResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/admin/vouchers [post]
+func createPlatformVoucher(c *fiber.Ctx) error {
+	return proxyToPurchaseService(c, "POST", "/api/v1/admin/vouchers")
+}
+
+// @Summary Disable platform voucher
+// @Description Admins stop a platform voucher from being redeemed. Purchases that already used it keep their discount; sellers' vouchers can only be disabled by their seller.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param voucherId path string true "Voucher ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/admin/vouchers/{voucherId}/disable [post]
+func disablePlatformVoucher(c *fiber.Ctx) error {
+	voucherID := c.Params("voucherId")
+	return proxyToPurchaseService(c, "POST", "/api/v1/admin/vouchers/"+voucherID+"/disable")
+}
@@ -0,0 +1,302 @@
+package handlers
+
+import (
+	"errors"
+	"product-service/pkg/dtos"
+	"product-service/pkg/entities"
+	"product-service/pkg/product"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ProductHandler struct {
+	service   product.Service
+	validator *validator.Validate
+}
+
+func NewProductHandler(service product.Service) *ProductHandler {
+	return &ProductHandler{
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+// CreateProduct handles POST /v1/product
+// @Summary Create a new product
+// @Description Seller can add a product to their catalogue
+// @Tags product
+// @Accept json
+// @Produce json
+// @Param request body dtos.ProductRequest true "Product request"
+// @Success 201 {object} presenter.ProductResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/product [post]
+func (h *ProductHandler) CreateProduct(c *fiber.Ctx) error {
+	var req dtos.ProductRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(validationErrorResponse(err))
+	}
+
+	product, err := h.service.CreateProduct(c.Context(), req)
+	if err != nil {
+		return h.handleError(c, err, "Failed to create product")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(product)
+}
+
+// UpdateProduct handles PUT /v1/product/:productId
+// @Summary Update a product
+// @Description Seller can update one of their products
+// @Tags product
+// @Accept json
+// @Produce json
+// @Param productId path string true "Product ID"
+// @Param request body dtos.ProductRequest true "Product request"
+// @Success 200 {object} presenter.ProductResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/product/{productId} [put]
+func (h *ProductHandler) UpdateProduct(c *fiber.Ctx) error {
+	productID := c.Params("productId")
+	if productID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Product ID is required",
+		})
+	}
+
+	var req dtos.ProductRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(validationErrorResponse(err))
+	}
+
+	product, err := h.service.UpdateProduct(c.Context(), productID, req)
+	if err != nil {
+		return h.handleError(c, err, "Failed to update product")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(product)
+}
+
+// DeleteProduct handles DELETE /v1/product/:productId
+// @Summary Delete a product
+// @Description Seller can delete one of their products, unless stock of it is still reserved for a pending purchase
+// @Tags product
+// @Produce json
+// @Param productId path string true "Product ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/product/{productId} [delete]
+func (h *ProductHandler) DeleteProduct(c *fiber.Ctx) error {
+	productID := c.Params("productId")
+	if productID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Product ID is required",
+		})
+	}
+
+	if err := h.service.DeleteProduct(c.Context(), productID); err != nil {
+		return h.handleError(c, err, "Failed to delete product")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Product deleted successfully",
+	})
+}
+
+// ListProducts handles GET /v1/product
+// @Summary List products
+// @Description Get a paginated, filterable list of products
+// @Tags product
+// @Produce json
+// @Param limit query int false "Items per page" default(5)
+// @Param offset query int false "Items to skip" default(0)
+// @Param productId query string false "Filter by product ID"
+// @Param sellerId query string false "Filter by seller ID"
+// @Param sku query string false "Filter by SKU"
+// @Param category query string false "Filter by category"
+// @Param sortBy query string false "newest, oldest, cheapest or expensive"
+// @Success 200 {object} presenter.ListProductsResponse
+// @Failure 500 {object} map[string]string
+// @Router /v1/product [get]
+func (h *ProductHandler) ListProducts(c *fiber.Ctx) error {
+	filter := dtos.ListProductsFilter{
+		Limit:     c.QueryInt("limit", 5),
+		Offset:    c.QueryInt("offset", 0),
+		ProductID: c.Query("productId"),
+		SellerID:  c.Query("sellerId"),
+		SKU:       c.Query("sku"),
+		Category:  c.Query("category"),
+		SortBy:    c.Query("sortBy"),
+	}
+
+	// Unknown categories match nothing rather than being ignored
+	if filter.Category != "" {
+		if err := h.validator.Var(filter.Category, "oneof=Food Beverage Clothes Furniture Tools"); err != nil {
+			filter.Category = "-"
+		}
+	}
+
+	products, err := h.service.ListProducts(c.Context(), filter)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to get products",
+		})
+	}
+
+	return c.Status(fiber.StatusOK).JSON(products)
+}
+
+// GetProductByID handles GET /product/:productId (internal)
+func (h *ProductHandler) GetProductByID(c *fiber.Ctx) error {
+	product, err := h.service.GetProductByID(c.Context(), c.Params("productId"))
+	if err != nil {
+		return h.handleError(c, err, "Failed to get product")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(product)
+}
+
+// DecreaseQuantity handles POST /product/:productId/decrease-quantity (internal)
+func (h *ProductHandler) DecreaseQuantity(c *fiber.Ctx) error {
+	var req dtos.DecreaseQuantityRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(validationErrorResponse(err))
+	}
+
+	if err := h.service.DecreaseQuantity(c.Context(), c.Params("productId"), req.Quantity); err != nil {
+		return h.handleError(c, err, "Failed to decrease product quantity")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Product quantity decreased successfully",
+	})
+}
+
+// ReserveQuantity handles POST /product/:productId/reserve (internal)
+func (h *ProductHandler) ReserveQuantity(c *fiber.Ctx) error {
+	var req dtos.ReserveQuantityRequest
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if err := h.validator.Struct(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(validationErrorResponse(err))
+	}
+
+	if err := h.service.ReserveQuantity(c.Context(), c.Params("productId"), req.PurchaseID, req.Quantity); err != nil {
+		return h.handleError(c, err, "Failed to reserve product quantity")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Product quantity reserved successfully",
+	})
+}
+
+// CommitReservation handles POST /product/:productId/reservations/:purchaseId/commit (internal)
+func (h *ProductHandler) CommitReservation(c *fiber.Ctx) error {
+	if err := h.service.CommitReservation(c.Context(), c.Params("productId"), c.Params("purchaseId")); err != nil {
+		return h.handleError(c, err, "Failed to commit reservation")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Reservation committed successfully",
+	})
+}
+
+// ReleaseReservation handles POST /product/:productId/reservations/:purchaseId/release (internal)
+func (h *ProductHandler) ReleaseReservation(c *fiber.Ctx) error {
+	if err := h.service.ReleaseReservation(c.Context(), c.Params("productId"), c.Params("purchaseId")); err != nil {
+		return h.handleError(c, err, "Failed to release reservation")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Reservation released successfully",
+	})
+}
+
+// handleError maps domain errors to HTTP status codes
+func (h *ProductHandler) handleError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, entities.ErrProductNotFound), errors.Is(err, entities.ErrReservationNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, entities.ErrProductForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, entities.ErrSKUAlreadyExists), errors.Is(err, entities.ErrInsufficientStock),
+		errors.Is(err, entities.ErrProductReserved), errors.Is(err, entities.ErrReservationReleased), errors.Is(err, entities.ErrReservationCommitted):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, entities.ErrFileNotFound), errors.Is(err, entities.ErrInvalidSellerID), errors.Is(err, entities.ErrPriceTooLow):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fallback})
+	}
+}
+
+// validationErrorResponse builds the standard validation failure body
+func validationErrorResponse(err error) fiber.Map {
+	validationErrors := make(map[string]string)
+	if errs, ok := err.(validator.ValidationErrors); ok {
+		for _, e := range errs {
+			validationErrors[e.Field()] = getValidationMessage(e)
+		}
+	}
+	return fiber.Map{
+		"error":   "Validation failed",
+		"details": validationErrors,
+	}
+}
+
+// getValidationMessage returns a user-friendly validation message
+func getValidationMessage(err validator.FieldError) string {
+	switch err.Tag() {
+	case "required":
+		return "This field is required"
+	case "min":
+		return "Value is too short"
+	case "max":
+		return "Value is too long"
+	case "uuid":
+		return "Invalid ID format"
+	case "oneof":
+		return "Invalid value, must be one of: " + err.Param()
+	default:
+		return "Invalid value"
+	}
+}
@@ -0,0 +1,54 @@
+package presenter
+
+import (
+	"product-service/pkg/entities"
+	"product-service/pkg/money"
+	"time"
+)
+
+// Product Response DTOs
+type ProductResponse struct {
+	ID               string       `json:"productId"`
+	Name             string       `json:"name"`
+	Category         string       `json:"category"`
+	Qty              int          `json:"qty"`
+	Price            money.Amount `json:"price"`
+	Currency         string       `json:"currency"`
+	SKU              string       `json:"sku"`
+	FileID           string       `json:"fileId"`
+	FileURI          string       `json:"fileUri"`
+	FileThumbnailURI string       `json:"fileThumbnailUri"`
+	SellerID         string       `json:"sellerId"`
+	CreatedAt        string       `json:"createdAt"`
+	UpdatedAt        string       `json:"updatedAt"`
+}
+
+type ListProductsResponse struct {
+	Products []ProductResponse `json:"products"`
+	Limit    int               `json:"limit"`
+	Offset   int               `json:"offset"`
+}
+
+// NewProductResponse converts a product entity into its API representation
+func NewProductResponse(p *entities.Product) ProductResponse {
+	resp := ProductResponse{
+		ID:        p.ID.String(),
+		Name:      p.Name,
+		Category:  p.Category,
+		Qty:       p.Qty,
+		Price:     p.Price,
+		Currency:  p.Currency,
+		SKU:       p.SKU,
+		SellerID:  p.SellerID.String(),
+		CreatedAt: p.CreatedAt.Format(time.RFC3339),
+		UpdatedAt: p.UpdatedAt.Format(time.RFC3339),
+	}
+	if p.FileID != nil {
+		resp.FileID = p.FileID.String()
+	}
+	if p.File != nil {
+		resp.FileURI = p.File.FileUri
+		resp.FileThumbnailURI = p.File.FileThumbnailUri
+	}
+	return resp
+}
@@ -0,0 +1,37 @@
+package routes
+
+import (
+	"product-service/api/handlers"
+	"product-service/api/middleware"
+	"product-service/config"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// ProductRouter sets up the public, gateway-facing product routes
+func ProductRouter(api fiber.Router, v *viper.Viper, services config.Services) {
+	productHandler := handlers.NewProductHandler(services.ProductService)
+
+	product := api.Group("/product")
+	{
+		product.Get("/", productHandler.ListProducts)
+		product.Post("/", middleware.GatewayTrust(v), productHandler.CreateProduct)
+		product.Put("/:productId", middleware.GatewayTrust(v), productHandler.UpdateProduct)
+		product.Delete("/:productId", middleware.GatewayTrust(v), productHandler.DeleteProduct)
+	}
+}
+
+// InternalProductRouter sets up the routes other services call directly
+func InternalProductRouter(app *fiber.App, v *viper.Viper, services config.Services) {
+	productHandler := handlers.NewProductHandler(services.ProductService)
+
+	internal := app.Group("/product", middleware.GatewayTrust(v))
+	{
+		internal.Get("/:productId", productHandler.GetProductByID)
+		internal.Post("/:productId/decrease-quantity", productHandler.DecreaseQuantity)
+		internal.Post("/:productId/reserve", productHandler.ReserveQuantity)
+		internal.Post("/:productId/reservations/:purchaseId/commit", productHandler.CommitReservation)
+		internal.Post("/:productId/reservations/:purchaseId/release", productHandler.ReleaseReservation)
+	}
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"product-service/api/routes"
+	"product-service/config"
+)
+
+// @title           Product Service API
+// @version         1.0
+// @description     Product service for TutupLapak application.
+
+// @securityDefinitions.apikey BearerAuth
+// @in header
+// @name Authorization
+
+func main() {
+	v := config.NewViper()
+	app := config.NewFiber(v)
+	db := config.NewGorm(v)
+
+	services := config.InitServices(db)
+	routes.SetupRoutes(app, v, db, services)
+
+	// Release stock reserved for purchases that were never written
+	if sweeper := config.NewReservationSweeper(v, services); sweeper != nil {
+		go sweeper.Run(context.Background())
+	}
+
+	// Run server
+	port := v.GetString("SERVER_PORT")
+	if port == "" {
+		port = "3003"
+	}
+	log.Printf("product-service listening on :%s", port)
+	log.Fatal(app.Listen(":" + port))
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"os"
+	"product-service/pkg/product"
+
+	"gorm.io/gorm"
+)
+
+// Services struct holds all service dependencies
+type Services struct {
+	ProductService product.Service
+}
+
+// InitServices initializes all application services
+func InitServices(db *gorm.DB) Services {
+	// Initialize repositories
+	productRepo := product.NewGormRepository(db)
+
+	// Get service URLs from environment variables
+	purchaseServiceURL := os.Getenv("PURCHASE_SERVICE_URL")
+	if purchaseServiceURL == "" {
+		purchaseServiceURL = "http://localhost:3004" // Default purchase service URL
+	}
+
+	internalSecret := os.Getenv("INTERNAL_SECRET")
+	if internalSecret == "" {
+		internalSecret = "backend-infra-internal-secret" // Default internal secret
+	}
+
+	// Initialize services
+	productService := product.NewService(productRepo, purchaseServiceURL, internalSecret)
+
+	return Services{
+		ProductService: productService,
+	}
+}
@@ -0,0 +1,34 @@
+package config
+
+import (
+	"product-service/pkg/product"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// NewReservationSweeper builds the background worker that releases stock
+// reserved for purchases that were never written. It returns nil when
+// RESERVATION_SWEEP_INTERVAL is set to a non-positive value.
+func NewReservationSweeper(config *viper.Viper, services Services) *product.ReservationSweeper {
+	config.SetDefault("RESERVATION_SWEEP_INTERVAL", "5m")
+	config.SetDefault("RESERVATION_STALE_AFTER", "15m")
+	config.SetDefault("RESERVATION_SWEEP_BATCH_SIZE", 100)
+
+	interval := config.GetDuration("RESERVATION_SWEEP_INTERVAL")
+	if interval <= 0 {
+		return nil
+	}
+
+	staleAfter := config.GetDuration("RESERVATION_STALE_AFTER")
+	if staleAfter <= 0 {
+		staleAfter = 15 * time.Minute
+	}
+
+	batchSize := config.GetInt("RESERVATION_SWEEP_BATCH_SIZE")
+	if batchSize <= 0 || batchSize > 100 {
+		batchSize = 100
+	}
+
+	return product.NewReservationSweeper(services.ProductService, interval, staleAfter, batchSize)
+}
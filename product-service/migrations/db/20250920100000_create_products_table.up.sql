@@ -0,0 +1,26 @@
+-- Create products table
+CREATE TABLE IF NOT EXISTS products (
+    id UUID PRIMARY KEY,
+    seller_id UUID NOT NULL,
+    name VARCHAR(32) NOT NULL,
+    category VARCHAR(32) NOT NULL,
+    qty INTEGER NOT NULL CHECK (qty >= 0),
+    price DECIMAL(10,2) NOT NULL,
+    sku VARCHAR(32) NOT NULL,
+    file_id UUID,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Create indexes for better performance
+CREATE INDEX IF NOT EXISTS idx_products_seller_id ON products(seller_id);
+CREATE INDEX IF NOT EXISTS idx_products_category ON products(category);
+CREATE INDEX IF NOT EXISTS idx_products_updated_at ON products(updated_at);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_products_seller_sku ON products(seller_id, sku);
+
+-- Add comments for documentation
+COMMENT ON TABLE products IS 'Stores products listed by sellers';
+COMMENT ON COLUMN products.seller_id IS 'ID of the user selling the product';
+COMMENT ON COLUMN products.qty IS 'Available stock';
+COMMENT ON COLUMN products.price IS 'Price per unit of the product';
+COMMENT ON COLUMN products.file_id IS 'ID of the product image in the files table';
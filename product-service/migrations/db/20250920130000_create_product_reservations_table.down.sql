@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_product_reservations_purchase_id;
+DROP INDEX IF EXISTS idx_product_reservations_product_purchase;
+
+DROP TABLE IF EXISTS product_reservations;
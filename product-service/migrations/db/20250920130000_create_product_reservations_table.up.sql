@@ -0,0 +1,17 @@
+-- Create product_reservations table
+CREATE TABLE IF NOT EXISTS product_reservations (
+    id UUID PRIMARY KEY,
+    product_id UUID NOT NULL REFERENCES products(id) ON DELETE CASCADE,
+    purchase_id VARCHAR(255) NOT NULL,
+    qty INTEGER NOT NULL CHECK (qty > 0),
+    status VARCHAR(32) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- One reservation per product per purchase keeps reserve calls idempotent
+CREATE UNIQUE INDEX IF NOT EXISTS idx_product_reservations_product_purchase ON product_reservations(product_id, purchase_id);
+CREATE INDEX IF NOT EXISTS idx_product_reservations_purchase_id ON product_reservations(purchase_id);
+
+COMMENT ON TABLE product_reservations IS 'Stock held for purchases until they are paid (committed) or cancelled (released)';
+COMMENT ON COLUMN product_reservations.status IS 'reserved, committed or released';
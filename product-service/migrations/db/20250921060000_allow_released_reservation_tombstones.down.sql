@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_product_reservations_status_created_at;
+
+DELETE FROM product_reservations WHERE qty = 0;
+ALTER TABLE product_reservations DROP CONSTRAINT IF EXISTS product_reservations_qty_check;
+ALTER TABLE product_reservations ADD CONSTRAINT product_reservations_qty_check CHECK (qty > 0);
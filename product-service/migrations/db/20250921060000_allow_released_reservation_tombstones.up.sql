@@ -0,0 +1,7 @@
+-- A release that arrives before its reserve records a released row with no
+-- stock, so the late reserve is refused instead of holding stock forever
+ALTER TABLE product_reservations DROP CONSTRAINT IF EXISTS product_reservations_qty_check;
+ALTER TABLE product_reservations ADD CONSTRAINT product_reservations_qty_check CHECK (qty > 0 OR status = 'released');
+
+-- Supports the sweep of reservations whose purchase was never written
+CREATE INDEX IF NOT EXISTS idx_product_reservations_status_created_at ON product_reservations(status, created_at);
@@ -0,0 +1,2 @@
+COMMENT ON COLUMN products.price IS 'Price per unit of the product';
+ALTER TABLE products DROP COLUMN IF EXISTS currency;
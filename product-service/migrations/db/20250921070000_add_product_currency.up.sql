@@ -0,0 +1,7 @@
+-- Prices are exact decimals in the product currency; existing products were
+-- all listed in rupiah
+ALTER TABLE products
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'IDR';
+
+COMMENT ON COLUMN products.currency IS 'ISO 4217 currency code of the price';
+COMMENT ON COLUMN products.price IS 'Price per unit of the product, in the product currency';
@@ -0,0 +1,34 @@
+package dtos
+
+import "product-service/pkg/money"
+
+// API Request DTOs
+type ProductRequest struct {
+	Name     string       `json:"name" validate:"required,min=4,max=32"`
+	Category string       `json:"category" validate:"required,oneof=Food Beverage Clothes Furniture Tools"`
+	Qty      int          `json:"qty" validate:"min=0"`
+	Price    money.Amount `json:"price" validate:"required"`                     // At least 100, checked by the service
+	Currency string       `json:"currency" validate:"omitempty,len=3,uppercase"` // Defaults to IDR
+	SKU      string       `json:"sku" validate:"required,max=32"`
+	FileID   string       `json:"fileId" validate:"required,uuid"`
+}
+
+type DecreaseQuantityRequest struct {
+	Quantity int `json:"quantity" validate:"required,min=1"`
+}
+
+type ReserveQuantityRequest struct {
+	PurchaseID string `json:"purchaseId" validate:"required"`
+	Quantity   int    `json:"quantity" validate:"required,min=1"`
+}
+
+// ListProductsFilter holds the query parameters accepted by GET /product
+type ListProductsFilter struct {
+	Limit     int
+	Offset    int
+	ProductID string
+	SellerID  string
+	SKU       string
+	Category  string
+	SortBy    string
+}
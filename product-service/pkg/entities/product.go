@@ -0,0 +1,101 @@
+package entities
+
+import (
+	"errors"
+	"product-service/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Product categories accepted by the product service
+const (
+	CategoryFood      = "Food"
+	CategoryBeverage  = "Beverage"
+	CategoryClothes   = "Clothes"
+	CategoryFurniture = "Furniture"
+	CategoryTools     = "Tools"
+)
+
+// File mirrors the files table owned by profile-service (shared database)
+type File struct {
+	ID               uuid.UUID `gorm:"type:uuid;primaryKey;column:id"`
+	FileUri          string    `gorm:"type:varchar(255);column:fileUri"`
+	FileThumbnailUri string    `gorm:"type:varchar(255);column:fileThumbnailUri"`
+}
+
+// Product represents a product listed by a seller
+type Product struct {
+	ID        uuid.UUID    `gorm:"type:uuid;primaryKey"`
+	SellerID  uuid.UUID    `gorm:"type:uuid;not null"`
+	Name      string       `gorm:"type:varchar(32);not null"`
+	Category  string       `gorm:"type:varchar(32);not null"`
+	Qty       int          `gorm:"not null"`
+	Price     money.Amount `gorm:"type:decimal(10,2);not null"`
+	Currency  string       `gorm:"type:varchar(3);not null;default:IDR"`
+	SKU       string       `gorm:"type:varchar(32);not null"`
+	FileID    *uuid.UUID   `gorm:"type:uuid"`
+	File      *File        `gorm:"foreignKey:FileID;references:ID"`
+	CreatedAt time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time    `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// Reservation statuses
+const (
+	ReservationStatusReserved  = "reserved"
+	ReservationStatusCommitted = "committed"
+	ReservationStatusReleased  = "released"
+)
+
+// ProductReservation holds stock for a purchase until it is paid or released
+type ProductReservation struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ProductID  uuid.UUID `gorm:"type:uuid;not null"`
+	PurchaseID string    `gorm:"type:varchar(255);not null"`
+	Qty        int       `gorm:"not null"`
+	Status     string    `gorm:"type:varchar(32);not null"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+var (
+	ErrProductNotFound   = errors.New("product not found")
+	ErrProductForbidden  = errors.New("product does not belong to seller")
+	ErrSKUAlreadyExists  = errors.New("sku already exists for this seller")
+	ErrFileNotFound      = errors.New("fileId not found")
+	ErrInsufficientStock = errors.New("insufficient product quantity")
+	ErrInvalidSellerID   = errors.New("sellerId is not valid")
+	ErrPriceTooLow       = errors.New("price must be at least 100")
+	ErrProductReserved   = errors.New("product has stock reserved for pending purchases")
+
+	ErrReservationNotFound  = errors.New("reservation not found")
+	ErrReservationReleased  = errors.New("reservation already released")
+	ErrReservationCommitted = errors.New("reservation already committed")
+)
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (p *Product) BeforeCreate(tx *gorm.DB) (err error) {
+	if p.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		p.ID = id
+	}
+	return nil
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (r *ProductReservation) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		r.ID = id
+	}
+	return nil
+}
+
+func (File) TableName() string { return "files" }
@@ -0,0 +1,93 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// serviceUserID identifies the product service itself on calls that are not
+// made on behalf of a user
+const serviceUserID = "product-service"
+
+// StatusError is returned when a service answers with an unexpected status code
+type StatusError struct {
+	Service    string
+	StatusCode int
+	Body       string
+}
+
+func newStatusError(service string, resp *http.Response) *StatusError {
+	body, _ := io.ReadAll(resp.Body)
+	return &StatusError{Service: service, StatusCode: resp.StatusCode, Body: string(body)}
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned status %d: %s", e.Service, e.StatusCode, e.Body)
+}
+
+type Client struct {
+	baseURL        string
+	httpClient     *http.Client
+	internalSecret string
+}
+
+func NewClient(baseURL, internalSecret string) *Client {
+	return &Client{
+		baseURL:        baseURL,
+		internalSecret: internalSecret,
+		httpClient: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// setInternalHeaders sets the required headers for internal service communication
+func (c *Client) setInternalHeaders(req *http.Request) {
+	req.Header.Set("X-Secret", c.internalSecret)
+	req.Header.Set("X-Auth-Gateway", "backend-infra")
+	req.Header.Set("X-User-ID", serviceUserID)
+}
+
+// GetExistingPurchaseIDs asks purchase service which of the given purchases exist
+func (c *Client) GetExistingPurchaseIDs(ctx context.Context, purchaseIDs []string) ([]string, error) {
+	url := fmt.Sprintf("%s/purchase/batch", c.baseURL)
+
+	jsonBody, err := json.Marshal(map[string]interface{}{
+		"purchaseIds": purchaseIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	c.setInternalHeaders(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError("purchase service", resp)
+	}
+
+	var body struct {
+		PurchaseIDs []string `json:"purchaseIds"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return body.PurchaseIDs, nil
+}
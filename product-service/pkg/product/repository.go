@@ -0,0 +1,344 @@
+package product
+
+import (
+	"context"
+	"errors"
+	"product-service/pkg/dtos"
+	"product-service/pkg/entities"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	CreateProduct(ctx context.Context, product *entities.Product) error
+	// UpdateProduct saves the seller's edits of a product. Stock is changed by
+	// qtyDelta rather than overwritten, so stock reserved or released since the
+	// product was read is kept; product.Qty is ignored.
+	UpdateProduct(ctx context.Context, product *entities.Product, qtyDelta int) error
+	// DeleteProduct deletes a product unless stock of it is still reserved for
+	// a purchase, since its reservations are deleted along with it
+	DeleteProduct(ctx context.Context, id string) error
+	GetProductByID(ctx context.Context, id string) (*entities.Product, error)
+	ListProducts(ctx context.Context, filter dtos.ListProductsFilter) ([]*entities.Product, error)
+	IsSKUTaken(ctx context.Context, sellerID, sku, excludeProductID string) (bool, error)
+	IsFileExist(ctx context.Context, id string) (bool, error)
+	DecreaseQuantity(ctx context.Context, id string, quantity int) error
+	ReserveQuantity(ctx context.Context, id, purchaseID string, quantity int) error
+	CommitReservation(ctx context.Context, id, purchaseID string) error
+	ReleaseReservation(ctx context.Context, id, purchaseID string) error
+	// GetReservationsCreatedBefore returns up to limit reservations in the given
+	// status created before the cutoff, in ID order starting after afterID
+	GetReservationsCreatedBefore(ctx context.Context, status string, before time.Time, afterID uuid.UUID, limit int) ([]*entities.ProductReservation, error)
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) CreateProduct(ctx context.Context, product *entities.Product) error {
+	if err := r.db.WithContext(ctx).Create(product).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Preload("File").First(product, "id = ?", product.ID).Error
+}
+
+func (r *GormRepository) UpdateProduct(ctx context.Context, product *entities.Product, qtyDelta int) error {
+	if err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Locking the product holds off reservations and releases, which change its qty
+		var current entities.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", product.ID).
+			First(&current).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return entities.ErrProductNotFound
+			}
+			return err
+		}
+		if current.Qty+qtyDelta < 0 {
+			return entities.ErrInsufficientStock
+		}
+
+		return tx.Model(&entities.Product{}).
+			Where("id = ?", product.ID).
+			Updates(map[string]interface{}{
+				"name":     product.Name,
+				"category": product.Category,
+				"qty":      current.Qty + qtyDelta,
+				"price":    product.Price,
+				"currency": product.Currency,
+				"sku":      product.SKU,
+				"file_id":  product.FileID,
+			}).Error
+	}); err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Preload("File").First(product, "id = ?", product.ID).Error
+}
+
+func (r *GormRepository) DeleteProduct(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Locking the product holds off new reservations, which decrease its qty
+		var product entities.Product
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			First(&product).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return entities.ErrProductNotFound
+			}
+			return err
+		}
+
+		var reserved int64
+		if err := tx.Model(&entities.ProductReservation{}).
+			Where("product_id = ? AND status = ?", id, entities.ReservationStatusReserved).
+			Count(&reserved).Error; err != nil {
+			return err
+		}
+		if reserved > 0 {
+			return entities.ErrProductReserved
+		}
+
+		return tx.Where("id = ?", id).Delete(&entities.Product{}).Error
+	})
+}
+
+func (r *GormRepository) GetProductByID(ctx context.Context, id string) (*entities.Product, error) {
+	var product entities.Product
+	if err := r.db.WithContext(ctx).Preload("File").Where("id = ?", id).First(&product).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, entities.ErrProductNotFound
+		}
+		return nil, err
+	}
+	return &product, nil
+}
+
+func (r *GormRepository) ListProducts(ctx context.Context, filter dtos.ListProductsFilter) ([]*entities.Product, error) {
+	var products []*entities.Product
+
+	query := r.db.WithContext(ctx).Preload("File")
+	if filter.ProductID != "" {
+		query = query.Where("id = ?", filter.ProductID)
+	}
+	if filter.SellerID != "" {
+		query = query.Where("seller_id = ?", filter.SellerID)
+	}
+	if filter.SKU != "" {
+		query = query.Where("sku = ?", filter.SKU)
+	}
+	if filter.Category != "" {
+		query = query.Where("category = ?", filter.Category)
+	}
+
+	switch filter.SortBy {
+	case "oldest":
+		query = query.Order("updated_at ASC")
+	case "cheapest":
+		query = query.Order("price ASC")
+	case "expensive":
+		query = query.Order("price DESC")
+	default:
+		query = query.Order("updated_at DESC")
+	}
+
+	if err := query.Offset(filter.Offset).Limit(filter.Limit).Find(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *GormRepository) IsSKUTaken(ctx context.Context, sellerID, sku, excludeProductID string) (bool, error) {
+	var count int64
+	query := r.db.WithContext(ctx).Model(&entities.Product{}).
+		Where("seller_id = ? AND sku = ?", sellerID, sku)
+	if excludeProductID != "" {
+		query = query.Where("id <> ?", excludeProductID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (r *GormRepository) IsFileExist(ctx context.Context, id string) (bool, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.File{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// DecreaseQuantity subtracts stock in a single conditional UPDATE so concurrent
+// purchases can never drive the quantity below zero
+func (r *GormRepository) DecreaseQuantity(ctx context.Context, id string, quantity int) error {
+	result := r.db.WithContext(ctx).Model(&entities.Product{}).
+		Where("id = ? AND qty >= ?", id, quantity).
+		Update("qty", gorm.Expr("qty - ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		if _, err := r.GetProductByID(ctx, id); err != nil {
+			return err
+		}
+		return entities.ErrInsufficientStock
+	}
+	return nil
+}
+
+// errReservationRaced means a concurrent call wrote the reservation row of the
+// same product and purchase first; the call is retried against that row
+var errReservationRaced = errors.New("reservation written concurrently")
+
+// ReserveQuantity takes stock out of the product and records it against the
+// purchase. Reserving twice for the same purchase is a no-op, and reserving
+// after the purchase was released fails with ErrReservationReleased.
+func (r *GormRepository) ReserveQuantity(ctx context.Context, id, purchaseID string, quantity int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing entities.ProductReservation
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ? AND purchase_id = ?", id, purchaseID).
+			First(&existing).Error
+		if err == nil {
+			if existing.Status == entities.ReservationStatusReleased {
+				return entities.ErrReservationReleased
+			}
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		if err := (&GormRepository{db: tx}).DecreaseQuantity(ctx, id, quantity); err != nil {
+			return err
+		}
+
+		reservation := &entities.ProductReservation{
+			ProductID:  uuid.MustParse(id),
+			PurchaseID: purchaseID,
+			Qty:        quantity,
+			Status:     entities.ReservationStatusReserved,
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(reservation)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errReservationRaced
+		}
+		return nil
+	})
+	if errors.Is(err, errReservationRaced) {
+		return r.ReserveQuantity(ctx, id, purchaseID, quantity)
+	}
+	return err
+}
+
+// CommitReservation marks reserved stock as sold
+func (r *GormRepository) CommitReservation(ctx context.Context, id, purchaseID string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var reservation entities.ProductReservation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ? AND purchase_id = ?", id, purchaseID).
+			First(&reservation).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return entities.ErrReservationNotFound
+			}
+			return err
+		}
+
+		switch reservation.Status {
+		case entities.ReservationStatusCommitted:
+			return nil
+		case entities.ReservationStatusReleased:
+			return entities.ErrReservationReleased
+		}
+
+		return tx.Model(&reservation).Update("status", entities.ReservationStatusCommitted).Error
+	})
+}
+
+// ReleaseReservation returns reserved stock to the product. Releasing a
+// reservation that was already released is a no-op. Releasing one that does
+// not exist yet records it as released, so a reserve for the same purchase
+// still in flight is refused instead of holding stock nobody gives back.
+func (r *GormRepository) ReleaseReservation(ctx context.Context, id, purchaseID string) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var reservation entities.ProductReservation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("product_id = ? AND purchase_id = ?", id, purchaseID).
+			First(&reservation).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return releaseMissingReservation(tx, id, purchaseID)
+			}
+			return err
+		}
+
+		switch reservation.Status {
+		case entities.ReservationStatusReleased:
+			return nil
+		case entities.ReservationStatusCommitted:
+			return entities.ErrReservationCommitted
+		}
+
+		if err := tx.Model(&entities.Product{}).
+			Where("id = ?", id).
+			Update("qty", gorm.Expr("qty + ?", reservation.Qty)).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&reservation).Update("status", entities.ReservationStatusReleased).Error
+	})
+	if errors.Is(err, errReservationRaced) {
+		return r.ReleaseReservation(ctx, id, purchaseID)
+	}
+	return err
+}
+
+// releaseMissingReservation writes a released row holding no stock for a
+// purchase that has not reserved the product. Products that do not exist have
+// nothing to reserve, so they are left alone.
+func releaseMissingReservation(tx *gorm.DB, id, purchaseID string) error {
+	var count int64
+	if err := tx.Model(&entities.Product{}).Where("id = ?", id).Count(&count).Error; err != nil {
+		return err
+	}
+	if count == 0 {
+		return nil
+	}
+
+	tombstone := &entities.ProductReservation{
+		ProductID:  uuid.MustParse(id),
+		PurchaseID: purchaseID,
+		Status:     entities.ReservationStatusReleased,
+	}
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(tombstone)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return errReservationRaced
+	}
+	return nil
+}
+
+// GetReservationsCreatedBefore pages by ID; IDs are UUID v7, so this is also
+// creation order
+func (r *GormRepository) GetReservationsCreatedBefore(ctx context.Context, status string, before time.Time, afterID uuid.UUID, limit int) ([]*entities.ProductReservation, error) {
+	var reservations []*entities.ProductReservation
+	if err := r.db.WithContext(ctx).
+		Where("status = ? AND created_at < ? AND id > ?", status, before, afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&reservations).Error; err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
@@ -0,0 +1,270 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"product-service/api/presenter"
+	"product-service/pkg/dtos"
+	"product-service/pkg/entities"
+	"product-service/pkg/http"
+	"product-service/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Service interface {
+	CreateProduct(ctx context.Context, req dtos.ProductRequest) (*presenter.ProductResponse, error)
+	UpdateProduct(ctx context.Context, productID string, req dtos.ProductRequest) (*presenter.ProductResponse, error)
+	DeleteProduct(ctx context.Context, productID string) error
+	GetProductByID(ctx context.Context, productID string) (*presenter.ProductResponse, error)
+	ListProducts(ctx context.Context, filter dtos.ListProductsFilter) (*presenter.ListProductsResponse, error)
+	DecreaseQuantity(ctx context.Context, productID string, quantity int) error
+	ReserveQuantity(ctx context.Context, productID, purchaseID string, quantity int) error
+	CommitReservation(ctx context.Context, productID, purchaseID string) error
+	ReleaseReservation(ctx context.Context, productID, purchaseID string) error
+	ReleaseOrphanReservations(ctx context.Context, staleAfter time.Duration, batchSize int) (int, error)
+}
+
+type service struct {
+	repo           Repository
+	purchaseClient *http.Client
+}
+
+func NewService(repo Repository, purchaseServiceURL, internalSecret string) Service {
+	return &service{
+		repo:           repo,
+		purchaseClient: http.NewClient(purchaseServiceURL, internalSecret),
+	}
+}
+
+func (s *service) CreateProduct(ctx context.Context, req dtos.ProductRequest) (*presenter.ProductResponse, error) {
+	// Get authenticated user ID from context (set by gateway trust middleware)
+	sellerID, err := sellerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	fileID, err := s.validateProductRequest(ctx, sellerID.String(), "", req)
+	if err != nil {
+		return nil, err
+	}
+
+	product := &entities.Product{
+		SellerID: sellerID,
+		Name:     req.Name,
+		Category: req.Category,
+		Qty:      req.Qty,
+		Price:    req.Price,
+		Currency: productCurrency(req),
+		SKU:      req.SKU,
+		FileID:   &fileID,
+	}
+	if err := s.repo.CreateProduct(ctx, product); err != nil {
+		return nil, fmt.Errorf("failed to create product: %w", err)
+	}
+
+	resp := presenter.NewProductResponse(product)
+	return &resp, nil
+}
+
+func (s *service) UpdateProduct(ctx context.Context, productID string, req dtos.ProductRequest) (*presenter.ProductResponse, error) {
+	sellerID, err := sellerFromContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err := s.getOwnedProduct(ctx, productID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	fileID, err := s.validateProductRequest(ctx, sellerID.String(), productID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// The seller edited the stock they saw; reservations made since then still hold
+	qtyDelta := req.Qty - product.Qty
+
+	product.Name = req.Name
+	product.Category = req.Category
+	product.Price = req.Price
+	product.Currency = productCurrency(req)
+	product.SKU = req.SKU
+	product.FileID = &fileID
+
+	if err := s.repo.UpdateProduct(ctx, product, qtyDelta); err != nil {
+		return nil, fmt.Errorf("failed to update product: %w", err)
+	}
+
+	resp := presenter.NewProductResponse(product)
+	return &resp, nil
+}
+
+func (s *service) DeleteProduct(ctx context.Context, productID string) error {
+	sellerID, err := sellerFromContext(ctx)
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.getOwnedProduct(ctx, productID, sellerID); err != nil {
+		return err
+	}
+
+	if err := s.repo.DeleteProduct(ctx, productID); err != nil {
+		return fmt.Errorf("failed to delete product: %w", err)
+	}
+	return nil
+}
+
+func (s *service) GetProductByID(ctx context.Context, productID string) (*presenter.ProductResponse, error) {
+	if _, err := uuid.Parse(productID); err != nil {
+		return nil, entities.ErrProductNotFound
+	}
+
+	product, err := s.repo.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := presenter.NewProductResponse(product)
+	return &resp, nil
+}
+
+func (s *service) ListProducts(ctx context.Context, filter dtos.ListProductsFilter) (*presenter.ListProductsResponse, error) {
+	// Set default pagination values
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 5
+	}
+	if filter.Offset < 0 {
+		filter.Offset = 0
+	}
+
+	// Invalid identifiers simply match nothing instead of failing the query
+	if filter.ProductID != "" {
+		if _, err := uuid.Parse(filter.ProductID); err != nil {
+			return &presenter.ListProductsResponse{Products: []presenter.ProductResponse{}, Limit: filter.Limit, Offset: filter.Offset}, nil
+		}
+	}
+	if filter.SellerID != "" {
+		if _, err := uuid.Parse(filter.SellerID); err != nil {
+			return &presenter.ListProductsResponse{Products: []presenter.ProductResponse{}, Limit: filter.Limit, Offset: filter.Offset}, nil
+		}
+	}
+
+	products, err := s.repo.ListProducts(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list products: %w", err)
+	}
+
+	productResponses := make([]presenter.ProductResponse, 0, len(products))
+	for _, product := range products {
+		productResponses = append(productResponses, presenter.NewProductResponse(product))
+	}
+
+	return &presenter.ListProductsResponse{
+		Products: productResponses,
+		Limit:    filter.Limit,
+		Offset:   filter.Offset,
+	}, nil
+}
+
+func (s *service) DecreaseQuantity(ctx context.Context, productID string, quantity int) error {
+	if _, err := uuid.Parse(productID); err != nil {
+		return entities.ErrProductNotFound
+	}
+	return s.repo.DecreaseQuantity(ctx, productID, quantity)
+}
+
+func (s *service) ReserveQuantity(ctx context.Context, productID, purchaseID string, quantity int) error {
+	if _, err := uuid.Parse(productID); err != nil {
+		return entities.ErrProductNotFound
+	}
+	return s.repo.ReserveQuantity(ctx, productID, purchaseID, quantity)
+}
+
+func (s *service) CommitReservation(ctx context.Context, productID, purchaseID string) error {
+	if _, err := uuid.Parse(productID); err != nil {
+		return entities.ErrReservationNotFound
+	}
+	return s.repo.CommitReservation(ctx, productID, purchaseID)
+}
+
+func (s *service) ReleaseReservation(ctx context.Context, productID, purchaseID string) error {
+	if _, err := uuid.Parse(productID); err != nil {
+		return nil
+	}
+	return s.repo.ReleaseReservation(ctx, productID, purchaseID)
+}
+
+// minPrice is the lowest price a product can be listed at, 100.00
+const minPrice = money.Amount(10000)
+
+// validateProductRequest checks the minimum price, file existence and
+// per-seller SKU uniqueness
+func (s *service) validateProductRequest(ctx context.Context, sellerID, productID string, req dtos.ProductRequest) (uuid.UUID, error) {
+	if req.Price < minPrice {
+		return uuid.Nil, entities.ErrPriceTooLow
+	}
+
+	fileID, err := uuid.Parse(req.FileID)
+	if err != nil {
+		return uuid.Nil, entities.ErrFileNotFound
+	}
+
+	exists, err := s.repo.IsFileExist(ctx, req.FileID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to check file: %w", err)
+	}
+	if !exists {
+		return uuid.Nil, entities.ErrFileNotFound
+	}
+
+	taken, err := s.repo.IsSKUTaken(ctx, sellerID, req.SKU, productID)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to check sku: %w", err)
+	}
+	if taken {
+		return uuid.Nil, entities.ErrSKUAlreadyExists
+	}
+
+	return fileID, nil
+}
+
+// productCurrency is the currency the product is priced in
+func productCurrency(req dtos.ProductRequest) string {
+	if req.Currency == "" {
+		return money.DefaultCurrency
+	}
+	return req.Currency
+}
+
+// getOwnedProduct loads a product and verifies it belongs to the seller
+func (s *service) getOwnedProduct(ctx context.Context, productID string, sellerID uuid.UUID) (*entities.Product, error) {
+	if _, err := uuid.Parse(productID); err != nil {
+		return nil, entities.ErrProductNotFound
+	}
+
+	product, err := s.repo.GetProductByID(ctx, productID)
+	if err != nil {
+		return nil, err
+	}
+	if product.SellerID != sellerID {
+		return nil, entities.ErrProductForbidden
+	}
+	return product, nil
+}
+
+// sellerFromContext reads the authenticated user set by the gateway trust middleware
+func sellerFromContext(ctx context.Context) (uuid.UUID, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return uuid.Nil, fmt.Errorf("user context not found")
+	}
+	sellerID, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, entities.ErrInvalidSellerID
+	}
+	return sellerID, nil
+}
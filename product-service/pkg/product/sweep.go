@@ -0,0 +1,112 @@
+package product
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"product-service/pkg/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReleaseOrphanReservations releases stock that is still reserved, after
+// staleAfter, for a purchase the purchase service never wrote. Purchase
+// service reserves stock before it saves the purchase, so a crash in between
+// leaves reservations that nothing else would give back. Reservations of
+// existing purchases are left to purchase service. It returns the number of
+// reservations released.
+func (s *service) ReleaseOrphanReservations(ctx context.Context, staleAfter time.Duration, batchSize int) (int, error) {
+	released := 0
+	cutoff := time.Now().Add(-staleAfter)
+
+	afterID := uuid.Nil
+	for {
+		reservations, err := s.repo.GetReservationsCreatedBefore(ctx, entities.ReservationStatusReserved, cutoff, afterID, batchSize)
+		if err != nil {
+			return released, fmt.Errorf("failed to get stale reservations: %w", err)
+		}
+		if len(reservations) == 0 {
+			return released, nil
+		}
+		afterID = reservations[len(reservations)-1].ID
+
+		// Purchase IDs that are not UUIDs cannot belong to a purchase
+		purchaseIDs := make([]string, 0, len(reservations))
+		seen := make(map[string]bool, len(reservations))
+		for _, reservation := range reservations {
+			if _, err := uuid.Parse(reservation.PurchaseID); err != nil || seen[reservation.PurchaseID] {
+				continue
+			}
+			seen[reservation.PurchaseID] = true
+			purchaseIDs = append(purchaseIDs, reservation.PurchaseID)
+		}
+
+		existing := make(map[string]bool, len(purchaseIDs))
+		if len(purchaseIDs) > 0 {
+			ids, err := s.purchaseClient.GetExistingPurchaseIDs(ctx, purchaseIDs)
+			if err != nil {
+				return released, fmt.Errorf("failed to check purchases: %w", err)
+			}
+			for _, id := range ids {
+				existing[id] = true
+			}
+		}
+
+		for _, reservation := range reservations {
+			if existing[reservation.PurchaseID] {
+				continue
+			}
+			if err := s.repo.ReleaseReservation(ctx, reservation.ProductID.String(), reservation.PurchaseID); err != nil {
+				log.Printf("failed to release reservation of product %s for purchase %s: %v", reservation.ProductID, reservation.PurchaseID, err)
+				continue
+			}
+			released++
+		}
+
+		if len(reservations) < batchSize {
+			return released, nil
+		}
+	}
+}
+
+// ReservationSweeper periodically releases reservations of purchases that
+// were never written
+type ReservationSweeper struct {
+	service    Service
+	interval   time.Duration
+	staleAfter time.Duration
+	batchSize  int
+}
+
+func NewReservationSweeper(service Service, interval, staleAfter time.Duration, batchSize int) *ReservationSweeper {
+	return &ReservationSweeper{
+		service:    service,
+		interval:   interval,
+		staleAfter: staleAfter,
+		batchSize:  batchSize,
+	}
+}
+
+// Run sweeps orphaned reservations every interval until ctx is cancelled
+func (w *ReservationSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	log.Printf("reservation sweeper started (stale after %s, interval %s)", w.staleAfter, w.interval)
+	for {
+		released, err := w.service.ReleaseOrphanReservations(ctx, w.staleAfter, w.batchSize)
+		if err != nil {
+			log.Printf("failed to release orphaned reservations: %v", err)
+		}
+		if released > 0 {
+			log.Printf("released %d orphaned reservations", released)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
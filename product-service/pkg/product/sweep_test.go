@@ -0,0 +1,174 @@
+package product
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	nethttp "net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"product-service/pkg/entities"
+	"product-service/pkg/http"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB connects to the Postgres database in TEST_DATABASE_URL and runs
+// the migrations in a schema of its own, dropped when the test ends. Tests
+// that need the database are skipped when the variable is not set.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database handle: %v", err)
+	}
+	// A single connection keeps the search path below for every query
+	sqlDB.SetMaxOpenConns(1)
+
+	schema := fmt.Sprintf("product_test_%d", time.Now().UnixNano())
+	if err := db.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DROP SCHEMA " + schema + " CASCADE")
+		sqlDB.Close()
+	})
+	if err := db.Exec("SET search_path TO " + schema + ", public").Error; err != nil {
+		t.Fatalf("failed to set search path: %v", err)
+	}
+
+	migrations, err := filepath.Glob("../../migrations/db/*.up.sql")
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("failed to find migrations: %v", err)
+	}
+	sort.Strings(migrations)
+	for _, migration := range migrations {
+		sql, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", migration, err)
+		}
+		if err := db.Exec(string(sql)).Error; err != nil {
+			t.Fatalf("failed to run %s: %v", filepath.Base(migration), err)
+		}
+	}
+	return db
+}
+
+// newPurchaseService answers /purchase/batch with those of the asked IDs
+// that are in existing
+func newPurchaseService(t *testing.T, existing ...string) *httptest.Server {
+	t.Helper()
+	known := make(map[string]bool, len(existing))
+	for _, id := range existing {
+		known[id] = true
+	}
+	return httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.URL.Path != "/purchase/batch" {
+			nethttp.NotFound(w, r)
+			return
+		}
+		var req struct {
+			PurchaseIDs []string `json:"purchaseIds"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			nethttp.Error(w, err.Error(), nethttp.StatusBadRequest)
+			return
+		}
+		response := struct {
+			PurchaseIDs []string `json:"purchaseIds"`
+		}{PurchaseIDs: []string{}}
+		for _, id := range req.PurchaseIDs {
+			if known[id] {
+				response.PurchaseIDs = append(response.PurchaseIDs, id)
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("failed to encode purchase IDs: %v", err)
+		}
+	}))
+}
+
+func TestReleaseOrphanReservations(t *testing.T) {
+	db := openTestDB(t)
+
+	product := &entities.Product{SellerID: uuid.New(), Name: "Keyboard", Category: entities.CategoryTools, Qty: 5, Price: 150000, SKU: "KB-1"}
+	if err := db.Create(product).Error; err != nil {
+		t.Fatalf("failed to seed product: %v", err)
+	}
+
+	stale := time.Now().Add(-2 * time.Hour)
+	existingPurchase := uuid.NewString()
+	seed := func(purchaseID string, qty int, status string, createdAt time.Time) *entities.ProductReservation {
+		t.Helper()
+		reservation := &entities.ProductReservation{ProductID: product.ID, PurchaseID: purchaseID, Qty: qty, Status: status, CreatedAt: createdAt}
+		if err := db.Create(reservation).Error; err != nil {
+			t.Fatalf("failed to seed reservation: %v", err)
+		}
+		return reservation
+	}
+	// The purchase was never written, so the stock goes back
+	orphan := seed(uuid.NewString(), 2, entities.ReservationStatusReserved, stale)
+	// Not a purchase ID at all
+	malformed := seed("checkout-1", 1, entities.ReservationStatusReserved, stale)
+	// Left to purchase service, which knows the purchase
+	kept := seed(existingPurchase, 1, entities.ReservationStatusReserved, stale)
+	// The checkout may still be writing the purchase
+	fresh := seed(uuid.NewString(), 1, entities.ReservationStatusReserved, time.Now())
+	// Sold stock is never given back
+	committed := seed(uuid.NewString(), 3, entities.ReservationStatusCommitted, stale)
+
+	purchaseService := newPurchaseService(t, existingPurchase)
+	defer purchaseService.Close()
+	s := &service{repo: NewGormRepository(db), purchaseClient: http.NewClient(purchaseService.URL, "secret")}
+
+	// A batch size of two makes the sweep read more than one page
+	released, err := s.ReleaseOrphanReservations(context.Background(), time.Hour, 2)
+	if err != nil {
+		t.Fatalf("ReleaseOrphanReservations() error = %v", err)
+	}
+	if released != 2 {
+		t.Errorf("ReleaseOrphanReservations() = %d, want 2", released)
+	}
+
+	wantStatus := map[*entities.ProductReservation]string{
+		orphan:    entities.ReservationStatusReleased,
+		malformed: entities.ReservationStatusReleased,
+		kept:      entities.ReservationStatusReserved,
+		fresh:     entities.ReservationStatusReserved,
+		committed: entities.ReservationStatusCommitted,
+	}
+	for seeded, want := range wantStatus {
+		var reservation entities.ProductReservation
+		if err := db.First(&reservation, "id = ?", seeded.ID).Error; err != nil {
+			t.Fatalf("failed to read reservation %s: %v", seeded.ID, err)
+		}
+		if reservation.Status != want {
+			t.Errorf("reservation for purchase %s is %s, want %s", seeded.PurchaseID, reservation.Status, want)
+		}
+	}
+
+	var got entities.Product
+	if err := db.First(&got, "id = ?", product.ID).Error; err != nil {
+		t.Fatalf("failed to read product: %v", err)
+	}
+	if got.Qty != 8 {
+		t.Errorf("Qty = %d, want 8", got.Qty)
+	}
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"profile-service/api/presenter"
+	"profile-service/pkg/address"
+	"profile-service/pkg/entities"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+var validateAddress = validator.New()
+
+// ListAddresses is handler/controller which lists the current user's address book
+// @Summary      List addresses
+// @Description  List the current user's shipping addresses, the default first
+// @Tags         Address
+// @Produce      json
+// @Security BearerAuth
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /api/v1/user/addresses [get]
+func ListAddresses(service address.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		data, err := service.ListAddresses(c.Context(), c.Get("X-User-ID"))
+		if err != nil {
+			return addressError(c, err)
+		}
+
+		return c.JSON(presenter.AddressesResponse(data))
+	}
+}
+
+// CreateAddress is handler/controller which adds an address to the current user's address book
+// @Summary      Create address
+// @Description  Add a shipping address. The first address, or one sent with isDefault, becomes the default.
+// @Tags         Address
+// @Accept       json
+// @Produce      json
+// @Security BearerAuth
+// @Param        address  body      entities.AddressRequest   true  "Address"
+// @Success      201   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      409   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /api/v1/user/addresses [post]
+func CreateAddress(service address.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var requestBody entities.AddressRequest
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+		}
+
+		if errVal := validateAddress.Struct(requestBody); errVal != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse(errVal.Error()))
+		}
+
+		data, err := service.CreateAddress(c.Context(), c.Get("X-User-ID"), requestBody)
+		if err != nil {
+			return addressError(c, err)
+		}
+
+		return c.Status(http.StatusCreated).JSON(presenter.AddressResponse(data))
+	}
+}
+
+// UpdateAddress is handler/controller which replaces an address of the current user
+// @Summary      Update address
+// @Description  Replace the fields of a shipping address. Sending isDefault makes it the default; an address cannot stop being the default this way.
+// @Tags         Address
+// @Accept       json
+// @Produce      json
+// @Security BearerAuth
+// @Param        id       path      string                    true  "Address ID"
+// @Param        address  body      entities.AddressRequest   true  "Address"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /api/v1/user/addresses/{id} [put]
+func UpdateAddress(service address.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var requestBody entities.AddressRequest
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+		}
+
+		if errVal := validateAddress.Struct(requestBody); errVal != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse(errVal.Error()))
+		}
+
+		data, err := service.UpdateAddress(c.Context(), c.Get("X-User-ID"), c.Params("id"), requestBody)
+		if err != nil {
+			return addressError(c, err)
+		}
+
+		return c.JSON(presenter.AddressResponse(data))
+	}
+}
+
+// DeleteAddress is handler/controller which removes an address of the current user
+// @Summary      Delete address
+// @Description  Remove a shipping address. When it was the default, the oldest remaining address becomes the default. Purchases keep their own copy of the address.
+// @Tags         Address
+// @Produce      json
+// @Security BearerAuth
+// @Param        id   path      string  true  "Address ID"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /api/v1/user/addresses/{id} [delete]
+func DeleteAddress(service address.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := service.DeleteAddress(c.Context(), c.Get("X-User-ID"), c.Params("id")); err != nil {
+			return addressError(c, err)
+		}
+
+		return c.JSON(fiber.Map{
+			"message": "address deleted",
+		})
+	}
+}
+
+// SetDefaultAddress is handler/controller which makes an address the current user's default
+// @Summary      Set default address
+// @Description  Make a shipping address the default, replacing the previous default
+// @Tags         Address
+// @Produce      json
+// @Security BearerAuth
+// @Param        id   path      string  true  "Address ID"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /api/v1/user/addresses/{id}/default [post]
+func SetDefaultAddress(service address.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		data, err := service.SetDefaultAddress(c.Context(), c.Get("X-User-ID"), c.Params("id"))
+		if err != nil {
+			return addressError(c, err)
+		}
+
+		return c.JSON(presenter.AddressResponse(data))
+	}
+}
+
+// GetAddressDetail is handler/controller which returns any address with its owner
+// @Summary      Get address detail (internal)
+// @Description  Internal lookup of an address and its owner by ID, used by purchase-service to snapshot the shipping address of a purchase
+// @Tags         Internal
+// @Produce      json
+// @Param        id   path      string  true  "Address ID"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /address/{id} [get]
+func GetAddressDetail(service address.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		data, err := service.GetAddress(c.Context(), c.Params("id"))
+		if err != nil {
+			return addressError(c, err)
+		}
+
+		return c.JSON(presenter.AddressDetailResponse(data))
+	}
+}
+
+// addressError maps address book errors to HTTP responses
+func addressError(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, entities.ErrInvalidUserID), errors.Is(err, entities.ErrInvalidAddressID):
+		return c.Status(http.StatusBadRequest).
+			JSON(presenter.ErrorResponse(err.Error()))
+	case errors.Is(err, entities.ErrAddressNotFound):
+		return c.Status(http.StatusNotFound).
+			JSON(presenter.ErrorResponse(err.Error()))
+	case errors.Is(err, entities.ErrAddressBookFull):
+		return c.Status(http.StatusConflict).
+			JSON(presenter.ErrorResponse(err.Error()))
+	}
+	return c.Status(http.StatusInternalServerError).
+		JSON(presenter.ErrorResponse(err.Error()))
+}
@@ -181,3 +181,72 @@ func UpdatePhone(service user.Service) fiber.Handler {
 
 	}
 }
+
+// GetUserDetail is handler/controller which returns any user's payout details
+// @Summary      Get user detail (internal)
+// @Description  Internal lookup of a user's bank account details by ID, used by purchase-service
+// @Tags         Internal
+// @Produce      json
+// @Param        id   path      string  true  "User ID"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      404   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /user/{id} [get]
+func GetUserDetail(service user.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		data, err := service.GetUserDetail(c.Context(), c.Params("id"))
+		if err != nil {
+			if errors.Is(err, entities.ErrInvalidUserID) {
+				return c.Status(http.StatusBadRequest).
+					JSON(presenter.ErrorResponse(err.Error()))
+			}
+			if errors.Is(err, entities.ErrUserNotFound) {
+				return c.Status(http.StatusNotFound).
+					JSON(presenter.ErrorResponse(err.Error()))
+			}
+			return c.Status(http.StatusInternalServerError).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		return c.JSON(presenter.UserDetailResponse(data))
+	}
+}
+
+// GetUserDetails is handler/controller which returns payout details for many users
+// @Summary      Get user details in batch (internal)
+// @Description  Internal lookup of bank account details for many user IDs at once
+// @Tags         Internal
+// @Accept       json
+// @Produce      json
+// @Param        request  body      entities.UserIDsRequest   true  "User IDs"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /user/batch [post]
+func GetUserDetails(service user.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var requestBody entities.UserIDsRequest
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+		}
+
+		if errVal := validateUser.Struct(requestBody); errVal != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse(errVal.Error()))
+		}
+
+		data, err := service.GetUserDetails(c.Context(), requestBody.IDs)
+		if err != nil {
+			if errors.Is(err, entities.ErrInvalidUserID) {
+				return c.Status(http.StatusBadRequest).
+					JSON(presenter.ErrorResponse(err.Error()))
+			}
+			return c.Status(http.StatusInternalServerError).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		return c.JSON(presenter.UserDetailsResponse(data))
+	}
+}
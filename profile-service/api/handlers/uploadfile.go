@@ -2,8 +2,10 @@ package handlers
 
 import (
 	"crypto/rand"
+	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
 	"profile-service/api/presenter"
 	"profile-service/pkg/entities"
 	"profile-service/pkg/uploadfile"
@@ -12,9 +14,13 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
+var validateFile = validator.New()
+
 const letters = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789"
 
 func RandomString(n int) (string, error) {
@@ -83,6 +89,10 @@ func UploadFile(fileService uploadfile.Service) fiber.Handler {
 			FileUri:          savePath,
 			FileThumbnailUri: savePath,
 		}
+		// Record the uploader so other services can check who owns the file
+		if userID, err := uuid.Parse(c.Get("X-User-ID")); err == nil {
+			upload_file.UserID = &userID
+		}
 
 		result, err := fileService.UploadFile(&upload_file)
 		if err != nil {
@@ -97,3 +107,41 @@ func UploadFile(fileService uploadfile.Service) fiber.Handler {
 		})
 	}
 }
+
+// GetFileDetails is handler/controller which returns many files with their owners
+// @Summary      Get file details in batch (internal)
+// @Description  Internal lookup of files and their uploaders for many file IDs at once, used by purchase-service to validate and display payment proofs, review images and dispute evidence
+// @Tags         Internal
+// @Accept       json
+// @Produce      json
+// @Param        request  body      entities.FileIDsRequest   true  "File IDs"
+// @Success      200   {object}  map[string]interface{}
+// @Failure      400   {object}  map[string]interface{}
+// @Failure      500   {object}  map[string]interface{}
+// @Router       /file/batch [post]
+func GetFileDetails(fileService uploadfile.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var requestBody entities.FileIDsRequest
+		if err := c.BodyParser(&requestBody); err != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse("invalid request body: " + err.Error()))
+		}
+
+		if errVal := validateFile.Struct(requestBody); errVal != nil {
+			return c.Status(http.StatusBadRequest).
+				JSON(presenter.ErrorResponse(errVal.Error()))
+		}
+
+		data, err := fileService.GetFiles(c.Context(), requestBody.IDs)
+		if err != nil {
+			if errors.Is(err, entities.ErrInvalidFileID) {
+				return c.Status(http.StatusBadRequest).
+					JSON(presenter.ErrorResponse(err.Error()))
+			}
+			return c.Status(http.StatusInternalServerError).
+				JSON(presenter.ErrorResponse(err.Error()))
+		}
+
+		return c.JSON(presenter.FileDetailsResponse(data))
+	}
+}
@@ -42,3 +42,81 @@ func ProfileSuccessResponse(data *entities.User) *fiber.Map {
 		"bankAccountNumber": data.BankAccountNumber,
 	}
 }
+
+// UserDetailResponse is the internal view of a user consumed by other services
+func UserDetailResponse(data *entities.User) *fiber.Map {
+	return &fiber.Map{
+		"id":                data.ID.String(),
+		"bankAccountName":   data.BankAccountName,
+		"bankAccountHolder": data.BankAccountHolder,
+		"bankAccountNumber": data.BankAccountNumber,
+	}
+}
+
+func UserDetailsResponse(data []*entities.User) *fiber.Map {
+	users := make([]*fiber.Map, 0, len(data))
+	for _, u := range data {
+		users = append(users, UserDetailResponse(u))
+	}
+	return &fiber.Map{
+		"users": users,
+	}
+}
+
+// FileDetailResponse is the internal view of a file consumed by other services
+func FileDetailResponse(data *entities.File) *fiber.Map {
+	userID := ""
+	if data.UserID != nil {
+		userID = data.UserID.String()
+	}
+	return &fiber.Map{
+		"id":               data.ID.String(),
+		"userId":           userID,
+		"fileUri":          data.FileUri,
+		"fileThumbnailUri": data.FileThumbnailUri,
+	}
+}
+
+func FileDetailsResponse(data []*entities.File) *fiber.Map {
+	files := make([]*fiber.Map, 0, len(data))
+	for _, f := range data {
+		files = append(files, FileDetailResponse(f))
+	}
+	return &fiber.Map{
+		"files": files,
+	}
+}
+
+// AddressResponse is an entry of the user's address book
+func AddressResponse(data *entities.Address) *fiber.Map {
+	return &fiber.Map{
+		"id":            data.ID.String(),
+		"label":         data.Label,
+		"recipientName": data.RecipientName,
+		"phone":         data.Phone,
+		"addressLine1":  data.AddressLine1,
+		"addressLine2":  data.AddressLine2,
+		"city":          data.City,
+		"province":      data.Province,
+		"postalCode":    data.PostalCode,
+		"country":       data.Country,
+		"isDefault":     data.IsDefault,
+	}
+}
+
+func AddressesResponse(data []*entities.Address) *fiber.Map {
+	addresses := make([]*fiber.Map, 0, len(data))
+	for _, a := range data {
+		addresses = append(addresses, AddressResponse(a))
+	}
+	return &fiber.Map{
+		"addresses": addresses,
+	}
+}
+
+// AddressDetailResponse is the internal view of an address consumed by other services
+func AddressDetailResponse(data *entities.Address) *fiber.Map {
+	response := AddressResponse(data)
+	(*response)["userId"] = data.UserID.String()
+	return response
+}
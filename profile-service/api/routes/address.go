@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"profile-service/api/handlers"
+	"profile-service/api/middleware"
+	"profile-service/pkg/address"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+func AddressRouter(app fiber.Router, addressService address.Service, v *viper.Viper) {
+
+	addresses := app.Group("/user/addresses", middleware.GatewayTrust(v))
+	addresses.Get("", handlers.ListAddresses(addressService))
+	addresses.Post("", handlers.CreateAddress(addressService))
+	addresses.Put("/:id", handlers.UpdateAddress(addressService))
+	addresses.Delete("/:id", handlers.DeleteAddress(addressService))
+	addresses.Post("/:id/default", handlers.SetDefaultAddress(addressService))
+}
+
+// InternalAddressRouter exposes address lookups to other services (not proxied by the gateway)
+func InternalAddressRouter(app fiber.Router, addressService address.Service, v *viper.Viper) {
+	internal := app.Group("/address", middleware.GatewayTrust(v))
+	internal.Get("/:id", handlers.GetAddressDetail(addressService))
+}
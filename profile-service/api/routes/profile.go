@@ -19,3 +19,10 @@ func ProfileRouter(app fiber.Router, userservice user.Service, jwtManager *confi
 	profile.Post("/link/email", handlers.UpdateEmail(userservice))
 	profile.Post("/link/phone", handlers.UpdatePhone(userservice))
 }
+
+// InternalUserRouter exposes user lookups to other services (not proxied by the gateway)
+func InternalUserRouter(app fiber.Router, userservice user.Service, v *viper.Viper) {
+	internal := app.Group("/user", middleware.GatewayTrust(v))
+	internal.Post("/batch", handlers.GetUserDetails(userservice))
+	internal.Get("/:id", handlers.GetUserDetail(userservice))
+}
@@ -25,5 +25,11 @@ func SetupRoutes(app *fiber.App, v *viper.Viper, db *gorm.DB, services config.Se
 
 	ProfileRouter(api, services.UserService, jwtManager, v)
 	UploadfileRouter(api, services.FileService, jwtManager, v)
+	AddressRouter(api, services.AddressService, v)
+
+	// Internal routes for service-to-service calls
+	InternalUserRouter(app, services.UserService, v)
+	InternalFileRouter(app, services.FileService, v)
+	InternalAddressRouter(app, services.AddressService, v)
 
 }
@@ -20,3 +20,9 @@ func UploadfileRouter(app fiber.Router, userService uploadfile.Service, jwtManag
 	)
 
 }
+
+// InternalFileRouter exposes file lookups to other services (not proxied by the gateway)
+func InternalFileRouter(app fiber.Router, fileService uploadfile.Service, v *viper.Viper) {
+	internal := app.Group("/file", middleware.GatewayTrust(v))
+	internal.Post("/batch", handlers.GetFileDetails(fileService))
+}
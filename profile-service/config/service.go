@@ -1,6 +1,7 @@
 package config
 
 import (
+	"profile-service/pkg/address"
 	"profile-service/pkg/uploadfile"
 	"profile-service/pkg/user"
 
@@ -9,8 +10,9 @@ import (
 
 // Services struct holds all service dependencies
 type Services struct {
-	UserService user.Service
-	FileService uploadfile.Service
+	UserService    user.Service
+	FileService    uploadfile.Service
+	AddressService address.Service
 }
 
 // InitServices initializes all application services
@@ -24,10 +26,14 @@ func InitServices(db *gorm.DB) Services {
 	fileRepo := uploadfile.NewRepo(db)
 	fileService := uploadfile.NewService(fileRepo)
 
+	addressRepo := address.NewGormRepository(db)
+	addressService := address.NewService(addressRepo)
+
 	// userfile.Service
 
 	return Services{
-		UserService: userService,
-		FileService: fileService,
+		UserService:    userService,
+		FileService:    fileService,
+		AddressService: addressService,
 	}
 }
@@ -0,0 +1,6 @@
+-- Record who uploaded each file so other services can check ownership.
+-- Files uploaded before this migration have no known owner.
+ALTER TABLE public.files
+    ADD COLUMN IF NOT EXISTS "userId" UUID;
+
+CREATE INDEX IF NOT EXISTS idx_files_user_id ON public.files ("userId");
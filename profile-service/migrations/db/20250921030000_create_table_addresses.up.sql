@@ -0,0 +1,23 @@
+-- Each user's address book of shipping addresses
+CREATE TABLE IF NOT EXISTS public.addresses (
+    id UUID NOT NULL,
+    "userId" UUID NOT NULL,
+    label VARCHAR(32) NOT NULL DEFAULT '',
+    "recipientName" VARCHAR(64) NOT NULL,
+    phone VARCHAR(20) NOT NULL,
+    "addressLine1" VARCHAR(255) NOT NULL,
+    "addressLine2" VARCHAR(255) NOT NULL DEFAULT '',
+    city VARCHAR(64) NOT NULL,
+    province VARCHAR(64) NOT NULL,
+    "postalCode" VARCHAR(10) NOT NULL,
+    country VARCHAR(2) NOT NULL DEFAULT 'ID',
+    "isDefault" BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at TIMESTAMPTZ,
+    updated_at TIMESTAMPTZ,
+    CONSTRAINT addresses_pkey PRIMARY KEY (id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_addresses_user_id ON public.addresses ("userId");
+
+-- A user has at most one default address
+CREATE UNIQUE INDEX IF NOT EXISTS uq_addresses_user_id_default ON public.addresses ("userId") WHERE "isDefault";
@@ -0,0 +1,114 @@
+package address
+
+import (
+	"context"
+	"profile-service/pkg/entities"
+
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	// WithTransaction runs fn against a repository bound to a single database
+	// transaction; any error returned by fn rolls back every write made through it
+	WithTransaction(ctx context.Context, fn func(tx Repository) error) error
+	FindByUserID(ctx context.Context, userID string) ([]*entities.Address, error)
+	CountByUserID(ctx context.Context, userID string) (int64, error)
+	// FindByID returns gorm.ErrRecordNotFound for unknown IDs
+	FindByID(ctx context.Context, id string) (*entities.Address, error)
+	Create(ctx context.Context, address *entities.Address) error
+	Update(ctx context.Context, address *entities.Address) error
+	Delete(ctx context.Context, id string) error
+	// SetDefault makes the address its user's default, clearing the previous one
+	SetDefault(ctx context.Context, userID, id string) error
+	// PromoteOldest makes the user's oldest address the default, if there is one
+	PromoteOldest(ctx context.Context, userID string) error
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) WithTransaction(ctx context.Context, fn func(tx Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&GormRepository{db: tx})
+	})
+}
+
+func (r *GormRepository) FindByUserID(ctx context.Context, userID string) ([]*entities.Address, error) {
+	var addresses []*entities.Address
+	if err := r.db.WithContext(ctx).
+		Where(`"userId" = ?`, userID).
+		Order(`"isDefault" DESC, created_at ASC, id ASC`).
+		Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+func (r *GormRepository) CountByUserID(ctx context.Context, userID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.Address{}).
+		Where(`"userId" = ?`, userID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *GormRepository) FindByID(ctx context.Context, id string) (*entities.Address, error) {
+	var address entities.Address
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&address).Error; err != nil {
+		return nil, err
+	}
+	return &address, nil
+}
+
+func (r *GormRepository) Create(ctx context.Context, address *entities.Address) error {
+	return r.db.WithContext(ctx).Create(address).Error
+}
+
+func (r *GormRepository) Update(ctx context.Context, address *entities.Address) error {
+	return r.db.WithContext(ctx).Model(&entities.Address{}).
+		Where("id = ?", address.ID).
+		Updates(map[string]interface{}{
+			"label":         address.Label,
+			"recipientName": address.RecipientName,
+			"phone":         address.Phone,
+			"addressLine1":  address.AddressLine1,
+			"addressLine2":  address.AddressLine2,
+			"city":          address.City,
+			"province":      address.Province,
+			"postalCode":    address.PostalCode,
+			"country":       address.Country,
+		}).Error
+}
+
+func (r *GormRepository) Delete(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&entities.Address{}).Error
+}
+
+func (r *GormRepository) SetDefault(ctx context.Context, userID, id string) error {
+	if err := r.db.WithContext(ctx).Model(&entities.Address{}).
+		Where(`"userId" = ? AND "isDefault" AND id <> ?`, userID, id).
+		Update("isDefault", false).Error; err != nil {
+		return err
+	}
+	return r.db.WithContext(ctx).Model(&entities.Address{}).
+		Where("id = ?", id).
+		Update("isDefault", true).Error
+}
+
+func (r *GormRepository) PromoteOldest(ctx context.Context, userID string) error {
+	oldest := r.db.Model(&entities.Address{}).
+		Select("id").
+		Where(`"userId" = ?`, userID).
+		Order("created_at ASC, id ASC").
+		Limit(1)
+	return r.db.WithContext(ctx).Model(&entities.Address{}).
+		Where("id = (?)", oldest).
+		Update("isDefault", true).Error
+}
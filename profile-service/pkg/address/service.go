@@ -0,0 +1,181 @@
+package address
+
+import (
+	"context"
+	"errors"
+	"profile-service/pkg/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Service interface {
+	ListAddresses(ctx context.Context, userID string) ([]*entities.Address, error)
+	CreateAddress(ctx context.Context, userID string, req entities.AddressRequest) (*entities.Address, error)
+	UpdateAddress(ctx context.Context, userID, id string, req entities.AddressRequest) (*entities.Address, error)
+	DeleteAddress(ctx context.Context, userID, id string) error
+	SetDefaultAddress(ctx context.Context, userID, id string) (*entities.Address, error)
+	// GetAddress looks up any address by ID for internal service-to-service calls
+	GetAddress(ctx context.Context, id string) (*entities.Address, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+func (s *service) ListAddresses(ctx context.Context, userID string) ([]*entities.Address, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, entities.ErrInvalidUserID
+	}
+	return s.repo.FindByUserID(ctx, userID)
+}
+
+// CreateAddress adds an address to the user's book. The first address
+// becomes the default, as does any address created with IsDefault.
+func (s *service) CreateAddress(ctx context.Context, userID string, req entities.AddressRequest) (*entities.Address, error) {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, entities.ErrInvalidUserID
+	}
+
+	address := &entities.Address{UserID: parsedUserID}
+	applyAddressRequest(address, req)
+
+	err = s.repo.WithTransaction(ctx, func(tx Repository) error {
+		count, err := tx.CountByUserID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if count >= entities.MaxAddresses {
+			return entities.ErrAddressBookFull
+		}
+
+		if err := tx.Create(ctx, address); err != nil {
+			return err
+		}
+		if count == 0 || req.IsDefault {
+			address.IsDefault = true
+			return tx.SetDefault(ctx, userID, address.ID.String())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// UpdateAddress replaces the fields of one of the user's addresses
+func (s *service) UpdateAddress(ctx context.Context, userID, id string, req entities.AddressRequest) (*entities.Address, error) {
+	var address *entities.Address
+	err := s.repo.WithTransaction(ctx, func(tx Repository) error {
+		var err error
+		if address, err = findUserAddress(ctx, tx, userID, id); err != nil {
+			return err
+		}
+
+		applyAddressRequest(address, req)
+		if err := tx.Update(ctx, address); err != nil {
+			return err
+		}
+		if req.IsDefault && !address.IsDefault {
+			address.IsDefault = true
+			return tx.SetDefault(ctx, userID, id)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+// DeleteAddress removes one of the user's addresses; when it was the
+// default, the oldest remaining address takes over
+func (s *service) DeleteAddress(ctx context.Context, userID, id string) error {
+	return s.repo.WithTransaction(ctx, func(tx Repository) error {
+		address, err := findUserAddress(ctx, tx, userID, id)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.Delete(ctx, id); err != nil {
+			return err
+		}
+		if address.IsDefault {
+			return tx.PromoteOldest(ctx, userID)
+		}
+		return nil
+	})
+}
+
+func (s *service) SetDefaultAddress(ctx context.Context, userID, id string) (*entities.Address, error) {
+	var address *entities.Address
+	err := s.repo.WithTransaction(ctx, func(tx Repository) error {
+		var err error
+		if address, err = findUserAddress(ctx, tx, userID, id); err != nil {
+			return err
+		}
+
+		address.IsDefault = true
+		return tx.SetDefault(ctx, userID, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return address, nil
+}
+
+func (s *service) GetAddress(ctx context.Context, id string) (*entities.Address, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, entities.ErrInvalidAddressID
+	}
+
+	address, err := s.repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entities.ErrAddressNotFound
+	}
+	return address, err
+}
+
+// findUserAddress loads an address of the user; other users' addresses are
+// reported as not found
+func findUserAddress(ctx context.Context, repo Repository, userID, id string) (*entities.Address, error) {
+	if _, err := uuid.Parse(userID); err != nil {
+		return nil, entities.ErrInvalidUserID
+	}
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, entities.ErrInvalidAddressID
+	}
+
+	address, err := repo.FindByID(ctx, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, entities.ErrAddressNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if address.UserID.String() != userID {
+		return nil, entities.ErrAddressNotFound
+	}
+	return address, nil
+}
+
+func applyAddressRequest(address *entities.Address, req entities.AddressRequest) {
+	address.Label = req.Label
+	address.RecipientName = req.RecipientName
+	address.Phone = req.Phone
+	address.AddressLine1 = req.AddressLine1
+	address.AddressLine2 = req.AddressLine2
+	address.City = req.City
+	address.Province = req.Province
+	address.PostalCode = req.PostalCode
+	address.Country = req.Country
+	if address.Country == "" {
+		address.Country = "ID"
+	}
+}
@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// addresses table; a user's address book of shipping addresses
+type Address struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey;column:id"`
+	UserID        uuid.UUID `gorm:"type:uuid;column:userId;not null"`
+	Label         string    `gorm:"type:varchar(32);column:label"`
+	RecipientName string    `gorm:"type:varchar(64);column:recipientName;not null"`
+	Phone         string    `gorm:"type:varchar(20);column:phone;not null"`
+	AddressLine1  string    `gorm:"type:varchar(255);column:addressLine1;not null"`
+	AddressLine2  string    `gorm:"type:varchar(255);column:addressLine2"`
+	City          string    `gorm:"type:varchar(64);column:city;not null"`
+	Province      string    `gorm:"type:varchar(64);column:province;not null"`
+	PostalCode    string    `gorm:"type:varchar(10);column:postalCode;not null"`
+	Country       string    `gorm:"type:varchar(2);column:country;not null;default:ID"`
+	IsDefault     bool      `gorm:"column:isDefault;not null;default:false"` // At most one per user
+
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// AddressRequest creates or replaces an address; Country is an ISO 3166-1
+// alpha-2 code and defaults to ID
+type AddressRequest struct {
+	Label         string `json:"label" validate:"max=32"`
+	RecipientName string `json:"recipientName" validate:"required,min=2,max=64"`
+	Phone         string `json:"phone" validate:"required,min=8,max=20"`
+	AddressLine1  string `json:"addressLine1" validate:"required,max=255"`
+	AddressLine2  string `json:"addressLine2" validate:"max=255"`
+	City          string `json:"city" validate:"required,max=64"`
+	Province      string `json:"province" validate:"required,max=64"`
+	PostalCode    string `json:"postalCode" validate:"required,numeric,min=3,max=10"`
+	Country       string `json:"country" validate:"omitempty,len=2,uppercase"`
+	IsDefault     bool   `json:"isDefault"`
+}
+
+// MaxAddresses is how many addresses a user's address book can hold
+const MaxAddresses = 20
+
+var (
+	ErrInvalidAddressID = errors.New("addressID is not valid")
+	ErrAddressNotFound  = errors.New("address not found")
+	ErrAddressBookFull  = errors.New("address book is full")
+)
+
+// BeforeCreate ensures UUID v7 is set by the application (no DB default)
+func (a *Address) BeforeCreate(tx *gorm.DB) (err error) {
+	if a.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		a.ID = id
+	}
+	return nil
+}
+
+func (Address) TableName() string { return "addresses" }
@@ -10,9 +10,10 @@ import (
 
 // files table
 type File struct {
-	ID               uuid.UUID `gorm:"type:uuid;primaryKey;column:id"`
-	FileUri          string    `gorm:"type:varchar(255);column:fileUri"`
-	FileThumbnailUri string    `gorm:"type:varchar(255);column:fileThumbnailUri"`
+	ID               uuid.UUID  `gorm:"type:uuid;primaryKey;column:id"`
+	FileUri          string     `gorm:"type:varchar(255);column:fileUri"`
+	FileThumbnailUri string     `gorm:"type:varchar(255);column:fileThumbnailUri"`
+	UserID           *uuid.UUID `gorm:"type:uuid;column:userId"` // Uploader; nil for files uploaded before ownership was recorded
 
 	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
 	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
@@ -55,6 +56,16 @@ type UpdateUserRequest struct {
 	BankAccountNumber string `gorm:"not null;column:bankAccountNumber" json:"bankAccountNumber" validate:"required,min=4,max=32"`
 }
 
+// UserIDsRequest is the body of the internal batch user lookup
+type UserIDsRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100,dive,uuid"`
+}
+
+// FileIDsRequest is the body of the internal batch file lookup
+type FileIDsRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100,dive,uuid"`
+}
+
 // Service layer request types (better practice)
 type CreateUserRequest struct {
 	Email    string
@@ -73,6 +84,7 @@ var (
 	ErrFileNotFound       = errors.New("fileID not found")
 	ErrInvalidUserID      = errors.New("userID is not valid")
 	ErrInvalidPhoneNumber = errors.New("phone number is not valid")
+	ErrUserNotFound       = errors.New("user not found")
 )
 
 // BeforeCreate ensures UUID v7 is set by the application (no DB default)
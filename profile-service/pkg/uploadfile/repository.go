@@ -1,6 +1,7 @@
 package uploadfile
 
 import (
+	"context"
 	"errors"
 	"profile-service/pkg/entities"
 
@@ -12,6 +13,7 @@ type Repository interface {
 	UploadFile(file *entities.File) (*entities.File, error)
 	GetUserFile(ID uint) (*entities.File, error)
 	IsFileExist(id string) (bool, error)
+	FindByIDs(ctx context.Context, ids []string) ([]*entities.File, error)
 }
 
 type repository struct {
@@ -58,3 +60,11 @@ func (r *repository) IsFileExist(id string) (bool, error) {
 	return true, nil
 
 }
+
+func (r *repository) FindByIDs(ctx context.Context, ids []string) ([]*entities.File, error) {
+	var files []*entities.File
+	if err := r.DB.WithContext(ctx).Where("id IN ?", ids).Find(&files).Error; err != nil {
+		return nil, err
+	}
+	return files, nil
+}
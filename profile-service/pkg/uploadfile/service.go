@@ -1,12 +1,18 @@
 package uploadfile
 
-import "profile-service/pkg/entities"
+import (
+	"context"
+	"profile-service/pkg/entities"
+
+	"github.com/google/uuid"
+)
 
 // Service is an interface from which our api module can access our repository of all our models
 type Service interface {
 	UploadFile(file *entities.File) (*entities.File, error)
 	GetUserFile(ID uint) (*entities.File, error)
 	isFileExist(id string) (bool, error)
+	GetFiles(ctx context.Context, ids []string) ([]*entities.File, error)
 }
 
 type service struct {
@@ -31,3 +37,14 @@ func (s *service) GetUserFile(userID uint) (*entities.File, error) {
 func (s *service) isFileExist(id string) (bool, error) {
 	return s.repository.IsFileExist(id)
 }
+
+// GetFiles looks up many files at once for internal service-to-service calls;
+// unknown IDs are simply absent
+func (s *service) GetFiles(ctx context.Context, ids []string) ([]*entities.File, error) {
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, entities.ErrInvalidFileID
+		}
+	}
+	return s.repository.FindByIDs(ctx, ids)
+}
@@ -14,6 +14,7 @@ type Repository interface {
 	FindByPhone(ctx context.Context, phone string) (*entities.User, error)
 	FindByID(id string) (*entities.User, error)
 	GetByID(id string) (*entities.User, error)
+	FindByIDs(ctx context.Context, ids []string) ([]*entities.User, error)
 	UpdateProfile(user *entities.User) (*entities.User, error)
 	IsFileExist(id string) (bool, error)
 	UpdateEmail(user *entities.User) (*entities.User, error)
@@ -126,6 +127,14 @@ func (r *GormRepository) GetByID(id string) (*entities.User, error) {
 	return &user, nil
 }
 
+func (r *GormRepository) FindByIDs(ctx context.Context, ids []string) ([]*entities.User, error) {
+	var users []*entities.User
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *GormRepository) IsFileExist(id string) (bool, error) {
 
 	var file entities.File
@@ -1,6 +1,7 @@
 package user
 
 import (
+	"context"
 	"profile-service/pkg/entities"
 	"regexp"
 
@@ -10,6 +11,8 @@ import (
 type Service interface {
 	FindByID(id string) (*entities.User, error)
 	GetByID(id string) (*entities.User, error)
+	GetUserDetail(ctx context.Context, id string) (*entities.User, error)
+	GetUserDetails(ctx context.Context, ids []string) ([]*entities.User, error)
 	IsFileExist(id string) (bool, error)
 	UpdateEmail(userIDString string, email string) (*entities.User, error)
 	UpdatePhone(userIDString string, phone string) (*entities.User, error)
@@ -108,3 +111,29 @@ func (s *service) UpdatePhone(userIDString string, phone string) (*entities.User
 func (s *service) GetByID(id string) (*entities.User, error) {
 	return s.repo.GetByID(id)
 }
+
+// GetUserDetail looks up any user by ID for internal service-to-service calls
+func (s *service) GetUserDetail(ctx context.Context, id string) (*entities.User, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, entities.ErrInvalidUserID
+	}
+
+	users, err := s.repo.FindByIDs(ctx, []string{id})
+	if err != nil {
+		return nil, err
+	}
+	if len(users) == 0 {
+		return nil, entities.ErrUserNotFound
+	}
+	return users[0], nil
+}
+
+// GetUserDetails looks up many users at once; unknown IDs are simply absent
+func (s *service) GetUserDetails(ctx context.Context, ids []string) ([]*entities.User, error) {
+	for _, id := range ids {
+		if _, err := uuid.Parse(id); err != nil {
+			return nil, entities.ErrInvalidUserID
+		}
+	}
+	return s.repo.FindByIDs(ctx, ids)
+}
@@ -0,0 +1,222 @@
+package handlers
+
+import (
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/purchase"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type CartHandler struct {
+	service   purchase.Service
+	validator *validator.Validate
+}
+
+func NewCartHandler(service purchase.Service) *CartHandler {
+	return &CartHandler{
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+// GetCart handles GET /v1/cart
+// @Summary Get cart
+// @Description Get the customer's cart priced with the current product details. Items whose price changed since they were added are flagged, and items that were deleted or lack stock are marked unavailable.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Success 200 {object} presenter.CartResponse
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/cart [get]
+func (h *CartHandler) GetCart(c *fiber.Ctx) error {
+	cart, err := h.service.GetCart(c.Context())
+	if err != nil {
+		return handleError(c, err, "Failed to get cart")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(cart)
+}
+
+// AddItem handles POST /v1/cart/items
+// @Summary Add item to cart
+// @Description Add a product to the cart, on top of the quantity already in it. All products in a cart must share a currency.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param request body dtos.AddCartItemRequest true "Cart item"
+// @Success 200 {object} presenter.CartResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/cart/items [post]
+func (h *CartHandler) AddItem(c *fiber.Ctx) error {
+	var req dtos.AddCartItemRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
+		})
+	}
+
+	cart, err := h.service.AddCartItem(c.Context(), req)
+	if err != nil {
+		return handleError(c, err, "Failed to add item to cart")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(cart)
+}
+
+// UpdateItem handles PUT /v1/cart/items/:productId
+// @Summary Update cart item quantity
+// @Description Set the quantity of a product in the cart
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param productId path string true "Product ID"
+// @Param request body dtos.UpdateCartItemRequest true "New quantity"
+// @Success 200 {object} presenter.CartResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/cart/items/{productId} [put]
+func (h *CartHandler) UpdateItem(c *fiber.Ctx) error {
+	var req dtos.UpdateCartItemRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
+		})
+	}
+
+	cart, err := h.service.UpdateCartItem(c.Context(), c.Params("productId"), req)
+	if err != nil {
+		return handleError(c, err, "Failed to update cart item")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(cart)
+}
+
+// RemoveItem handles DELETE /v1/cart/items/:productId
+// @Summary Remove item from cart
+// @Description Take a product out of the cart
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param productId path string true "Product ID"
+// @Success 200 {object} presenter.CartResponse
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/cart/items/{productId} [delete]
+func (h *CartHandler) RemoveItem(c *fiber.Ctx) error {
+	cart, err := h.service.RemoveCartItem(c.Context(), c.Params("productId"))
+	if err != nil {
+		return handleError(c, err, "Failed to remove cart item")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(cart)
+}
+
+// ClearCart handles DELETE /v1/cart
+// @Summary Clear cart
+// @Description Remove every product from the cart
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/cart [delete]
+func (h *CartHandler) ClearCart(c *fiber.Ctx) error {
+	if err := h.service.ClearCart(c.Context()); err != nil {
+		return handleError(c, err, "Failed to clear cart")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Cart cleared successfully",
+	})
+}
+
+// Checkout handles POST /v1/cart/checkout
+// @Summary Check out the cart
+// @Description Turn the cart into a purchase. Prices and stock are checked against the product service first: when a price changed, the cart takes the new price and 409 is returned so the customer can review it. The cart is emptied once the purchase is created.
+// @Tags cart
+// @Accept json
+// @Produce json
+// @Param Idempotency-Key header string false "Unique key to safely retry the request; the original response is replayed"
+// @Param request body dtos.CheckoutRequest true "Sender of the purchase"
+// @Success 201 {object} presenter.PurchaseResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/cart/checkout [post]
+func (h *CartHandler) Checkout(c *fiber.Ctx) error {
+	var req dtos.CheckoutRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
+		})
+	}
+
+	// Additional validation for contact details
+	if err := validateContactDetails(h.validator, req.SenderContactType, req.SenderContactDetail); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	purchase, err := h.service.Checkout(c.Context(), req)
+	if err != nil {
+		return handleError(c, err, "Failed to check out cart")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(purchase)
+}
@@ -0,0 +1,247 @@
+package handlers
+
+import (
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/purchase"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type DisputeHandler struct {
+	service   purchase.Service
+	validator *validator.Validate
+}
+
+func NewDisputeHandler(service purchase.Service) *DisputeHandler {
+	return &DisputeHandler{
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+// OpenDispute handles POST /v1/purchase/:purchaseId/dispute
+// @Summary Open a dispute
+// @Description The buyer or a seller of a purchase in progress opens a dispute, such as a transfer the seller says never arrived. The message starts the thread and fileIds attach up to 5 evidence files the caller uploaded. A purchase has at most one active dispute, and the other side is notified.
+// @Tags dispute
+// @Accept json
+// @Produce json
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.DisputeMessageRequest true "Dispute reason"
+// @Success 201 {object} presenter.DisputeResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/dispute [post]
+func (h *DisputeHandler) OpenDispute(c *fiber.Ctx) error {
+	var req dtos.DisputeMessageRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
+		})
+	}
+
+	dispute, err := h.service.OpenDispute(c.Context(), c.Params("purchaseId"), req)
+	if err != nil {
+		return handleError(c, err, "Failed to open dispute")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(dispute)
+}
+
+// GetDispute handles GET /v1/purchase/:purchaseId/dispute
+// @Summary Get the dispute of a purchase
+// @Description Get the latest dispute of a purchase with its message thread and evidence, for the buyer and the sellers of the purchase
+// @Tags dispute
+// @Accept json
+// @Produce json
+// @Param purchaseId path string true "Purchase ID"
+// @Success 200 {object} presenter.DisputeResponse
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/dispute [get]
+func (h *DisputeHandler) GetDispute(c *fiber.Ctx) error {
+	dispute, err := h.service.GetDispute(c.Context(), c.Params("purchaseId"))
+	if err != nil {
+		return handleError(c, err, "Failed to get dispute")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dispute)
+}
+
+// AddDisputeMessage handles POST /v1/purchase/:purchaseId/dispute/messages
+// @Summary Reply to a dispute
+// @Description Add a message with optional evidence files to the active dispute of a purchase. The dispute then awaits the other side, unless it was escalated, and the other side is notified.
+// @Tags dispute
+// @Accept json
+// @Produce json
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.DisputeMessageRequest true "Message"
+// @Success 201 {object} presenter.DisputeMessageResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/dispute/messages [post]
+func (h *DisputeHandler) AddDisputeMessage(c *fiber.Ctx) error {
+	var req dtos.DisputeMessageRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
+		})
+	}
+
+	message, err := h.service.AddDisputeMessage(c.Context(), c.Params("purchaseId"), req)
+	if err != nil {
+		return handleError(c, err, "Failed to add dispute message")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(message)
+}
+
+// EscalateDispute handles POST /v1/purchase/:purchaseId/dispute/escalate
+// @Summary Escalate a dispute
+// @Description The buyer or a seller hands the active dispute of a purchase over to an admin, who can force the purchase to be cancelled or completed
+// @Tags dispute
+// @Accept json
+// @Produce json
+// @Param purchaseId path string true "Purchase ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/dispute/escalate [post]
+func (h *DisputeHandler) EscalateDispute(c *fiber.Ctx) error {
+	if err := h.service.EscalateDispute(c.Context(), c.Params("purchaseId")); err != nil {
+		return handleError(c, err, "Failed to escalate dispute")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Dispute escalated successfully",
+	})
+}
+
+// ListDisputes handles GET /v1/admin/disputes
+// @Summary List disputes
+// @Description Admins get a paginated list of all disputes, newest first, optionally only those in one status
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param status query string false "Dispute status (open, awaiting_seller, awaiting_buyer, escalated, resolved)"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} presenter.ListDisputesResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/admin/disputes [get]
+func (h *DisputeHandler) ListDisputes(c *fiber.Ctx) error {
+	disputes, err := h.service.ListDisputes(c.Context(), c.Query("status"), c.QueryInt("page", 1), c.QueryInt("limit", 10))
+	if err != nil {
+		return handleError(c, err, "Failed to get disputes")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(disputes)
+}
+
+// GetDisputeByID handles GET /v1/admin/disputes/:disputeId
+// @Summary Get dispute
+// @Description Admins get any dispute with its message thread and evidence
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param disputeId path string true "Dispute ID"
+// @Success 200 {object} presenter.DisputeResponse
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/admin/disputes/{disputeId} [get]
+func (h *DisputeHandler) GetDisputeByID(c *fiber.Ctx) error {
+	dispute, err := h.service.GetDisputeByID(c.Context(), c.Params("disputeId"))
+	if err != nil {
+		return handleError(c, err, "Failed to get dispute")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dispute)
+}
+
+// ResolveDispute handles POST /v1/admin/disputes/:disputeId/resolve
+// @Summary Resolve dispute
+// @Description Admins close a dispute with a note. Outcome cancel or complete forces the purchase into that status from any status it is not final in; none leaves the purchase as it is. The buyer and sellers are notified.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param disputeId path string true "Dispute ID"
+// @Param request body dtos.ResolveDisputeRequest true "Resolution"
+// @Success 200 {object} presenter.DisputeResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/admin/disputes/{disputeId}/resolve [post]
+func (h *DisputeHandler) ResolveDispute(c *fiber.Ctx) error {
+	var req dtos.ResolveDisputeRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
+		})
+	}
+
+	dispute, err := h.service.ResolveDispute(c.Context(), c.Params("disputeId"), req)
+	if err != nil {
+		return handleError(c, err, "Failed to resolve dispute")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(dispute)
+}
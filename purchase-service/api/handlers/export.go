@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"log"
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/export"
+	"purchase-service/pkg/purchase"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// parseExportFilter reads the query parameters shared by the export endpoints.
+// It returns the message to send back when a parameter is invalid.
+func parseExportFilter(c *fiber.Ctx) (dtos.ExportPurchasesFilter, string) {
+	filter := dtos.ExportPurchasesFilter{
+		Format: c.Query("format", export.FormatCSV),
+		Status: c.Query("status"),
+	}
+
+	if !export.IsValidFormat(filter.Format) {
+		return filter, "Invalid format, must be csv or xlsx"
+	}
+	if filter.Status != "" && !purchase.IsValidStatus(filter.Status) {
+		return filter, "Invalid status filter"
+	}
+
+	var err error
+	if filter.From, err = parseDateQuery(c.Query("from"), false); err != nil {
+		return filter, "Invalid from date"
+	}
+	if filter.To, err = parseDateQuery(c.Query("to"), true); err != nil {
+		return filter, "Invalid to date"
+	}
+	return filter, ""
+}
+
+// sendExport streams a prepared export as a file download. Rows are written
+// while they are read from the database, so the status is already sent when
+// the export starts; a failure midway only cuts the file short and is logged.
+func sendExport(c *fiber.Ctx, name, format string, write purchase.ExportFunc) error {
+	filename := fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102"), format)
+	c.Set(fiber.HeaderContentType, export.ContentType(format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Status(fiber.StatusOK)
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		if err := write(context.Background(), w); err != nil {
+			log.Printf("failed to stream %s: %v", filename, err)
+		}
+	})
+	return nil
+}
@@ -1,8 +1,12 @@
 package handlers
 
 import (
+	"errors"
+	"fmt"
 	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/money"
 	"purchase-service/pkg/purchase"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -26,10 +30,15 @@ func NewPurchaseHandler(service purchase.Service) *PurchaseHandler {
 // @Tags purchase
 // @Accept json
 // @Produce json
+// @Param Idempotency-Key header string false "Unique key to safely retry the request; the original response is replayed"
 // @Param request body dtos.CreatePurchaseRequest true "Purchase request"
 // @Success 201 {object} presenter.PurchaseResponse
 // @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
 // @Router /v1/purchase [post]
 func (h *PurchaseHandler) CreatePurchase(c *fiber.Ctx) error {
 	var req dtos.CreatePurchaseRequest
@@ -54,7 +63,7 @@ func (h *PurchaseHandler) CreatePurchase(c *fiber.Ctx) error {
 	}
 
 	// Additional validation for contact details
-	if err := h.validateContactDetails(req.SenderContactType, req.SenderContactDetail); err != nil {
+	if err := validateContactDetails(h.validator, req.SenderContactType, req.SenderContactDetail); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -63,9 +72,7 @@ func (h *PurchaseHandler) CreatePurchase(c *fiber.Ctx) error {
 	// Create purchase
 	purchase, err := h.service.CreatePurchase(c.Context(), req)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create purchase",
-		})
+		return handleError(c, err, "Failed to create purchase")
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(purchase)
@@ -73,14 +80,19 @@ func (h *PurchaseHandler) CreatePurchase(c *fiber.Ctx) error {
 
 // UploadPaymentProof handles POST /v1/purchase/:purchaseId
 // @Summary Upload payment proof for a purchase
-// @Description Customer can upload their payment proof photo here. After payment, decreases the real product quantity.
+// @Description Customer can upload their payment proof photo here. Each proof is for the transfer to one seller; sellerId is required when the purchase has several sellers. Stock reserved at checkout is committed once the seller confirms the payment.
 // @Tags purchase
 // @Accept json
 // @Produce json
 // @Param purchaseId path string true "Purchase ID"
+// @Param Idempotency-Key header string false "Unique key to safely retry the request; the original response is replayed"
 // @Param request body dtos.PaymentProofRequest true "Payment proof request"
 // @Success 201 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 422 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /v1/purchase/{purchaseId} [post]
 func (h *PurchaseHandler) UploadPaymentProof(c *fiber.Ctx) error {
@@ -114,9 +126,7 @@ func (h *PurchaseHandler) UploadPaymentProof(c *fiber.Ctx) error {
 
 	// Upload payment proof
 	if err := h.service.UploadPaymentProof(c.Context(), purchaseID, req); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to upload payment proof",
-		})
+		return handleError(c, err, "Failed to upload payment proof")
 	}
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
@@ -133,6 +143,7 @@ func (h *PurchaseHandler) UploadPaymentProof(c *fiber.Ctx) error {
 // @Param purchaseId path string true "Purchase ID"
 // @Success 200 {object} presenter.GetPurchaseResponse
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /v1/purchase/{purchaseId} [get]
@@ -147,74 +158,409 @@ func (h *PurchaseHandler) GetPurchaseByID(c *fiber.Ctx) error {
 	// Get purchase
 	purchase, err := h.service.GetPurchaseByID(c.Context(), purchaseID)
 	if err != nil {
-		if err.Error() == "purchase not found" || err.Error() == "invalid purchase ID format" {
-			return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-				"error": "Purchase not found",
+		return handleError(c, err, "Failed to get purchase")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(purchase)
+}
+
+// GetInvoice handles GET /v1/purchase/:purchaseId/invoice
+// @Summary Get purchase invoice
+// @Description Download the invoices of a purchase as a PDF, one page per seller with its own sequential invoice number. The buyer gets every seller's invoice, a seller only their own.
+// @Tags purchase
+// @Produce application/pdf
+// @Param purchaseId path string true "Purchase ID"
+// @Success 200 {file} file
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/invoice [get]
+func (h *PurchaseHandler) GetInvoice(c *fiber.Ctx) error {
+	purchaseID := c.Params("purchaseId")
+
+	invoice, err := h.service.GetInvoice(c.Context(), purchaseID)
+	if err != nil {
+		return handleError(c, err, "Failed to generate invoice")
+	}
+
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`inline; filename="invoice-%s.pdf"`, purchaseID))
+	return c.Status(fiber.StatusOK).Send(invoice)
+}
+
+// CancelPurchase handles POST /v1/purchase/:purchaseId/cancel
+// @Summary Cancel a purchase
+// @Description Customer can cancel a purchase until it is paid. The reserved stock is released and the sellers are notified. When some sellers already confirmed their payment, only the payments to the other sellers are cancelled and the purchase becomes confirmed.
+// @Tags purchase
+// @Accept json
+// @Produce json
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.CancelPurchaseRequest false "Cancel purchase request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/cancel [post]
+func (h *PurchaseHandler) CancelPurchase(c *fiber.Ctx) error {
+	var req dtos.CancelPurchaseRequest
+
+	// The reason is optional, so an empty body is allowed
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
 			})
 		}
-		if err.Error() == "unauthorized: purchase does not belong to user" {
-			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-				"error": "Access denied",
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
+		})
+	}
+
+	if err := h.service.CancelPurchase(c.Context(), c.Params("purchaseId"), req.Reason); err != nil {
+		return handleError(c, err, "Failed to cancel purchase")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Purchase cancelled successfully",
+	})
+}
+
+// RequestRefund handles POST /v1/purchase/:purchaseId/refund
+// @Summary Request a refund
+// @Description Customer can ask for their money back once the payment is confirmed. The sellers are notified and approve or reject the request. A rejected refund cannot be requested again.
+// @Tags purchase
+// @Accept json
+// @Produce json
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.RefundRequest true "Refund request"
+// @Success 201 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/refund [post]
+func (h *PurchaseHandler) RequestRefund(c *fiber.Ctx) error {
+	var req dtos.RefundRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
+		})
+	}
+
+	if err := h.service.RequestRefund(c.Context(), c.Params("purchaseId"), req.Reason); err != nil {
+		return handleError(c, err, "Failed to request refund")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Refund requested successfully",
+	})
+}
+
+// ConfirmReceipt handles POST /v1/purchase/:purchaseId/received
+// @Summary Confirm receipt of a shipment
+// @Description Customer confirms they received the parcel of one seller, who is notified. sellerId is required when the purchase has several sellers. The purchase is completed once every parcel is received. A purchase checked out without a shipping address has no parcels and is completed right away, with sellerId left out.
+// @Tags purchase
+// @Accept json
+// @Produce json
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.ConfirmReceiptRequest false "Confirm receipt request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/received [post]
+func (h *PurchaseHandler) ConfirmReceipt(c *fiber.Ctx) error {
+	var req dtos.ConfirmReceiptRequest
+
+	// The seller is optional, so an empty body is allowed
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
 			})
 		}
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get purchase",
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
 		})
 	}
 
-	return c.Status(fiber.StatusOK).JSON(purchase)
+	if err := h.service.ConfirmReceipt(c.Context(), c.Params("purchaseId"), req); err != nil {
+		return handleError(c, err, "Failed to confirm receipt")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Receipt confirmed successfully",
+	})
 }
 
 // ListPurchases handles GET /v1/purchase
 // @Summary List user's purchases
-// @Description Get a paginated list of user's purchases
+// @Description Get the user's purchases, newest first. Pass the returned nextCursor to get the following page.
 // @Tags purchase
 // @Accept json
 // @Produce json
-// @Param page query int false "Page number" default(1)
+// @Param cursor query string false "Cursor returned by the previous page"
 // @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by status (pending_payment, proof_uploaded, confirmed, completed, cancelled, expired, refund_requested, refunded)"
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC3339 or YYYY-MM-DD, a date includes the whole day)"
+// @Param sellerId query string false "Only purchases containing items from this seller"
+// @Param productId query string false "Only purchases containing this product"
+// @Param minTotal query number false "Minimum total price"
+// @Param maxTotal query number false "Maximum total price"
 // @Success 200 {object} presenter.ListPurchasesResponse
 // @Failure 400 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /v1/purchase [get]
 func (h *PurchaseHandler) ListPurchases(c *fiber.Ctx) error {
-	// Parse query parameters
-	page := c.QueryInt("page", 1)
-	limit := c.QueryInt("limit", 10)
+	filter := dtos.ListPurchasesFilter{
+		Cursor:    c.Query("cursor"),
+		Limit:     c.QueryInt("limit", 10),
+		Status:    c.Query("status"),
+		SellerID:  c.Query("sellerId"),
+		ProductID: c.Query("productId"),
+	}
+
+	// Validate page size
+	if filter.Limit < 1 || filter.Limit > 100 {
+		filter.Limit = 10
+	}
+
+	// Validate status filter
+	if filter.Status != "" && !purchase.IsValidStatus(filter.Status) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid status filter",
+		})
+	}
+
+	if filter.SellerID != "" {
+		if err := h.validator.Var(filter.SellerID, "uuid"); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid seller ID",
+			})
+		}
+	}
+
+	// Validate date range
+	var err error
+	if filter.From, err = parseDateQuery(c.Query("from"), false); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid from date",
+		})
+	}
+	if filter.To, err = parseDateQuery(c.Query("to"), true); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid to date",
+		})
+	}
 
-	// Validate pagination parameters
-	if page < 1 {
-		page = 1
+	// Validate total range
+	if filter.MinTotal, err = parseAmountQuery(c.Query("minTotal")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid minTotal",
+		})
 	}
-	if limit < 1 || limit > 100 {
-		limit = 10
+	if filter.MaxTotal, err = parseAmountQuery(c.Query("maxTotal")); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid maxTotal",
+		})
 	}
 
 	// Get purchases
-	purchases, err := h.service.ListPurchases(c.Context(), page, limit)
+	purchases, err := h.service.ListPurchases(c.Context(), filter)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to get purchases",
+		return handleError(c, err, "Failed to get purchases")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(purchases)
+}
+
+// ExportPurchases handles GET /v1/purchase/export
+// @Summary Export user's purchases
+// @Description Download the user's purchases as CSV or XLSX with one row per purchased item, oldest first. Rows are streamed, so exports of any size are supported.
+// @Tags purchase
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "File format (csv, xlsx)" default(csv)
+// @Param status query string false "Filter by status (pending_payment, proof_uploaded, confirmed, completed, cancelled, expired, refund_requested, refunded)"
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC3339 or YYYY-MM-DD, a date includes the whole day)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/purchase/export [get]
+func (h *PurchaseHandler) ExportPurchases(c *fiber.Ctx) error {
+	filter, message := parseExportFilter(c)
+	if message != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": message,
 		})
 	}
 
+	export, err := h.service.ExportPurchases(c.Context(), filter)
+	if err != nil {
+		return handleError(c, err, "Failed to export purchases")
+	}
+
+	return sendExport(c, "purchases", filter.Format, export)
+}
+
+// ListNotifications handles GET /v1/purchase/notifications
+// @Summary List purchase notifications
+// @Description Get a paginated list of notifications about the user's purchases, such as confirmed or rejected payments
+// @Tags purchase
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} presenter.ListNotificationsResponse
+// @Failure 500 {object} map[string]string
+// @Router /v1/purchase/notifications [get]
+func (h *PurchaseHandler) ListNotifications(c *fiber.Ctx) error {
+	notifications, err := h.service.ListNotifications(c.Context(), c.QueryInt("page", 1), c.QueryInt("limit", 10))
+	if err != nil {
+		return handleError(c, err, "Failed to get notifications")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(notifications)
+}
+
+// GetExistingPurchaseIDs handles POST /purchase/batch (internal)
+func (h *PurchaseHandler) GetExistingPurchaseIDs(c *fiber.Ctx) error {
+	var req dtos.PurchaseIDsRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
+		})
+	}
+
+	purchases, err := h.service.GetExistingPurchaseIDs(c.Context(), req)
+	if err != nil {
+		return handleError(c, err, "Failed to get purchases")
+	}
+
 	return c.Status(fiber.StatusOK).JSON(purchases)
 }
 
 // validateContactDetails validates email or phone based on contact type
-func (h *PurchaseHandler) validateContactDetails(contactType, contactDetail string) error {
+func validateContactDetails(v *validator.Validate, contactType, contactDetail string) error {
 	if contactType == "email" {
-		if err := h.validator.Var(contactDetail, "email"); err != nil {
+		if err := v.Var(contactDetail, "email"); err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, "Invalid email format")
 		}
 	} else if contactType == "phone" {
-		if err := h.validator.Var(contactDetail, "min=10,max=15"); err != nil {
+		if err := v.Var(contactDetail, "min=10,max=15"); err != nil {
 			return fiber.NewError(fiber.StatusBadRequest, "Invalid phone number format")
 		}
 	}
 	return nil
 }
 
+// parseDateQuery parses an RFC3339 timestamp or a YYYY-MM-DD date. A bare date
+// used as an exclusive upper bound is moved to the next day so the whole day
+// is included.
+func parseDateQuery(value string, endOfRange bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse(time.DateOnly, value)
+	if err != nil {
+		return nil, err
+	}
+	if endOfRange {
+		t = t.AddDate(0, 0, 1)
+	}
+	return &t, nil
+}
+
+// parseAmountQuery parses an optional money amount
+func parseAmountQuery(value string) (*money.Amount, error) {
+	if value == "" {
+		return nil, nil
+	}
+	amount, err := money.Parse(value)
+	if err != nil {
+		return nil, err
+	}
+	return &amount, nil
+}
+
+// handleError maps service errors to HTTP status codes. Server-side failures
+// are reported with the fallback message so internal details do not leak.
+func handleError(c *fiber.Ctx, err error, fallback string) error {
+	switch {
+	case errors.Is(err, purchase.ErrUnauthenticated):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Unauthorized"})
+	case errors.Is(err, purchase.ErrNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, purchase.ErrForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, purchase.ErrInvalidInput):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, purchase.ErrConflict):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, purchase.ErrUpstreamUnavailable):
+		return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": fallback})
+	}
+}
+
 // getValidationMessage returns a user-friendly validation message
 func getValidationMessage(err validator.FieldError) string {
 	switch err.Tag() {
@@ -226,6 +572,8 @@ func getValidationMessage(err validator.FieldError) string {
 		return "Value is too long"
 	case "email":
 		return "Invalid email format"
+	case "uuid":
+		return "Invalid ID format"
 	case "oneof":
 		return "Invalid value, must be one of: " + err.Param()
 	case "dive":
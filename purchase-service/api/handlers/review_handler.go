@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/purchase"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type ReviewHandler struct {
+	service   purchase.Service
+	validator *validator.Validate
+}
+
+func NewReviewHandler(service purchase.Service) *ReviewHandler {
+	return &ReviewHandler{
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+// CreateReview handles POST /v1/purchase/:purchaseId/reviews
+// @Summary Review a purchased product
+// @Description Customer rates a product of one of their completed purchases with 1 to 5 stars, an optional comment and up to 5 images they uploaded. Each product can be reviewed once per purchase, and the seller is notified.
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.CreateReviewRequest true "Review"
+// @Success 201 {object} presenter.ReviewResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/purchase/{purchaseId}/reviews [post]
+func (h *ReviewHandler) CreateReview(c *fiber.Ctx) error {
+	var req dtos.CreateReviewRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
+		})
+	}
+
+	review, err := h.service.CreateReview(c.Context(), c.Params("purchaseId"), req)
+	if err != nil {
+		return handleError(c, err, "Failed to create review")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(review)
+}
+
+// ListProductReviews handles GET /v1/reviews/product/:productId
+// @Summary List product reviews
+// @Description Get a paginated list of the reviews of a product, newest first
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param productId path string true "Product ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} presenter.ListReviewsResponse
+// @Failure 500 {object} map[string]string
+// @Router /v1/reviews/product/{productId} [get]
+func (h *ReviewHandler) ListProductReviews(c *fiber.Ctx) error {
+	reviews, err := h.service.ListProductReviews(c.Context(), c.Params("productId"), c.QueryInt("page", 1), c.QueryInt("limit", 10))
+	if err != nil {
+		return handleError(c, err, "Failed to get reviews")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(reviews)
+}
+
+// ListSellerReviews handles GET /v1/reviews/seller/:sellerId
+// @Summary List seller reviews
+// @Description Get a paginated list of the reviews of all of a seller's products, newest first, along with the seller's average rating
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param sellerId path string true "Seller ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} presenter.ListSellerReviewsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/reviews/seller/{sellerId} [get]
+func (h *ReviewHandler) ListSellerReviews(c *fiber.Ctx) error {
+	reviews, err := h.service.ListSellerReviews(c.Context(), c.Params("sellerId"), c.QueryInt("page", 1), c.QueryInt("limit", 10))
+	if err != nil {
+		return handleError(c, err, "Failed to get reviews")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(reviews)
+}
+
+// GetSellerRating handles GET /v1/reviews/seller/:sellerId/rating
+// @Summary Get seller rating
+// @Description Get the average rating of a seller over all their reviews, rounded to two decimals. Sellers without reviews have an average of 0.
+// @Tags review
+// @Accept json
+// @Produce json
+// @Param sellerId path string true "Seller ID"
+// @Success 200 {object} presenter.SellerRatingResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/reviews/seller/{sellerId}/rating [get]
+func (h *ReviewHandler) GetSellerRating(c *fiber.Ctx) error {
+	rating, err := h.service.GetSellerRating(c.Context(), c.Params("sellerId"))
+	if err != nil {
+		return handleError(c, err, "Failed to get seller rating")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(rating)
+}
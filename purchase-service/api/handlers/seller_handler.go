@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/purchase"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type SellerOrderHandler struct {
+	service   purchase.Service
+	validator *validator.Validate
+}
+
+func NewSellerOrderHandler(service purchase.Service) *SellerOrderHandler {
+	return &SellerOrderHandler{
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+// ListOrders handles GET /v1/seller/orders
+// @Summary List seller's orders
+// @Description Get a paginated list of purchases containing the seller's products
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Param status query string false "Filter by status (pending_payment, proof_uploaded, confirmed, completed, cancelled, expired, refund_requested, refunded)"
+// @Success 200 {object} presenter.ListSellerOrdersResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/orders [get]
+func (h *SellerOrderHandler) ListOrders(c *fiber.Ctx) error {
+	status := c.Query("status")
+	if status != "" && !purchase.IsValidStatus(status) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid status filter",
+		})
+	}
+
+	orders, err := h.service.ListSellerOrders(c.Context(), c.QueryInt("page", 1), c.QueryInt("limit", 10), status)
+	if err != nil {
+		return handleError(c, err, "Failed to get orders")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(orders)
+}
+
+// ExportOrders handles GET /v1/seller/orders/export
+// @Summary Export seller's orders
+// @Description Download the seller's items across all purchases as CSV or XLSX, oldest first, with the state of the buyer's payment to the seller for reconciling transfers. Rows are streamed, so exports of any size are supported.
+// @Tags seller
+// @Produce text/csv
+// @Produce application/vnd.openxmlformats-officedocument.spreadsheetml.sheet
+// @Param format query string false "File format (csv, xlsx)" default(csv)
+// @Param status query string false "Filter by status (pending_payment, proof_uploaded, confirmed, completed, cancelled, expired, refund_requested, refunded)"
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD)"
+// @Param to query string false "Created before (RFC3339 or YYYY-MM-DD, a date includes the whole day)"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/orders/export [get]
+func (h *SellerOrderHandler) ExportOrders(c *fiber.Ctx) error {
+	filter, message := parseExportFilter(c)
+	if message != "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": message,
+		})
+	}
+
+	export, err := h.service.ExportSellerOrders(c.Context(), filter)
+	if err != nil {
+		return handleError(c, err, "Failed to export orders")
+	}
+
+	return sendExport(c, "orders", filter.Format, export)
+}
+
+// GetAnalytics handles GET /v1/seller/analytics
+// @Summary Get seller's sales analytics
+// @Description Revenue per day, week or month, top products and categories, average order value and the conversion from created to confirmed purchases. Sales are the seller's items whose payment they confirmed, excluding refunded purchases, attributed to the date the purchase was created.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Param from query string false "Created at or after (RFC3339 or YYYY-MM-DD), defaults to 30 days before to"
+// @Param to query string false "Created before (RFC3339 or YYYY-MM-DD, a date includes the whole day), defaults to now"
+// @Param interval query string false "Revenue interval (day, week, month)" default(day)
+// @Param currency query string false "Currency of the purchases to include" default(IDR)
+// @Param limit query int false "Number of top products and categories" default(5)
+// @Success 200 {object} presenter.SellerAnalyticsResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/analytics [get]
+func (h *SellerOrderHandler) GetAnalytics(c *fiber.Ctx) error {
+	filter := dtos.SellerAnalyticsFilter{
+		Interval: c.Query("interval", purchase.IntervalDay),
+		Currency: c.Query("currency"),
+		Limit:    c.QueryInt("limit", 5),
+	}
+
+	if !purchase.IsValidInterval(filter.Interval) {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid interval, must be day, week or month",
+		})
+	}
+
+	// Validate date range
+	var err error
+	if filter.From, err = parseDateQuery(c.Query("from"), false); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid from date",
+		})
+	}
+	if filter.To, err = parseDateQuery(c.Query("to"), true); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid to date",
+		})
+	}
+
+	analytics, err := h.service.GetSellerAnalytics(c.Context(), filter)
+	if err != nil {
+		return handleError(c, err, "Failed to get analytics")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(analytics)
+}
+
+// GetOrder handles GET /v1/seller/orders/:purchaseId
+// @Summary Get seller's order
+// @Description Get the seller's items of a purchase together with the buyer's payment proof files
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Param purchaseId path string true "Purchase ID"
+// @Success 200 {object} presenter.SellerOrderResponse
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/orders/{purchaseId} [get]
+func (h *SellerOrderHandler) GetOrder(c *fiber.Ctx) error {
+	order, err := h.service.GetSellerOrder(c.Context(), c.Params("purchaseId"))
+	if err != nil {
+		return handleError(c, err, "Failed to get order")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(order)
+}
+
+// ConfirmPayment handles POST /v1/seller/orders/:purchaseId/confirm
+// @Summary Confirm payment
+// @Description Seller confirms the buyer's payment proof for their own items. Their reserved stock is committed and the buyer is notified. The purchase is confirmed once every seller has confirmed.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Param purchaseId path string true "Purchase ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /v1/seller/orders/{purchaseId}/confirm [post]
+func (h *SellerOrderHandler) ConfirmPayment(c *fiber.Ctx) error {
+	if err := h.service.ConfirmPayment(c.Context(), c.Params("purchaseId")); err != nil {
+		return handleError(c, err, "Failed to confirm payment")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Payment confirmed successfully",
+	})
+}
+
+// RejectPayment handles POST /v1/seller/orders/:purchaseId/reject
+// @Summary Reject payment
+// @Description Seller rejects the buyer's payment proof for their own items with a reason. That payment goes back to awaiting payment and the buyer is notified.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.RejectPaymentRequest true "Reject payment request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/orders/{purchaseId}/reject [post]
+func (h *SellerOrderHandler) RejectPayment(c *fiber.Ctx) error {
+	var req dtos.RejectPaymentRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
+		})
+	}
+
+	if err := h.service.RejectPayment(c.Context(), c.Params("purchaseId"), req.Reason); err != nil {
+		return handleError(c, err, "Failed to reject payment")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Payment rejected successfully",
+	})
+}
+
+// ShipOrder handles POST /v1/seller/orders/:purchaseId/ship
+// @Summary Ship order
+// @Description Seller marks their items of a confirmed purchase as shipped with the courier's tracking number. The buyer is notified.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.ShipOrderRequest true "Ship order request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/orders/{purchaseId}/ship [post]
+func (h *SellerOrderHandler) ShipOrder(c *fiber.Ctx) error {
+	var req dtos.ShipOrderRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
+		})
+	}
+
+	if err := h.service.ShipOrder(c.Context(), c.Params("purchaseId"), req); err != nil {
+		return handleError(c, err, "Failed to ship order")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Order shipped successfully",
+	})
+}
+
+// ApproveRefund handles POST /v1/seller/orders/:purchaseId/refund/approve
+// @Summary Approve refund
+// @Description Seller approves the buyer's refund request for their part of the purchase and the buyer is notified. The purchase is marked refunded once every seller that was paid approved it.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Param purchaseId path string true "Purchase ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/orders/{purchaseId}/refund/approve [post]
+func (h *SellerOrderHandler) ApproveRefund(c *fiber.Ctx) error {
+	if err := h.service.ApproveRefund(c.Context(), c.Params("purchaseId")); err != nil {
+		return handleError(c, err, "Failed to approve refund")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Refund approved successfully",
+	})
+}
+
+// RejectRefund handles POST /v1/seller/orders/:purchaseId/refund/reject
+// @Summary Reject refund
+// @Description Seller rejects the buyer's refund request for their part of the purchase with a reason and the buyer is notified. Once every seller that was paid has decided, the purchase goes back to confirmed with the sellers' reasons.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Param purchaseId path string true "Purchase ID"
+// @Param request body dtos.RejectRefundRequest true "Reject refund request"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/orders/{purchaseId}/refund/reject [post]
+func (h *SellerOrderHandler) RejectRefund(c *fiber.Ctx) error {
+	var req dtos.RejectRefundRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
+		})
+	}
+
+	if err := h.service.RejectRefund(c.Context(), c.Params("purchaseId"), req.Reason); err != nil {
+		return handleError(c, err, "Failed to reject refund")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Refund rejected successfully",
+	})
+}
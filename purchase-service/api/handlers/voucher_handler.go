@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/purchase"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/gofiber/fiber/v2"
+)
+
+type VoucherHandler struct {
+	service   purchase.Service
+	validator *validator.Validate
+}
+
+func NewVoucherHandler(service purchase.Service) *VoucherHandler {
+	return &VoucherHandler{
+		service:   service,
+		validator: validator.New(),
+	}
+}
+
+// CreateVoucher handles POST /v1/seller/vouchers
+// @Summary Create voucher
+// @Description Create a discount code for the seller's items, either a percentage off (optionally capped by maxDiscount) or a fixed amount off. Codes are case-insensitive and unique across all sellers.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Param request body dtos.CreateVoucherRequest true "Voucher"
+// @Success 201 {object} presenter.VoucherResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/vouchers [post]
+func (h *VoucherHandler) CreateVoucher(c *fiber.Ctx) error {
+	var req dtos.CreateVoucherRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
+		})
+	}
+
+	voucher, err := h.service.CreateVoucher(c.Context(), req)
+	if err != nil {
+		return handleError(c, err, "Failed to create voucher")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(voucher)
+}
+
+// ListVouchers handles GET /v1/seller/vouchers
+// @Summary List seller's vouchers
+// @Description Get a paginated list of the seller's vouchers with how often each was used, newest first
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Items per page" default(10)
+// @Success 200 {object} presenter.ListVouchersResponse
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/vouchers [get]
+func (h *VoucherHandler) ListVouchers(c *fiber.Ctx) error {
+	vouchers, err := h.service.ListSellerVouchers(c.Context(), c.QueryInt("page", 1), c.QueryInt("limit", 10))
+	if err != nil {
+		return handleError(c, err, "Failed to get vouchers")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(vouchers)
+}
+
+// DisableVoucher handles POST /v1/seller/vouchers/:voucherId/disable
+// @Summary Disable voucher
+// @Description Stop a voucher from being redeemed. Purchases that already used it keep their discount.
+// @Tags seller
+// @Accept json
+// @Produce json
+// @Param voucherId path string true "Voucher ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/seller/vouchers/{voucherId}/disable [post]
+func (h *VoucherHandler) DisableVoucher(c *fiber.Ctx) error {
+	if err := h.service.DisableVoucher(c.Context(), c.Params("voucherId")); err != nil {
+		return handleError(c, err, "Failed to disable voucher")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Voucher disabled successfully",
+	})
+}
+
+// CreatePlatformVoucher handles POST /v1/admin/vouchers
+// @Summary Create platform voucher
+// @Description Admins create a discount code for whole purchases, either a percentage off (optionally capped by maxDiscount) or a fixed amount off, split across the sellers in proportion to their items. Codes are case-insensitive and unique across all vouchers.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param request body dtos.CreateVoucherRequest true "Voucher"
+// @Success 201 {object} presenter.VoucherResponse
+// @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/admin/vouchers [post]
+func (h *VoucherHandler) CreatePlatformVoucher(c *fiber.Ctx) error {
+	var req dtos.CreateVoucherRequest
+
+	// Parse request body
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	// Validate request
+	if err := h.validator.Struct(req); err != nil {
+		validationErrors := make(map[string]string)
+		for _, err := range err.(validator.ValidationErrors) {
+			validationErrors[err.Field()] = getValidationMessage(err)
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error":   "Validation failed",
+			"details": validationErrors,
+		})
+	}
+
+	voucher, err := h.service.CreatePlatformVoucher(c.Context(), req)
+	if err != nil {
+		return handleError(c, err, "Failed to create voucher")
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(voucher)
+}
+
+// DisablePlatformVoucher handles POST /v1/admin/vouchers/:voucherId/disable
+// @Summary Disable platform voucher
+// @Description Admins stop a platform voucher from being redeemed. Purchases that already used it keep their discount; sellers' vouchers can only be disabled by their seller.
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param voucherId path string true "Voucher ID"
+// @Success 200 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /v1/admin/vouchers/{voucherId}/disable [post]
+func (h *VoucherHandler) DisablePlatformVoucher(c *fiber.Ctx) error {
+	if err := h.service.DisablePlatformVoucher(c.Context(), c.Params("voucherId")); err != nil {
+		return handleError(c, err, "Failed to disable voucher")
+	}
+
+	return c.Status(fiber.StatusOK).JSON(fiber.Map{
+		"message": "Voucher disabled successfully",
+	})
+}
@@ -0,0 +1,27 @@
+package middleware
+
+import (
+	"purchase-service/api/presenter"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/spf13/viper"
+)
+
+// AdminOnly middleware restricts a route to the users listed in the
+// comma-separated ADMIN_USER_IDS. It must run after GatewayTrust, which sets
+// the user context.
+func AdminOnly(config *viper.Viper) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, _ := c.Locals("user_id").(string)
+
+		for _, adminID := range strings.Split(config.GetString("ADMIN_USER_IDS"), ",") {
+			if adminID = strings.TrimSpace(adminID); adminID != "" && adminID == userID {
+				return c.Next()
+			}
+		}
+
+		return c.Status(fiber.StatusForbidden).
+			JSON(presenter.ErrorResponse("admin access required"))
+	}
+}
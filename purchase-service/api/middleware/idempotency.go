@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"errors"
+	"log"
+	"purchase-service/api/presenter"
+	"purchase-service/pkg/idempotency"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Idempotency makes a route safe to retry when the client sends an
+// Idempotency-Key header: the first response is stored and replayed for
+// retries with the same key and payload. Requests without the header pass
+// through unchanged. Must run after GatewayTrust, which sets the user.
+func Idempotency(service idempotency.Service) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get("Idempotency-Key")
+		if key == "" {
+			return c.Next()
+		}
+		if len(key) > 255 {
+			return c.Status(fiber.StatusBadRequest).
+				JSON(presenter.ErrorResponse("Idempotency-Key must be at most 255 characters"))
+		}
+
+		userID, _ := c.Locals("user_id").(string)
+		requestHash := idempotency.HashRequest(c.Method(), c.Path(), c.Body())
+
+		record, err := service.Begin(c.Context(), userID, key, requestHash)
+		switch {
+		case errors.Is(err, idempotency.ErrKeyReused):
+			return c.Status(fiber.StatusUnprocessableEntity).
+				JSON(presenter.ErrorResponse(err.Error()))
+		case errors.Is(err, idempotency.ErrRequestInProgress):
+			return c.Status(fiber.StatusConflict).
+				JSON(presenter.ErrorResponse(err.Error()))
+		case err != nil:
+			return c.Status(fiber.StatusInternalServerError).
+				JSON(presenter.ErrorResponse("failed to process idempotency key"))
+		}
+
+		// Replay the stored response of the original request
+		if record.Completed() {
+			c.Set("Idempotent-Replayed", "true")
+			c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+			return c.Status(record.StatusCode).Send(record.ResponseBody)
+		}
+
+		if err := c.Next(); err != nil {
+			if abandonErr := service.Abandon(c.Context(), record); abandonErr != nil {
+				log.Printf("%v", abandonErr)
+			}
+			return err
+		}
+
+		// Server errors are not final, so let the client retry them for real
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			if err := service.Abandon(c.Context(), record); err != nil {
+				log.Printf("%v", err)
+			}
+			return nil
+		}
+
+		if err := service.Complete(c.Context(), record, status, c.Response().Body()); err != nil {
+			log.Printf("%v", err)
+		}
+		return nil
+	}
+}
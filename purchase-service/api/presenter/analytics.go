@@ -0,0 +1,51 @@
+package presenter
+
+import "purchase-service/pkg/money"
+
+// SellerAnalyticsResponse summarizes a seller's sales over a date range. Sales
+// are items whose payment the seller confirmed, excluding refunded purchases.
+type SellerAnalyticsResponse struct {
+	From              string             `json:"from"`
+	To                string             `json:"to"`
+	Interval          string             `json:"interval"`
+	Currency          string             `json:"currency"`
+	Revenue           money.Amount       `json:"revenue"`
+	Orders            int                `json:"orders"`
+	ItemsSold         int                `json:"itemsSold"`
+	AverageOrderValue money.Amount       `json:"averageOrderValue"`
+	Conversion        ConversionResponse `json:"conversion"`
+	RevenueByPeriod   []RevenuePeriod    `json:"revenueByPeriod"`
+	TopProducts       []TopProduct       `json:"topProducts"`
+	TopCategories     []TopCategory      `json:"topCategories"`
+}
+
+// RevenuePeriod is the sales of one day, week or month, named by its first day
+type RevenuePeriod struct {
+	Period    string       `json:"period"`
+	Revenue   money.Amount `json:"revenue"`
+	Orders    int          `json:"orders"`
+	ItemsSold int          `json:"itemsSold"`
+}
+
+// TopProduct and TopCategory rank the seller's items by revenue, after voucher
+// discounts like the headline revenue
+type TopProduct struct {
+	ProductID string       `json:"productId"`
+	Name      string       `json:"name"`
+	QtySold   int          `json:"qtySold"`
+	Revenue   money.Amount `json:"revenue"`
+}
+
+type TopCategory struct {
+	Category string       `json:"category"`
+	QtySold  int          `json:"qtySold"`
+	Revenue  money.Amount `json:"revenue"`
+}
+
+// ConversionResponse compares the purchases created with the seller's items
+// to those whose payment the seller confirmed
+type ConversionResponse struct {
+	Created   int     `json:"created"`
+	Confirmed int     `json:"confirmed"`
+	Rate      float64 `json:"rate"`
+}
@@ -0,0 +1,30 @@
+package presenter
+
+import "purchase-service/pkg/money"
+
+// CartResponse is the buyer's cart, priced with the current product details
+type CartResponse struct {
+	Items      []CartItemResponse `json:"items"`
+	TotalPrice money.Amount       `json:"totalPrice"`         // Of the products that still exist
+	Currency   string             `json:"currency,omitempty"` // Empty while the cart is empty
+	UpdatedAt  string             `json:"updatedAt,omitempty"`
+}
+
+// CartItemResponse is a product in the cart. Price is the current unit price
+// and SavedPrice the one the buyer last saw; checkout fails while they differ.
+// Available is false when the product was deleted or lacks the stock for Qty.
+type CartItemResponse struct {
+	ProductID        string       `json:"productId"`
+	SellerID         string       `json:"sellerId"`
+	Name             string       `json:"name"`
+	Category         string       `json:"category"`
+	SKU              string       `json:"sku"`
+	FileThumbnailURI string       `json:"fileThumbnailUri"`
+	Qty              int          `json:"qty"`
+	Price            money.Amount `json:"price"`
+	SavedPrice       money.Amount `json:"savedPrice"`
+	PriceChanged     bool         `json:"priceChanged"`
+	LineTotal        money.Amount `json:"lineTotal"`
+	Stock            int          `json:"stock"`
+	Available        bool         `json:"available"`
+}
@@ -0,0 +1,40 @@
+package presenter
+
+// DisputeResponse is a dispute of a purchase. Messages are left out of lists.
+type DisputeResponse struct {
+	DisputeID      string                   `json:"disputeId"`
+	PurchaseID     string                   `json:"purchaseId"`
+	OpenedBy       string                   `json:"openedBy"`
+	OpenedByRole   string                   `json:"openedByRole"`
+	Status         string                   `json:"status"`
+	Outcome        string                   `json:"outcome,omitempty"`
+	ResolutionNote string                   `json:"resolutionNote,omitempty"`
+	ResolvedBy     *string                  `json:"resolvedBy,omitempty"`
+	EscalatedAt    *string                  `json:"escalatedAt,omitempty"`
+	ResolvedAt     *string                  `json:"resolvedAt,omitempty"`
+	Messages       []DisputeMessageResponse `json:"messages,omitempty"`
+	CreatedAt      string                   `json:"createdAt"`
+	UpdatedAt      string                   `json:"updatedAt"`
+}
+
+type DisputeMessageResponse struct {
+	MessageID  string            `json:"messageId"`
+	SenderID   string            `json:"senderId"`
+	SenderRole string            `json:"senderRole"`
+	Message    string            `json:"message"`
+	Evidence   []DisputeEvidence `json:"evidence"`
+	CreatedAt  string            `json:"createdAt"`
+}
+
+type DisputeEvidence struct {
+	FileID           string `json:"fileId"`
+	FileURI          string `json:"fileUri"`
+	FileThumbnailURI string `json:"fileThumbnailUri"`
+}
+
+type ListDisputesResponse struct {
+	Disputes []DisputeResponse `json:"disputes"`
+	Total    int               `json:"total"`
+	Page     int               `json:"page"`
+	Limit    int               `json:"limit"`
+}
@@ -1,19 +1,22 @@
 package presenter
 
+import "purchase-service/pkg/money"
+
 // External service response DTOs
 type ProductResponse struct {
-	ID       string  `json:"productId"`
-	Name     string  `json:"name"`
-	Category string  `json:"category"`
-	Qty      int     `json:"qty"`
-	Price    float64 `json:"price"`
-	SKU      string  `json:"sku"`
-	FileID   string  `json:"fileId"`
-	FileURI  string  `json:"fileUri"`
-	FileThumbnailURI string `json:"fileThumbnailUri"`
-	SellerID string  `json:"sellerId"`
-	CreatedAt string `json:"createdAt"`
-	UpdatedAt string `json:"updatedAt"`
+	ID               string       `json:"productId"`
+	Name             string       `json:"name"`
+	Category         string       `json:"category"`
+	Qty              int          `json:"qty"`
+	Price            money.Amount `json:"price"`
+	Currency         string       `json:"currency"`
+	SKU              string       `json:"sku"`
+	FileID           string       `json:"fileId"`
+	FileURI          string       `json:"fileUri"`
+	FileThumbnailURI string       `json:"fileThumbnailUri"`
+	SellerID         string       `json:"sellerId"`
+	CreatedAt        string       `json:"createdAt"`
+	UpdatedAt        string       `json:"updatedAt"`
 }
 
 type ExternalUserResponse struct {
@@ -23,6 +26,34 @@ type ExternalUserResponse struct {
 	BankAccountNumber string `json:"bankAccountNumber"`
 }
 
+type ExternalUsersResponse struct {
+	Users []ExternalUserResponse `json:"users"`
+}
+
+type ExternalFileResponse struct {
+	ID               string `json:"id"`
+	UserID           string `json:"userId"` // Empty when the uploader is unknown
+	FileURI          string `json:"fileUri"`
+	FileThumbnailURI string `json:"fileThumbnailUri"`
+}
+
+type ExternalFilesResponse struct {
+	Files []ExternalFileResponse `json:"files"`
+}
+
+type ExternalAddressResponse struct {
+	ID            string `json:"id"`
+	UserID        string `json:"userId"`
+	RecipientName string `json:"recipientName"`
+	Phone         string `json:"phone"`
+	AddressLine1  string `json:"addressLine1"`
+	AddressLine2  string `json:"addressLine2"`
+	City          string `json:"city"`
+	Province      string `json:"province"`
+	PostalCode    string `json:"postalCode"`
+	Country       string `json:"country"`
+}
+
 type SellerResponse struct {
 	ID                string `json:"id"`
 	BankAccountName   string `json:"bankAccountName"`
@@ -1,51 +1,104 @@
 package presenter
 
+import "purchase-service/pkg/money"
+
 // Purchase Response DTOs
 type PurchaseResponse struct {
-	PurchaseID     string                `json:"purchaseId"`
-	PurchasedItems []PurchaseItemResponse `json:"purchasedItems"`
-	TotalPrice     float64               `json:"totalPrice"`
-	PaymentDetails []PaymentDetail       `json:"paymentDetails"`
+	PurchaseID      string                 `json:"purchaseId"`
+	Status          string                 `json:"status"`
+	PurchasedItems  []PurchaseItemResponse `json:"purchasedItems"`
+	TotalPrice      money.Amount           `json:"totalPrice"` // After Discount, with ShippingCost
+	Discount        money.Amount           `json:"discount"`
+	VoucherCode     string                 `json:"voucherCode,omitempty"`
+	ShippingCost    money.Amount           `json:"shippingCost"`
+	Currency        string                 `json:"currency"`
+	PaymentDetails  []PaymentDetail        `json:"paymentDetails"`
+	ShippingAddress *ShippingAddress       `json:"shippingAddress,omitempty"`
+	Shipments       []ShipmentResponse     `json:"shipments"`
 }
 
 type GetPurchaseResponse struct {
-	PurchaseID       string                `json:"purchaseId"`
-	UserID           string                `json:"userId"`
-	PaymentProofIds  []string              `json:"paymentProofIds"`
-	PurchasedItems   []PurchaseItemResponse `json:"purchasedItems"`
-	TotalPrice       float64               `json:"totalPrice"`
-	PaymentDetails   []PaymentDetail       `json:"paymentDetails"`
-	SenderInfo       SenderInfo            `json:"senderInfo"`
-	CreatedAt        string                `json:"createdAt"`
-	UpdatedAt        string                `json:"updatedAt"`
+	PurchaseID      string                 `json:"purchaseId"`
+	UserID          string                 `json:"userId"`
+	Status          string                 `json:"status"`
+	StatusHistory   StatusTimestamps       `json:"statusHistory"`
+	StatusReason    string                 `json:"statusReason,omitempty"`
+	PaymentProofIds []string               `json:"paymentProofIds"` // Proofs awaiting review or accepted
+	PaymentProofs   []PaymentProofFile     `json:"paymentProofs"`
+	PurchasedItems  []PurchaseItemResponse `json:"purchasedItems"`
+	TotalPrice      money.Amount           `json:"totalPrice"` // After Discount, with ShippingCost
+	Discount        money.Amount           `json:"discount"`
+	VoucherCode     string                 `json:"voucherCode,omitempty"`
+	ShippingCost    money.Amount           `json:"shippingCost"`
+	Currency        string                 `json:"currency"`
+	PaymentDetails  []PaymentDetail        `json:"paymentDetails"`
+	ShippingAddress *ShippingAddress       `json:"shippingAddress,omitempty"`
+	Shipments       []ShipmentResponse     `json:"shipments"`
+	Refund          *RefundResponse        `json:"refund,omitempty"`
+	SenderInfo      SenderInfo             `json:"senderInfo"`
+	CreatedAt       string                 `json:"createdAt"`
+	UpdatedAt       string                 `json:"updatedAt"`
 }
 
 type ListPurchasesResponse struct {
-	Purchases []GetPurchaseResponse `json:"purchases"`
-	Total     int                   `json:"total"`
-	Page      int                   `json:"page"`
-	Limit     int                   `json:"limit"`
+	Purchases  []GetPurchaseResponse `json:"purchases"`
+	NextCursor string                `json:"nextCursor,omitempty"` // Empty on the last page
+	Limit      int                   `json:"limit"`
 }
 
 type PurchaseItemResponse struct {
-	ProductID         string  `json:"productId"`
-	Name              string  `json:"name"`
-	Category          string  `json:"category"`
-	Qty               int     `json:"qty"`
-	Price             float64 `json:"price"`
-	SKU               string  `json:"sku"`
-	FileID            string  `json:"fileId"`
-	FileURI           string  `json:"fileUri"`
-	FileThumbnailURI  string  `json:"fileThumbnailUri"`
-	CreatedAt         string  `json:"createdAt"`
-	UpdatedAt         string  `json:"updatedAt"`
+	ProductID        string       `json:"productId"`
+	Name             string       `json:"name"`
+	Category         string       `json:"category"`
+	Qty              int          `json:"qty"`
+	Price            money.Amount `json:"price"`
+	LineTotal        money.Amount `json:"lineTotal"`
+	SKU              string       `json:"sku"`
+	FileID           string       `json:"fileId"`
+	FileURI          string       `json:"fileUri"`
+	FileThumbnailURI string       `json:"fileThumbnailUri"`
+	CreatedAt        string       `json:"createdAt"`
+	UpdatedAt        string       `json:"updatedAt"`
 }
 
+// PaymentDetail is where the buyer pays one seller, and how far that payment got
 type PaymentDetail struct {
-	BankAccountName   string  `json:"bankAccountName"`
-	BankAccountHolder string  `json:"bankAccountHolder"`
-	BankAccountNumber string  `json:"bankAccountNumber"`
-	TotalPrice        float64 `json:"totalPrice"`
+	SellerID          string       `json:"sellerId"`
+	BankAccountName   string       `json:"bankAccountName"`
+	BankAccountHolder string       `json:"bankAccountHolder"`
+	BankAccountNumber string       `json:"bankAccountNumber"`
+	TotalPrice        money.Amount `json:"totalPrice"` // Payable to the seller, after Discount and with shipping
+	Discount          money.Amount `json:"discount"`
+	Status            string       `json:"status"`
+	StatusReason      string       `json:"statusReason,omitempty"`
+	ProofUploadedAt   *string      `json:"proofUploadedAt,omitempty"`
+	ConfirmedAt       *string      `json:"confirmedAt,omitempty"`
+	RefundStatus      string       `json:"refundStatus,omitempty"` // Seller's decision on the latest refund request
+	RefundReason      string       `json:"refundReason,omitempty"`
+}
+
+// ShippingAddress is where the purchase is delivered, as it was at checkout
+type ShippingAddress struct {
+	AddressID     string `json:"addressId"`
+	RecipientName string `json:"recipientName"`
+	Phone         string `json:"phone"`
+	AddressLine1  string `json:"addressLine1"`
+	AddressLine2  string `json:"addressLine2,omitempty"`
+	City          string `json:"city"`
+	Province      string `json:"province"`
+	PostalCode    string `json:"postalCode"`
+	Country       string `json:"country"`
+}
+
+// ShipmentResponse is the delivery of one seller's items
+type ShipmentResponse struct {
+	SellerID       string       `json:"sellerId"`
+	Method         string       `json:"method"`
+	Cost           money.Amount `json:"cost"`
+	TrackingNumber string       `json:"trackingNumber,omitempty"`
+	Status         string       `json:"status"`
+	ShippedAt      *string      `json:"shippedAt,omitempty"`
+	ReceivedAt     *string      `json:"receivedAt,omitempty"`
 }
 
 type SenderInfo struct {
@@ -53,3 +106,43 @@ type SenderInfo struct {
 	SenderContactType   string `json:"senderContactType"`
 	SenderContactDetail string `json:"senderContactDetail"`
 }
+
+// StatusTimestamps records when a purchase entered each lifecycle status
+type StatusTimestamps struct {
+	ProofUploadedAt   *string `json:"proofUploadedAt,omitempty"`
+	ConfirmedAt       *string `json:"confirmedAt,omitempty"`
+	CompletedAt       *string `json:"completedAt,omitempty"`
+	CancelledAt       *string `json:"cancelledAt,omitempty"`
+	ExpiredAt         *string `json:"expiredAt,omitempty"`
+	RefundRequestedAt *string `json:"refundRequestedAt,omitempty"`
+	RefundedAt        *string `json:"refundedAt,omitempty"`
+}
+
+// RefundResponse is the latest refund request of a purchase and its outcome
+type RefundResponse struct {
+	Status           string  `json:"status"`
+	Reason           string  `json:"reason"`
+	ResolutionReason string  `json:"resolutionReason,omitempty"`
+	RequestedAt      string  `json:"requestedAt"`
+	ResolvedAt       *string `json:"resolvedAt,omitempty"`
+}
+
+type NotificationResponse struct {
+	NotificationID string `json:"notificationId"`
+	PurchaseID     string `json:"purchaseId"`
+	Type           string `json:"type"`
+	Message        string `json:"message"`
+	CreatedAt      string `json:"createdAt"`
+}
+
+type ListNotificationsResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	Total         int                    `json:"total"`
+	Page          int                    `json:"page"`
+	Limit         int                    `json:"limit"`
+}
+
+// PurchaseIDsResponse lists the requested purchases that exist
+type PurchaseIDsResponse struct {
+	PurchaseIDs []string `json:"purchaseIds"`
+}
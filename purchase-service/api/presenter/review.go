@@ -0,0 +1,43 @@
+package presenter
+
+// ReviewResponse is a buyer's review of a product
+type ReviewResponse struct {
+	ReviewID    string        `json:"reviewId"`
+	PurchaseID  string        `json:"purchaseId"`
+	ProductID   string        `json:"productId"`
+	ProductName string        `json:"productName"`
+	SellerID    string        `json:"sellerId"`
+	UserID      string        `json:"userId"`
+	Rating      int           `json:"rating"`
+	Comment     string        `json:"comment"`
+	Images      []ReviewImage `json:"images"`
+	CreatedAt   string        `json:"createdAt"`
+}
+
+type ReviewImage struct {
+	FileID           string `json:"fileId"`
+	FileURI          string `json:"fileUri"`
+	FileThumbnailURI string `json:"fileThumbnailUri"`
+}
+
+type ListReviewsResponse struct {
+	Reviews []ReviewResponse `json:"reviews"`
+	Total   int              `json:"total"`
+	Page    int              `json:"page"`
+	Limit   int              `json:"limit"`
+}
+
+// SellerRatingResponse is the average of all the ratings a seller received
+type SellerRatingResponse struct {
+	SellerID      string  `json:"sellerId"`
+	AverageRating float64 `json:"averageRating"` // Rounded to two decimals, 0 without reviews
+	ReviewCount   int     `json:"reviewCount"`
+}
+
+type ListSellerReviewsResponse struct {
+	Rating  SellerRatingResponse `json:"rating"`
+	Reviews []ReviewResponse     `json:"reviews"`
+	Total   int                  `json:"total"`
+	Page    int                  `json:"page"`
+	Limit   int                  `json:"limit"`
+}
@@ -0,0 +1,68 @@
+package presenter
+
+import "purchase-service/pkg/money"
+
+// Seller order Response DTOs
+type SellerOrderResponse struct {
+	PurchaseID      string                 `json:"purchaseId"`
+	BuyerID         string                 `json:"buyerId"`
+	Status          string                 `json:"status"`
+	StatusHistory   StatusTimestamps       `json:"statusHistory"`
+	StatusReason    string                 `json:"statusReason,omitempty"`
+	PurchasedItems  []PurchaseItemResponse `json:"purchasedItems"`
+	TotalPrice      money.Amount           `json:"totalPrice"` // Of the seller's items, after Discount and with the shipment cost
+	Discount        money.Amount           `json:"discount"`
+	Currency        string                 `json:"currency"`
+	PaymentDetail   *PaymentDetail         `json:"paymentDetail"`
+	PaymentProofs   []PaymentProofFile     `json:"paymentProofs"`
+	Refund          *RefundResponse        `json:"refund,omitempty"`
+	SenderInfo      SenderInfo             `json:"senderInfo"`
+	ShippingAddress *ShippingAddress       `json:"shippingAddress,omitempty"`
+	Shipment        *ShipmentResponse      `json:"shipment,omitempty"`
+	CreatedAt       string                 `json:"createdAt"`
+	UpdatedAt       string                 `json:"updatedAt"`
+}
+
+type ListSellerOrdersResponse struct {
+	Orders []SellerOrderResponse `json:"orders"`
+	Total  int                   `json:"total"`
+	Page   int                   `json:"page"`
+	Limit  int                   `json:"limit"`
+}
+
+type PaymentProofFile struct {
+	FileID           string  `json:"fileId"`
+	FileURI          string  `json:"fileUri"`
+	FileThumbnailURI string  `json:"fileThumbnailUri"`
+	SellerID         string  `json:"sellerId,omitempty"` // Empty when the proof covers the whole purchase
+	Status           string  `json:"status"`
+	UploadedAt       string  `json:"uploadedAt"`
+	ReviewedAt       *string `json:"reviewedAt,omitempty"`
+}
+
+// VoucherResponse is a discount code and how much of it has been used
+type VoucherResponse struct {
+	VoucherID    string        `json:"voucherId"`
+	Code         string        `json:"code"`
+	SellerID     string        `json:"sellerId,omitempty"` // Empty for platform vouchers
+	Type         string        `json:"type"`
+	Percentage   int           `json:"percentage,omitempty"`
+	Amount       money.Amount  `json:"amount,omitempty"`
+	MaxDiscount  *money.Amount `json:"maxDiscount,omitempty"`
+	Currency     string        `json:"currency"`
+	MinSpend     money.Amount  `json:"minSpend"`
+	UsageLimit   *int          `json:"usageLimit,omitempty"`
+	PerUserLimit *int          `json:"perUserLimit,omitempty"`
+	UsedCount    int           `json:"usedCount"`
+	StartsAt     *string       `json:"startsAt,omitempty"`
+	EndsAt       *string       `json:"endsAt,omitempty"`
+	DisabledAt   *string       `json:"disabledAt,omitempty"`
+	CreatedAt    string        `json:"createdAt"`
+}
+
+type ListVouchersResponse struct {
+	Vouchers []VoucherResponse `json:"vouchers"`
+	Total    int               `json:"total"`
+	Page     int               `json:"page"`
+	Limit    int               `json:"limit"`
+}
@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"purchase-service/api/handlers"
+	"purchase-service/api/middleware"
+	"purchase-service/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// CartRouter sets up the shopping cart routes
+func CartRouter(api fiber.Router, services config.Services) {
+	cartHandler := handlers.NewCartHandler(services.PurchaseService)
+
+	config := config.NewViper()
+
+	// Cart routes
+	cart := api.Group("/cart")
+	{
+		cart.Get("/", middleware.GatewayTrust(config), cartHandler.GetCart)
+		cart.Delete("/", middleware.GatewayTrust(config), cartHandler.ClearCart)
+		cart.Post("/items", middleware.GatewayTrust(config), cartHandler.AddItem)
+		cart.Put("/items/:productId", middleware.GatewayTrust(config), cartHandler.UpdateItem)
+		cart.Delete("/items/:productId", middleware.GatewayTrust(config), cartHandler.RemoveItem)
+		cart.Post("/checkout", middleware.GatewayTrust(config), middleware.Idempotency(services.IdempotencyService), cartHandler.Checkout)
+	}
+}
@@ -0,0 +1,33 @@
+package routes
+
+import (
+	"purchase-service/api/handlers"
+	"purchase-service/api/middleware"
+	"purchase-service/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// DisputeRouter sets up the purchase dispute routes and their admin counterparts
+func DisputeRouter(api fiber.Router, services config.Services) {
+	disputeHandler := handlers.NewDisputeHandler(services.PurchaseService)
+
+	config := config.NewViper()
+
+	// Dispute routes for the buyer and sellers of a purchase
+	dispute := api.Group("/purchase/:purchaseId/dispute")
+	{
+		dispute.Post("/", middleware.GatewayTrust(config), disputeHandler.OpenDispute)
+		dispute.Get("/", middleware.GatewayTrust(config), disputeHandler.GetDispute)
+		dispute.Post("/messages", middleware.GatewayTrust(config), disputeHandler.AddDisputeMessage)
+		dispute.Post("/escalate", middleware.GatewayTrust(config), disputeHandler.EscalateDispute)
+	}
+
+	// Admin dispute routes
+	admin := api.Group("/admin/disputes")
+	{
+		admin.Get("/", middleware.GatewayTrust(config), middleware.AdminOnly(config), disputeHandler.ListDisputes)
+		admin.Get("/:disputeId", middleware.GatewayTrust(config), middleware.AdminOnly(config), disputeHandler.GetDisputeByID)
+		admin.Post("/:disputeId/resolve", middleware.GatewayTrust(config), middleware.AdminOnly(config), disputeHandler.ResolveDispute)
+	}
+}
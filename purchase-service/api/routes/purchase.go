@@ -17,9 +17,25 @@ func PurchaseRouter(api fiber.Router, services config.Services) {
 	// Purchase routes
 	purchase := api.Group("/purchase")
 	{
-		purchase.Post("/", middleware.GatewayTrust(config), purchaseHandler.CreatePurchase)
+		purchase.Post("/", middleware.GatewayTrust(config), middleware.Idempotency(services.IdempotencyService), purchaseHandler.CreatePurchase)
 		purchase.Get("/", middleware.GatewayTrust(config), purchaseHandler.ListPurchases)
+		purchase.Get("/notifications", middleware.GatewayTrust(config), purchaseHandler.ListNotifications)
+		purchase.Get("/export", middleware.GatewayTrust(config), purchaseHandler.ExportPurchases)
 		purchase.Get("/:purchaseId", middleware.GatewayTrust(config), purchaseHandler.GetPurchaseByID)
-		purchase.Post("/:purchaseId", middleware.GatewayTrust(config), purchaseHandler.UploadPaymentProof)
+		purchase.Post("/:purchaseId", middleware.GatewayTrust(config), middleware.Idempotency(services.IdempotencyService), purchaseHandler.UploadPaymentProof)
+		purchase.Get("/:purchaseId/invoice", middleware.GatewayTrust(config), purchaseHandler.GetInvoice)
+		purchase.Post("/:purchaseId/cancel", middleware.GatewayTrust(config), purchaseHandler.CancelPurchase)
+		purchase.Post("/:purchaseId/refund", middleware.GatewayTrust(config), purchaseHandler.RequestRefund)
+		purchase.Post("/:purchaseId/received", middleware.GatewayTrust(config), purchaseHandler.ConfirmReceipt)
 	}
 }
+
+// InternalPurchaseRouter exposes purchase lookups to other services (not proxied by the gateway)
+func InternalPurchaseRouter(app fiber.Router, services config.Services) {
+	purchaseHandler := handlers.NewPurchaseHandler(services.PurchaseService)
+
+	config := config.NewViper()
+
+	internal := app.Group("/purchase", middleware.GatewayTrust(config))
+	internal.Post("/batch", purchaseHandler.GetExistingPurchaseIDs)
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"purchase-service/api/handlers"
+	"purchase-service/api/middleware"
+	"purchase-service/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ReviewRouter sets up the product review and seller rating routes
+func ReviewRouter(api fiber.Router, services config.Services) {
+	reviewHandler := handlers.NewReviewHandler(services.PurchaseService)
+
+	config := config.NewViper()
+
+	// Review routes
+	reviews := api.Group("/reviews")
+	{
+		reviews.Get("/product/:productId", middleware.GatewayTrust(config), reviewHandler.ListProductReviews)
+		reviews.Get("/seller/:sellerId", middleware.GatewayTrust(config), reviewHandler.ListSellerReviews)
+		reviews.Get("/seller/:sellerId/rating", middleware.GatewayTrust(config), reviewHandler.GetSellerRating)
+	}
+
+	api.Post("/purchase/:purchaseId/reviews", middleware.GatewayTrust(config), reviewHandler.CreateReview)
+}
@@ -21,6 +21,13 @@ func SetupRoutes(app *fiber.App, v *viper.Viper, db *gorm.DB, services config.Se
 	})
 
 	PurchaseRouter(api, services)
+	SellerRouter(api, services)
+	CartRouter(api, services)
+	ReviewRouter(api, services)
+	DisputeRouter(api, services)
+
+	// Internal routes for service-to-service calls
+	InternalPurchaseRouter(app, services)
 
 	app.Get("/healthz", func(c *fiber.Ctx) error {
 		sqlDB, err := db.DB() // get underlying *sql.DB from GORM
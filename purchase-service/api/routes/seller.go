@@ -0,0 +1,48 @@
+package routes
+
+import (
+	"purchase-service/api/handlers"
+	"purchase-service/api/middleware"
+	"purchase-service/config"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SellerRouter sets up the seller order inbox, voucher and analytics routes,
+// and the admin routes of platform vouchers
+func SellerRouter(api fiber.Router, services config.Services) {
+	sellerHandler := handlers.NewSellerOrderHandler(services.PurchaseService)
+	voucherHandler := handlers.NewVoucherHandler(services.PurchaseService)
+
+	config := config.NewViper()
+
+	// Seller order routes
+	orders := api.Group("/seller/orders")
+	{
+		orders.Get("/", middleware.GatewayTrust(config), sellerHandler.ListOrders)
+		orders.Get("/export", middleware.GatewayTrust(config), sellerHandler.ExportOrders)
+		orders.Get("/:purchaseId", middleware.GatewayTrust(config), sellerHandler.GetOrder)
+		orders.Post("/:purchaseId/confirm", middleware.GatewayTrust(config), sellerHandler.ConfirmPayment)
+		orders.Post("/:purchaseId/reject", middleware.GatewayTrust(config), sellerHandler.RejectPayment)
+		orders.Post("/:purchaseId/ship", middleware.GatewayTrust(config), sellerHandler.ShipOrder)
+		orders.Post("/:purchaseId/refund/approve", middleware.GatewayTrust(config), sellerHandler.ApproveRefund)
+		orders.Post("/:purchaseId/refund/reject", middleware.GatewayTrust(config), sellerHandler.RejectRefund)
+	}
+
+	// Seller voucher routes
+	vouchers := api.Group("/seller/vouchers")
+	{
+		vouchers.Post("/", middleware.GatewayTrust(config), voucherHandler.CreateVoucher)
+		vouchers.Get("/", middleware.GatewayTrust(config), voucherHandler.ListVouchers)
+		vouchers.Post("/:voucherId/disable", middleware.GatewayTrust(config), voucherHandler.DisableVoucher)
+	}
+
+	// Admin platform voucher routes
+	admin := api.Group("/admin/vouchers")
+	{
+		admin.Post("/", middleware.GatewayTrust(config), middleware.AdminOnly(config), voucherHandler.CreatePlatformVoucher)
+		admin.Post("/:voucherId/disable", middleware.GatewayTrust(config), middleware.AdminOnly(config), voucherHandler.DisablePlatformVoucher)
+	}
+
+	api.Get("/seller/analytics", middleware.GatewayTrust(config), sellerHandler.GetAnalytics)
+}
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"purchase-service/api/routes"
@@ -23,6 +24,11 @@ func main() {
 	services := config.InitServices(db)
 	routes.SetupRoutes(app, v, db, services)
 
+	// Expire purchases that were never paid
+	if worker := config.NewExpiryWorker(v, services); worker != nil {
+		go worker.Run(context.Background())
+	}
+
 	// Run server
 	port := v.GetString("SERVER_PORT")
 	if port == "" {
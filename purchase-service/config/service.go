@@ -1,21 +1,26 @@
 package config
 
 import (
+	"log"
 	"os"
+	"purchase-service/pkg/idempotency"
 	"purchase-service/pkg/purchase"
+	"time"
 
 	"gorm.io/gorm"
 )
 
 // Services struct holds all service dependencies
 type Services struct {
-	PurchaseService purchase.Service
+	PurchaseService    purchase.Service
+	IdempotencyService idempotency.Service
 }
 
 // InitServices initializes all application services
 func InitServices(db *gorm.DB) Services {
 	// Initialize repositories
 	purchaseRepo := purchase.NewGormRepository(db)
+	idempotencyRepo := idempotency.NewGormRepository(db)
 
 	// Get service URLs from environment variables
 	userServiceURL := os.Getenv("USER_SERVICE_URL")
@@ -33,10 +38,28 @@ func InitServices(db *gorm.DB) Services {
 		internalSecret = "backend-infra-internal-secret" // Default internal secret
 	}
 
+	// Responses to requests with an Idempotency-Key are replayed for this long
+	idempotencyKeyTTL, err := time.ParseDuration(os.Getenv("IDEMPOTENCY_KEY_TTL"))
+	if err != nil || idempotencyKeyTTL <= 0 {
+		idempotencyKeyTTL = 24 * time.Hour // Default idempotency window
+	}
+
+	// Flat shipping cost per seller of each shipping method
+	shippingRatesConfig := os.Getenv("SHIPPING_RATES")
+	if shippingRatesConfig == "" {
+		shippingRatesConfig = "regular=15000,express=30000" // Default shipping rates
+	}
+	shippingRates, err := purchase.ParseShippingRates(shippingRatesConfig)
+	if err != nil {
+		log.Fatalf("Invalid SHIPPING_RATES: %v", err)
+	}
+
 	// Initialize services
-	purchaseService := purchase.NewService(purchaseRepo, userServiceURL, productServiceURL, internalSecret)
+	purchaseService := purchase.NewService(purchaseRepo, userServiceURL, productServiceURL, internalSecret, shippingRates)
+	idempotencyService := idempotency.NewService(idempotencyRepo, idempotencyKeyTTL)
 
 	return Services{
-		PurchaseService: purchaseService,
+		PurchaseService:    purchaseService,
+		IdempotencyService: idempotencyService,
 	}
 }
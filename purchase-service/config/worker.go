@@ -0,0 +1,33 @@
+package config
+
+import (
+	"purchase-service/pkg/purchase"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// NewExpiryWorker builds the background worker that expires unpaid purchases.
+// It returns nil when PURCHASE_EXPIRY_INTERVAL is set to a non-positive value.
+func NewExpiryWorker(config *viper.Viper, services Services) *purchase.ExpiryWorker {
+	config.SetDefault("PURCHASE_PAYMENT_DEADLINE", "24h")
+	config.SetDefault("PURCHASE_EXPIRY_INTERVAL", "1m")
+	config.SetDefault("PURCHASE_EXPIRY_BATCH_SIZE", 50)
+
+	interval := config.GetDuration("PURCHASE_EXPIRY_INTERVAL")
+	if interval <= 0 {
+		return nil
+	}
+
+	deadline := config.GetDuration("PURCHASE_PAYMENT_DEADLINE")
+	if deadline <= 0 {
+		deadline = 24 * time.Hour
+	}
+
+	batchSize := config.GetInt("PURCHASE_EXPIRY_BATCH_SIZE")
+	if batchSize <= 0 {
+		batchSize = 50
+	}
+
+	return purchase.NewExpiryWorker(services.PurchaseService, interval, deadline, batchSize)
+}
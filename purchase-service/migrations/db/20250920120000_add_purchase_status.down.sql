@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS idx_purchases_user_id_status;
+
+ALTER TABLE purchases DROP CONSTRAINT IF EXISTS chk_purchases_status;
+
+ALTER TABLE purchases
+    DROP COLUMN IF EXISTS expired_at,
+    DROP COLUMN IF EXISTS cancelled_at,
+    DROP COLUMN IF EXISTS completed_at,
+    DROP COLUMN IF EXISTS confirmed_at,
+    DROP COLUMN IF EXISTS proof_uploaded_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,24 @@
+-- Add lifecycle status and transition timestamps to purchases
+ALTER TABLE purchases
+    ADD COLUMN IF NOT EXISTS status VARCHAR(32) NOT NULL DEFAULT 'pending_payment',
+    ADD COLUMN IF NOT EXISTS proof_uploaded_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS confirmed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS completed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS cancelled_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS expired_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE purchases
+    ADD CONSTRAINT chk_purchases_status CHECK (status IN (
+        'pending_payment', 'proof_uploaded', 'confirmed', 'completed', 'cancelled', 'expired'
+    ));
+
+-- Backfill: purchases that already have payment proof were waiting for confirmation
+UPDATE purchases
+SET status = 'proof_uploaded',
+    proof_uploaded_at = updated_at
+WHERE payment_proof_ids IS NOT NULL
+  AND payment_proof_ids NOT IN ('', '[]', 'null');
+
+CREATE INDEX IF NOT EXISTS idx_purchases_user_id_status ON purchases(user_id, status);
+
+COMMENT ON COLUMN purchases.status IS 'Lifecycle status: pending_payment, proof_uploaded, confirmed, completed, cancelled or expired';
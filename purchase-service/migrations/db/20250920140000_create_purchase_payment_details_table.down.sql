@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_purchase_payment_details_purchase_id;
+
+DROP TABLE IF EXISTS purchase_payment_details;
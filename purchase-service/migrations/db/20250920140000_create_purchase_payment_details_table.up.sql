@@ -0,0 +1,18 @@
+-- Snapshot of each seller's bank account and subtotal at checkout time
+CREATE TABLE IF NOT EXISTS purchase_payment_details (
+    id UUID PRIMARY KEY,
+    purchase_id UUID NOT NULL REFERENCES purchases(id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL,
+    bank_account_name VARCHAR(255) NOT NULL,
+    bank_account_holder VARCHAR(255) NOT NULL,
+    bank_account_number VARCHAR(255) NOT NULL,
+    total_price DECIMAL(10,2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_payment_details_purchase_id ON purchase_payment_details(purchase_id);
+
+COMMENT ON TABLE purchase_payment_details IS 'Stores per-seller bank account details and subtotal captured when the purchase was created';
+COMMENT ON COLUMN purchase_payment_details.seller_id IS 'ID of the seller the buyer has to pay';
+COMMENT ON COLUMN purchase_payment_details.total_price IS 'Subtotal owed to this seller';
@@ -0,0 +1,13 @@
+-- Restore the previous meaning of qty (product stock) where it is known
+UPDATE purchase_items
+SET qty = stock_snapshot
+WHERE stock_snapshot IS NOT NULL;
+
+ALTER TABLE purchases DROP COLUMN IF EXISTS total_price;
+
+ALTER TABLE purchase_items
+    DROP COLUMN IF EXISTS line_total,
+    DROP COLUMN IF EXISTS stock_snapshot;
+
+COMMENT ON COLUMN purchase_items.qty IS 'Quantity of the product purchased';
+COMMENT ON COLUMN purchase_items.price IS 'Price per unit of the product';
@@ -0,0 +1,70 @@
+-- Purchase items used to store the product stock in qty while totals were
+-- computed from the ordered quantity. Keep the stock as its own snapshot
+-- column and store line and purchase totals explicitly.
+ALTER TABLE purchase_items
+    ADD COLUMN IF NOT EXISTS stock_snapshot INTEGER,
+    ADD COLUMN IF NOT EXISTS line_total DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+ALTER TABLE purchases
+    ADD COLUMN IF NOT EXISTS total_price DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+-- Backfill: where the stock reservation for the purchase is still around, the
+-- reserved quantity is the ordered quantity and the old qty was the stock.
+-- Products listed more than once in the same purchase share one reservation,
+-- so those rows are left as they are.
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'product_reservations') THEN
+        UPDATE purchase_items pi
+        SET stock_snapshot = pi.qty,
+            qty = pr.qty
+        FROM product_reservations pr
+        WHERE pr.purchase_id = pi.purchase_id::text
+          AND pr.product_id::text = pi.product_id
+          AND pi.stock_snapshot IS NULL
+          AND NOT EXISTS (
+              SELECT 1 FROM purchase_items other
+              WHERE other.purchase_id = pi.purchase_id
+                AND other.product_id = pi.product_id
+                AND other.id <> pi.id
+          );
+    END IF;
+END $$;
+
+-- Otherwise, a seller with a single item in the purchase lets us recover the
+-- ordered quantity from that seller's payment subtotal
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'products') THEN
+        UPDATE purchase_items pi
+        SET stock_snapshot = pi.qty,
+            qty = ROUND(ppd.total_price / pi.price)::INTEGER
+        FROM purchase_payment_details ppd
+        WHERE ppd.purchase_id = pi.purchase_id
+          AND pi.stock_snapshot IS NULL
+          AND pi.price > 0
+          AND ppd.total_price > 0
+          AND EXISTS (
+              SELECT 1 FROM products p
+              WHERE p.id::text = pi.product_id AND p.seller_id = ppd.seller_id
+          )
+          AND (
+              SELECT COUNT(*) FROM purchase_items other
+              JOIN products op ON op.id::text = other.product_id
+              WHERE other.purchase_id = pi.purchase_id AND op.seller_id = ppd.seller_id
+          ) = 1;
+    END IF;
+END $$;
+
+UPDATE purchase_items SET line_total = price * qty;
+
+UPDATE purchases p
+SET total_price = COALESCE((
+    SELECT SUM(pi.line_total) FROM purchase_items pi WHERE pi.purchase_id = p.id
+), 0);
+
+COMMENT ON COLUMN purchase_items.qty IS 'Quantity of the product ordered by the buyer';
+COMMENT ON COLUMN purchase_items.price IS 'Unit price of the product at checkout';
+COMMENT ON COLUMN purchase_items.line_total IS 'Unit price multiplied by ordered quantity';
+COMMENT ON COLUMN purchase_items.stock_snapshot IS 'Product stock at checkout; NULL for rows that could not be backfilled';
+COMMENT ON COLUMN purchases.total_price IS 'Sum of the line totals of all purchase items';
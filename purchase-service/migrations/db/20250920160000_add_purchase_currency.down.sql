@@ -0,0 +1 @@
+ALTER TABLE purchases DROP COLUMN IF EXISTS currency;
@@ -0,0 +1,6 @@
+-- Amounts are exact decimals in the purchase currency; existing purchases
+-- were all made in rupiah
+ALTER TABLE purchases
+    ADD COLUMN IF NOT EXISTS currency VARCHAR(3) NOT NULL DEFAULT 'IDR';
+
+COMMENT ON COLUMN purchases.currency IS 'ISO 4217 currency code shared by every amount in the purchase';
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_purchases_status_created_at;
+
+ALTER TABLE purchases DROP COLUMN IF EXISTS status_reason;
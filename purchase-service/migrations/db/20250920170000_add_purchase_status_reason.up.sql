@@ -0,0 +1,8 @@
+-- Reason attached to the latest status change (e.g. why a purchase expired)
+ALTER TABLE purchases
+    ADD COLUMN IF NOT EXISTS status_reason TEXT NOT NULL DEFAULT '';
+
+-- Lets the expiry worker find overdue purchases without scanning the table
+CREATE INDEX IF NOT EXISTS idx_purchases_status_created_at ON purchases(status, created_at);
+
+COMMENT ON COLUMN purchases.status_reason IS 'Why the purchase entered its current status, if a reason was given';
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_purchase_notifications_user_id_created_at;
+
+DROP TABLE IF EXISTS purchase_notifications;
+
+DROP INDEX IF EXISTS idx_purchase_items_seller_id;
+
+ALTER TABLE purchase_items DROP COLUMN IF EXISTS seller_id;
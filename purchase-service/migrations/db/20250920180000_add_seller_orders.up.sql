@@ -0,0 +1,40 @@
+-- Record which seller sold each item so sellers can list their orders
+ALTER TABLE purchase_items
+    ADD COLUMN IF NOT EXISTS seller_id UUID;
+
+-- Backfill from the product catalogue when it is available
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 'products') THEN
+        UPDATE purchase_items pi
+        SET seller_id = p.seller_id
+        FROM products p
+        WHERE p.id::text = pi.product_id
+          AND pi.seller_id IS NULL;
+    END IF;
+END $$;
+
+-- Purchases paid to a single seller can be attributed from their payment details
+UPDATE purchase_items pi
+SET seller_id = ppd.seller_id
+FROM purchase_payment_details ppd
+WHERE ppd.purchase_id = pi.purchase_id
+  AND pi.seller_id IS NULL
+  AND (SELECT COUNT(*) FROM purchase_payment_details other WHERE other.purchase_id = pi.purchase_id) = 1;
+
+CREATE INDEX IF NOT EXISTS idx_purchase_items_seller_id ON purchase_items(seller_id);
+
+-- Messages for buyers about their purchases
+CREATE TABLE IF NOT EXISTS purchase_notifications (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    purchase_id UUID NOT NULL REFERENCES purchases(id) ON DELETE CASCADE,
+    type VARCHAR(50) NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_notifications_user_id_created_at ON purchase_notifications(user_id, created_at);
+
+COMMENT ON COLUMN purchase_items.seller_id IS 'ID of the seller of the product; NULL for items that could not be backfilled';
+COMMENT ON TABLE purchase_notifications IS 'Stores notifications for buyers, e.g. confirmed or rejected payments';
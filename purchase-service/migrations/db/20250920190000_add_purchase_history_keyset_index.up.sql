@@ -0,0 +1,2 @@
+-- Supports keyset pagination of a buyer's purchase history on (created_at, id)
+CREATE INDEX IF NOT EXISTS idx_purchases_user_id_created_at_id ON purchases(user_id, created_at DESC, id DESC);
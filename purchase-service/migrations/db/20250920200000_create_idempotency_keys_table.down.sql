@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_idempotency_keys_expires_at;
+DROP INDEX IF EXISTS idx_idempotency_keys_user_id_key;
+
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,19 @@
+-- Stores the outcome of requests sent with an Idempotency-Key header
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    key VARCHAR(255) NOT NULL,
+    request_hash VARCHAR(64) NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    response_body BYTEA,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_idempotency_keys_user_id_key ON idempotency_keys(user_id, key);
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires_at ON idempotency_keys(expires_at);
+
+COMMENT ON TABLE idempotency_keys IS 'Stores responses of idempotent requests so retries replay the original result';
+COMMENT ON COLUMN idempotency_keys.request_hash IS 'SHA-256 of method, path and body; a retry must match it';
+COMMENT ON COLUMN idempotency_keys.status_code IS 'HTTP status of the stored response; 0 while the request is still running';
@@ -0,0 +1,18 @@
+DROP INDEX IF EXISTS idx_purchase_refunds_purchase_id_created_at;
+
+DROP TABLE IF EXISTS purchase_refunds;
+
+-- Refunded purchases have no status to go back to; they were confirmed before
+UPDATE purchases SET status = 'confirmed' WHERE status IN ('refund_requested', 'refunded');
+
+ALTER TABLE purchases DROP CONSTRAINT IF EXISTS chk_purchases_status;
+ALTER TABLE purchases
+    ADD CONSTRAINT chk_purchases_status CHECK (status IN (
+        'pending_payment', 'proof_uploaded', 'confirmed', 'completed', 'cancelled', 'expired'
+    ));
+
+ALTER TABLE purchases
+    DROP COLUMN IF EXISTS refunded_at,
+    DROP COLUMN IF EXISTS refund_requested_at;
+
+COMMENT ON COLUMN purchases.status IS 'Lifecycle status: pending_payment, proof_uploaded, confirmed, completed, cancelled or expired';
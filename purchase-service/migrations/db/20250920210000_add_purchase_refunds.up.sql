@@ -0,0 +1,30 @@
+-- Allow buyers to request refunds of confirmed purchases
+ALTER TABLE purchases
+    ADD COLUMN IF NOT EXISTS refund_requested_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS refunded_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE purchases DROP CONSTRAINT IF EXISTS chk_purchases_status;
+ALTER TABLE purchases
+    ADD CONSTRAINT chk_purchases_status CHECK (status IN (
+        'pending_payment', 'proof_uploaded', 'confirmed', 'completed', 'cancelled', 'expired',
+        'refund_requested', 'refunded'
+    ));
+
+-- Refund requests and the seller's decision on them
+CREATE TABLE IF NOT EXISTS purchase_refunds (
+    id UUID PRIMARY KEY,
+    purchase_id UUID NOT NULL REFERENCES purchases(id) ON DELETE CASCADE,
+    status VARCHAR(16) NOT NULL DEFAULT 'requested',
+    reason TEXT NOT NULL,
+    resolution_reason TEXT NOT NULL DEFAULT '',
+    resolved_by UUID,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT chk_purchase_refunds_status CHECK (status IN ('requested', 'approved', 'rejected'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_refunds_purchase_id_created_at ON purchase_refunds(purchase_id, created_at);
+
+COMMENT ON COLUMN purchases.status IS 'Lifecycle status: pending_payment, proof_uploaded, confirmed, completed, cancelled, expired, refund_requested or refunded';
+COMMENT ON TABLE purchase_refunds IS 'Stores buyers'' refund requests and whether the seller approved or rejected them';
@@ -0,0 +1,19 @@
+ALTER TABLE purchases
+    ADD COLUMN IF NOT EXISTS payment_proof_ids TEXT;
+
+-- Restore the JSON column from the proofs that are still current
+UPDATE purchases p
+SET payment_proof_ids = proofs.ids
+FROM (
+    SELECT purchase_id, json_agg(file_id::text ORDER BY created_at)::text AS ids
+    FROM purchase_payment_proofs
+    WHERE status IN ('pending', 'accepted')
+    GROUP BY purchase_id
+) proofs
+WHERE proofs.purchase_id = p.id;
+
+COMMENT ON COLUMN purchases.payment_proof_ids IS 'JSON array of file IDs for payment proof images';
+
+DROP INDEX IF EXISTS idx_purchase_payment_proofs_purchase_id;
+
+DROP TABLE IF EXISTS purchase_payment_proofs;
@@ -0,0 +1,41 @@
+-- Payment proofs move from a JSON text column to their own table, so each
+-- proof can record the seller it pays, when it was uploaded and its review
+CREATE TABLE IF NOT EXISTS purchase_payment_proofs (
+    id UUID PRIMARY KEY,
+    purchase_id UUID NOT NULL REFERENCES purchases(id) ON DELETE CASCADE,
+    seller_id UUID,
+    file_id UUID NOT NULL,
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT chk_purchase_payment_proofs_status CHECK (status IN ('pending', 'accepted', 'rejected', 'replaced'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_payment_proofs_purchase_id ON purchase_payment_proofs(purchase_id);
+
+-- Backfill from the JSON column. Entries that are not UUIDs never referenced a
+-- file and are dropped; proofs of single-seller purchases are attributed to
+-- that seller, and proofs of paid purchases count as accepted.
+INSERT INTO purchase_payment_proofs (id, purchase_id, seller_id, file_id, status, reviewed_at, created_at, updated_at)
+SELECT uuid_generate_v4(),
+       p.id,
+       (SELECT MIN(ppd.seller_id::text)::uuid
+        FROM purchase_payment_details ppd
+        WHERE ppd.purchase_id = p.id
+        HAVING COUNT(*) = 1),
+       proof.file_id::uuid,
+       CASE WHEN p.status IN ('confirmed', 'completed', 'refund_requested', 'refunded') THEN 'accepted' ELSE 'pending' END,
+       CASE WHEN p.status IN ('confirmed', 'completed', 'refund_requested', 'refunded') THEN p.confirmed_at END,
+       COALESCE(p.proof_uploaded_at, p.updated_at),
+       COALESCE(p.proof_uploaded_at, p.updated_at)
+FROM purchases p
+CROSS JOIN LATERAL json_array_elements_text(p.payment_proof_ids::json) AS proof(file_id)
+WHERE p.payment_proof_ids IS NOT NULL
+  AND p.payment_proof_ids NOT IN ('', 'null')
+  AND proof.file_id ~* '^[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$';
+
+ALTER TABLE purchases DROP COLUMN IF EXISTS payment_proof_ids;
+
+COMMENT ON TABLE purchase_payment_proofs IS 'Stores the payment proof files uploaded by buyers and their review status';
+COMMENT ON COLUMN purchase_payment_proofs.seller_id IS 'Seller the transfer was made to; NULL when the proof covers the whole purchase';
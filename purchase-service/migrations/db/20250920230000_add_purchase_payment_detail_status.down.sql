@@ -0,0 +1,7 @@
+ALTER TABLE purchase_payment_details DROP CONSTRAINT IF EXISTS chk_purchase_payment_details_status;
+
+ALTER TABLE purchase_payment_details
+    DROP COLUMN IF EXISTS status_reason,
+    DROP COLUMN IF EXISTS confirmed_at,
+    DROP COLUMN IF EXISTS proof_uploaded_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,24 @@
+-- Track the payment to each seller of a purchase separately
+ALTER TABLE purchase_payment_details
+    ADD COLUMN IF NOT EXISTS status VARCHAR(32) NOT NULL DEFAULT 'pending_payment',
+    ADD COLUMN IF NOT EXISTS proof_uploaded_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS confirmed_at TIMESTAMP WITH TIME ZONE,
+    ADD COLUMN IF NOT EXISTS status_reason TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE purchase_payment_details
+    ADD CONSTRAINT chk_purchase_payment_details_status CHECK (status IN ('pending_payment', 'proof_uploaded', 'confirmed'));
+
+-- Existing payments were reviewed for the purchase as a whole, so every
+-- seller takes over the purchase's payment state
+UPDATE purchase_payment_details d
+SET status = CASE
+        WHEN p.status = 'proof_uploaded' THEN 'proof_uploaded'
+        WHEN p.status IN ('confirmed', 'completed', 'refund_requested', 'refunded') THEN 'confirmed'
+        ELSE 'pending_payment'
+    END,
+    proof_uploaded_at = p.proof_uploaded_at,
+    confirmed_at = p.confirmed_at
+FROM purchases p
+WHERE p.id = d.purchase_id;
+
+COMMENT ON COLUMN purchase_payment_details.status IS 'Payment status towards this seller: pending_payment, proof_uploaded or confirmed';
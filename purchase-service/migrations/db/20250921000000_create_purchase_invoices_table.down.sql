@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS purchase_invoices;
+
+DROP TABLE IF EXISTS seller_invoice_counters;
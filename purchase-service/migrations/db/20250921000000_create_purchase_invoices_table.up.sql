@@ -0,0 +1,21 @@
+-- Last invoice sequence allocated to each seller
+CREATE TABLE IF NOT EXISTS seller_invoice_counters (
+    seller_id UUID PRIMARY KEY,
+    last_sequence INTEGER NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Invoices sellers issue for their part of a purchase
+CREATE TABLE IF NOT EXISTS purchase_invoices (
+    id UUID PRIMARY KEY,
+    purchase_id UUID NOT NULL REFERENCES purchases(id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL,
+    sequence INTEGER NOT NULL,
+    number VARCHAR(64) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT uq_purchase_invoices_purchase_id_seller_id UNIQUE (purchase_id, seller_id),
+    CONSTRAINT uq_purchase_invoices_seller_id_sequence UNIQUE (seller_id, sequence)
+);
+
+COMMENT ON TABLE seller_invoice_counters IS 'Allocates gapless invoice sequences per seller';
+COMMENT ON TABLE purchase_invoices IS 'Stores the invoice number each seller issued for a purchase';
@@ -0,0 +1,26 @@
+-- One cart per buyer; version is bumped by every change to its items
+CREATE TABLE IF NOT EXISTS carts (
+    id UUID PRIMARY KEY,
+    user_id UUID NOT NULL,
+    version INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT uq_carts_user_id UNIQUE (user_id)
+);
+
+-- Products in a cart with the unit price the buyer last saw
+CREATE TABLE IF NOT EXISTS cart_items (
+    id UUID PRIMARY KEY,
+    cart_id UUID NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+    product_id VARCHAR(255) NOT NULL,
+    qty INTEGER NOT NULL,
+    price DECIMAL(10,2) NOT NULL,
+    currency VARCHAR(3) NOT NULL DEFAULT 'IDR',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT uq_cart_items_cart_id_product_id UNIQUE (cart_id, product_id),
+    CONSTRAINT chk_cart_items_qty CHECK (qty > 0)
+);
+
+COMMENT ON TABLE carts IS 'Stores the shopping cart of each buyer until checkout';
+COMMENT ON TABLE cart_items IS 'Stores the products and quantities in a cart';
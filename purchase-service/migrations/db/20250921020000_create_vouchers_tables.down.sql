@@ -0,0 +1,10 @@
+ALTER TABLE purchase_payment_details
+    DROP COLUMN IF EXISTS discount;
+
+ALTER TABLE purchases
+    DROP COLUMN IF EXISTS voucher_code,
+    DROP COLUMN IF EXISTS discount;
+
+DROP TABLE IF EXISTS voucher_redemptions;
+
+DROP TABLE IF EXISTS vouchers;
@@ -0,0 +1,52 @@
+-- Discount codes; a voucher without a seller applies to the whole purchase
+CREATE TABLE IF NOT EXISTS vouchers (
+    id UUID PRIMARY KEY,
+    code VARCHAR(32) NOT NULL,
+    seller_id UUID,
+    type VARCHAR(16) NOT NULL,
+    percentage INTEGER NOT NULL DEFAULT 0,
+    amount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    max_discount DECIMAL(10,2),
+    currency VARCHAR(3) NOT NULL DEFAULT 'IDR',
+    min_spend DECIMAL(10,2) NOT NULL DEFAULT 0,
+    usage_limit INTEGER,
+    per_user_limit INTEGER,
+    used_count INTEGER NOT NULL DEFAULT 0,
+    starts_at TIMESTAMP WITH TIME ZONE,
+    ends_at TIMESTAMP WITH TIME ZONE,
+    disabled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT uq_vouchers_code UNIQUE (code),
+    CONSTRAINT chk_vouchers_type CHECK (type IN ('percentage', 'fixed')),
+    CONSTRAINT chk_vouchers_percentage CHECK (percentage BETWEEN 0 AND 100)
+);
+
+CREATE INDEX IF NOT EXISTS idx_vouchers_seller_id_created_at ON vouchers(seller_id, created_at);
+
+-- One redemption per purchase; released when the purchase is cancelled or expires
+CREATE TABLE IF NOT EXISTS voucher_redemptions (
+    id UUID PRIMARY KEY,
+    voucher_id UUID NOT NULL REFERENCES vouchers(id),
+    purchase_id UUID NOT NULL REFERENCES purchases(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL,
+    discount DECIMAL(10,2) NOT NULL,
+    released_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT uq_voucher_redemptions_purchase_id UNIQUE (purchase_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_voucher_redemptions_voucher_id_user_id ON voucher_redemptions(voucher_id, user_id);
+
+-- total_price of a purchase and of each seller's payment is now after the discount
+ALTER TABLE purchases
+    ADD COLUMN IF NOT EXISTS discount DECIMAL(10,2) NOT NULL DEFAULT 0,
+    ADD COLUMN IF NOT EXISTS voucher_code VARCHAR(32) NOT NULL DEFAULT '';
+
+ALTER TABLE purchase_payment_details
+    ADD COLUMN IF NOT EXISTS discount DECIMAL(10,2) NOT NULL DEFAULT 0;
+
+COMMENT ON TABLE vouchers IS 'Stores seller and platform discount codes';
+COMMENT ON TABLE voucher_redemptions IS 'Stores the use of a voucher on a purchase';
+COMMENT ON COLUMN purchase_payment_details.discount IS 'Share of the purchase discount taken off this seller''s total';
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS purchase_shipments;
+
+DROP TABLE IF EXISTS purchase_shipping_addresses;
@@ -0,0 +1,37 @@
+-- Snapshot of the buyer's address book entry the purchase is delivered to
+CREATE TABLE IF NOT EXISTS purchase_shipping_addresses (
+    id UUID PRIMARY KEY,
+    purchase_id UUID NOT NULL REFERENCES purchases(id) ON DELETE CASCADE,
+    address_id UUID NOT NULL,
+    recipient_name VARCHAR(64) NOT NULL,
+    phone VARCHAR(20) NOT NULL,
+    address_line1 VARCHAR(255) NOT NULL,
+    address_line2 VARCHAR(255) NOT NULL DEFAULT '',
+    city VARCHAR(64) NOT NULL,
+    province VARCHAR(64) NOT NULL,
+    postal_code VARCHAR(10) NOT NULL,
+    country VARCHAR(2) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT uq_purchase_shipping_addresses_purchase_id UNIQUE (purchase_id)
+);
+
+-- Delivery of each seller's items; the cost is included in the seller's payment
+CREATE TABLE IF NOT EXISTS purchase_shipments (
+    id UUID PRIMARY KEY,
+    purchase_id UUID NOT NULL REFERENCES purchases(id) ON DELETE CASCADE,
+    seller_id UUID NOT NULL,
+    method VARCHAR(32) NOT NULL,
+    cost DECIMAL(10,2) NOT NULL,
+    tracking_number VARCHAR(64) NOT NULL DEFAULT '',
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    shipped_at TIMESTAMP WITH TIME ZONE,
+    received_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT uq_purchase_shipments_purchase_id_seller_id UNIQUE (purchase_id, seller_id),
+    CONSTRAINT chk_purchase_shipments_status CHECK (status IN ('pending', 'shipped', 'received'))
+);
+
+COMMENT ON TABLE purchase_shipping_addresses IS 'Stores the shipping address of a purchase as it was at checkout';
+COMMENT ON TABLE purchase_shipments IS 'Stores the shipping method, cost and tracking of each seller''s items of a purchase';
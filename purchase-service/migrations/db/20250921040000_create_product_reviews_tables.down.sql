@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS seller_ratings;
+
+DROP TABLE IF EXISTS product_review_images;
+
+DROP TABLE IF EXISTS product_reviews;
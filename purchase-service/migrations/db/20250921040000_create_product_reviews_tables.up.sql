@@ -0,0 +1,42 @@
+-- A buyer's rating of a product of a completed purchase, once per purchase and product
+CREATE TABLE IF NOT EXISTS product_reviews (
+    id UUID PRIMARY KEY,
+    purchase_id UUID NOT NULL REFERENCES purchases(id) ON DELETE CASCADE,
+    product_id VARCHAR(255) NOT NULL,
+    seller_id UUID NOT NULL,
+    user_id UUID NOT NULL,
+    product_name VARCHAR(255) NOT NULL,
+    rating INTEGER NOT NULL,
+    comment TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT uq_product_reviews_purchase_id_product_id UNIQUE (purchase_id, product_id),
+    CONSTRAINT chk_product_reviews_rating CHECK (rating BETWEEN 1 AND 5)
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_reviews_product_id_created_at ON product_reviews(product_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_product_reviews_seller_id_created_at ON product_reviews(seller_id, created_at);
+
+-- Images the buyer attached to a review, in the order they were attached
+CREATE TABLE IF NOT EXISTS product_review_images (
+    id UUID PRIMARY KEY,
+    review_id UUID NOT NULL REFERENCES product_reviews(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_product_review_images_review_id ON product_review_images(review_id);
+
+-- Running totals of each seller's ratings, updated with every review
+CREATE TABLE IF NOT EXISTS seller_ratings (
+    seller_id UUID PRIMARY KEY,
+    review_count INTEGER NOT NULL DEFAULT 0,
+    rating_total INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+COMMENT ON TABLE product_reviews IS 'Stores the buyers'' reviews of the products of their completed purchases';
+COMMENT ON TABLE product_review_images IS 'Stores the image files attached to product reviews';
+COMMENT ON TABLE seller_ratings IS 'Stores the number of reviews and the sum of the ratings of each seller';
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS purchase_dispute_evidence;
+
+DROP TABLE IF EXISTS purchase_dispute_messages;
+
+DROP TABLE IF EXISTS purchase_disputes;
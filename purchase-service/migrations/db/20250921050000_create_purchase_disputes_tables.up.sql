@@ -0,0 +1,53 @@
+-- Disagreement between the buyer and the sellers of a purchase, settled between
+-- them or by an admin
+CREATE TABLE IF NOT EXISTS purchase_disputes (
+    id UUID PRIMARY KEY,
+    purchase_id UUID NOT NULL REFERENCES purchases(id) ON DELETE CASCADE,
+    opened_by UUID NOT NULL,
+    opened_by_role VARCHAR(16) NOT NULL,
+    status VARCHAR(32) NOT NULL DEFAULT 'open',
+    outcome VARCHAR(16) NOT NULL DEFAULT '',
+    resolution_note TEXT NOT NULL DEFAULT '',
+    resolved_by UUID,
+    escalated_at TIMESTAMP WITH TIME ZONE,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT chk_purchase_disputes_opened_by_role CHECK (opened_by_role IN ('buyer', 'seller')),
+    CONSTRAINT chk_purchase_disputes_status CHECK (status IN ('open', 'awaiting_seller', 'awaiting_buyer', 'escalated', 'resolved')),
+    CONSTRAINT chk_purchase_disputes_outcome CHECK (outcome IN ('', 'cancel', 'complete', 'none'))
+);
+
+-- A purchase has at most one dispute that is not resolved
+CREATE UNIQUE INDEX IF NOT EXISTS uq_purchase_disputes_purchase_id_active ON purchase_disputes(purchase_id) WHERE status <> 'resolved';
+CREATE INDEX IF NOT EXISTS idx_purchase_disputes_purchase_id_created_at ON purchase_disputes(purchase_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_purchase_disputes_status_created_at ON purchase_disputes(status, created_at);
+
+-- Thread of a dispute; the first message is the reason it was opened
+CREATE TABLE IF NOT EXISTS purchase_dispute_messages (
+    id UUID PRIMARY KEY,
+    dispute_id UUID NOT NULL REFERENCES purchase_disputes(id) ON DELETE CASCADE,
+    sender_id UUID NOT NULL,
+    sender_role VARCHAR(16) NOT NULL,
+    message TEXT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    CONSTRAINT chk_purchase_dispute_messages_sender_role CHECK (sender_role IN ('buyer', 'seller'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_dispute_messages_dispute_id_created_at ON purchase_dispute_messages(dispute_id, created_at);
+
+-- Files attached to dispute messages, in the order they were attached
+CREATE TABLE IF NOT EXISTS purchase_dispute_evidence (
+    id UUID PRIMARY KEY,
+    dispute_id UUID NOT NULL REFERENCES purchase_disputes(id) ON DELETE CASCADE,
+    message_id UUID NOT NULL REFERENCES purchase_dispute_messages(id) ON DELETE CASCADE,
+    file_id UUID NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_purchase_dispute_evidence_dispute_id ON purchase_dispute_evidence(dispute_id);
+
+COMMENT ON TABLE purchase_disputes IS 'Stores disputes between the buyer and sellers of a purchase and how an admin resolved them';
+COMMENT ON TABLE purchase_dispute_messages IS 'Stores the message thread of each dispute';
+COMMENT ON TABLE purchase_dispute_evidence IS 'Stores the evidence files attached to dispute messages';
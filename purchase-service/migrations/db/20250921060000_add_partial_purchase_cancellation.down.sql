@@ -0,0 +1,13 @@
+-- Cancelled payments and shipments cannot be told apart from unpaid ones any more
+UPDATE purchase_shipments SET status = 'pending' WHERE status = 'cancelled';
+UPDATE purchase_payment_details SET status = 'pending_payment' WHERE status = 'cancelled';
+
+ALTER TABLE purchase_shipments DROP CONSTRAINT IF EXISTS chk_purchase_shipments_status;
+ALTER TABLE purchase_shipments
+    ADD CONSTRAINT chk_purchase_shipments_status CHECK (status IN ('pending', 'shipped', 'received'));
+
+ALTER TABLE purchase_payment_details DROP CONSTRAINT IF EXISTS chk_purchase_payment_details_status;
+ALTER TABLE purchase_payment_details
+    ADD CONSTRAINT chk_purchase_payment_details_status CHECK (status IN ('pending_payment', 'proof_uploaded', 'confirmed'));
+
+COMMENT ON COLUMN purchase_payment_details.status IS 'Payment status towards this seller: pending_payment, proof_uploaded or confirmed';
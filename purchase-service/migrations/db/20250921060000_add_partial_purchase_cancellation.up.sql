@@ -0,0 +1,11 @@
+-- The payments to sellers that never confirmed can be called off once other
+-- sellers of the purchase confirmed theirs
+ALTER TABLE purchase_payment_details DROP CONSTRAINT IF EXISTS chk_purchase_payment_details_status;
+ALTER TABLE purchase_payment_details
+    ADD CONSTRAINT chk_purchase_payment_details_status CHECK (status IN ('pending_payment', 'proof_uploaded', 'confirmed', 'cancelled'));
+
+ALTER TABLE purchase_shipments DROP CONSTRAINT IF EXISTS chk_purchase_shipments_status;
+ALTER TABLE purchase_shipments
+    ADD CONSTRAINT chk_purchase_shipments_status CHECK (status IN ('pending', 'shipped', 'received', 'cancelled'));
+
+COMMENT ON COLUMN purchase_payment_details.status IS 'Payment status towards this seller: pending_payment, proof_uploaded, confirmed or cancelled';
@@ -0,0 +1,6 @@
+ALTER TABLE purchase_payment_details DROP CONSTRAINT IF EXISTS chk_purchase_payment_details_refund_status;
+
+ALTER TABLE purchase_payment_details
+    DROP COLUMN IF EXISTS refund_decided_at,
+    DROP COLUMN IF EXISTS refund_reason,
+    DROP COLUMN IF EXISTS refund_status;
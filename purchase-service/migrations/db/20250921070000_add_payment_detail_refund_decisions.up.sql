@@ -0,0 +1,23 @@
+-- Every seller of a purchase decides on a refund request for their own payment
+ALTER TABLE purchase_payment_details
+    ADD COLUMN IF NOT EXISTS refund_status VARCHAR(16) NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS refund_reason TEXT NOT NULL DEFAULT '',
+    ADD COLUMN IF NOT EXISTS refund_decided_at TIMESTAMP WITH TIME ZONE;
+
+ALTER TABLE purchase_payment_details DROP CONSTRAINT IF EXISTS chk_purchase_payment_details_refund_status;
+ALTER TABLE purchase_payment_details
+    ADD CONSTRAINT chk_purchase_payment_details_refund_status CHECK (refund_status IN ('', 'requested', 'approved', 'rejected'));
+
+-- Refunds decided before take over the decision of the whole request
+UPDATE purchase_payment_details d
+SET refund_status = r.status,
+    refund_reason = r.resolution_reason,
+    refund_decided_at = r.resolved_at
+FROM (
+    SELECT DISTINCT ON (purchase_id) purchase_id, status, resolution_reason, resolved_at
+    FROM purchase_refunds
+    ORDER BY purchase_id, created_at DESC
+) r
+WHERE d.purchase_id = r.purchase_id AND d.status = 'confirmed';
+
+COMMENT ON COLUMN purchase_payment_details.refund_status IS 'Seller''s decision on the latest refund request: requested, approved or rejected; empty if none was made';
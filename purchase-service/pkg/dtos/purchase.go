@@ -1,11 +1,19 @@
 package dtos
 
+import (
+	"purchase-service/pkg/money"
+	"time"
+)
+
 // API Request DTOs
 type CreatePurchaseRequest struct {
 	PurchasedItems []PurchaseItemRequest `json:"purchasedItems" validate:"required,min=1,dive"`
 	SenderName     string                `json:"senderName" validate:"required,min=4,max=55"`
 	SenderContactType    string          `json:"senderContactType" validate:"required,oneof=email phone"`
 	SenderContactDetail  string          `json:"senderContactDetail" validate:"required"`
+	VoucherCode          string          `json:"voucherCode" validate:"omitempty,max=32"`
+	ShippingAddressID    string          `json:"shippingAddressId" validate:"omitempty,uuid"`
+	Shipping             []ShippingRequest `json:"shipping" validate:"omitempty,dive"`
 }
 
 type PurchaseItemRequest struct {
@@ -13,6 +21,139 @@ type PurchaseItemRequest struct {
 	Qty       int    `json:"qty" validate:"required,min=1"`
 }
 
+// AddCartItemRequest adds a product to the cart, on top of the qty already in it
+type AddCartItemRequest struct {
+	ProductID string `json:"productId" validate:"required"`
+	Qty       int    `json:"qty" validate:"required,min=1"`
+}
+
+type UpdateCartItemRequest struct {
+	Qty int `json:"qty" validate:"required,min=1"`
+}
+
+// ShippingRequest picks the shipping method for one seller's items. Sellers
+// left out are shipped with the default method.
+type ShippingRequest struct {
+	SellerID string `json:"sellerId" validate:"required,uuid"`
+	Method   string `json:"method" validate:"required,max=32"`
+}
+
+// CheckoutRequest turns the cart into a purchase; the items come from the cart
+type CheckoutRequest struct {
+	SenderName          string            `json:"senderName" validate:"required,min=4,max=55"`
+	SenderContactType   string            `json:"senderContactType" validate:"required,oneof=email phone"`
+	SenderContactDetail string            `json:"senderContactDetail" validate:"required"`
+	VoucherCode         string            `json:"voucherCode" validate:"omitempty,max=32"`
+	ShippingAddressID   string            `json:"shippingAddressId" validate:"omitempty,uuid"`
+	Shipping            []ShippingRequest `json:"shipping" validate:"omitempty,dive"`
+}
+
+// PaymentProofRequest attaches proof files to the transfer made to one seller.
+// SellerID may be left out when the purchase has a single seller.
 type PaymentProofRequest struct {
-	FileIds []string `json:"fileIds" validate:"required,min=1,dive,required"`
+	SellerID string   `json:"sellerId" validate:"omitempty,uuid"`
+	FileIds  []string `json:"fileIds" validate:"required,min=1,max=10,dive,required,uuid"`
+}
+
+type RejectPaymentRequest struct {
+	Reason string `json:"reason" validate:"required,min=1,max=255"`
+}
+
+type CancelPurchaseRequest struct {
+	Reason string `json:"reason" validate:"max=255"`
+}
+
+type RefundRequest struct {
+	Reason string `json:"reason" validate:"required,min=1,max=255"`
+}
+
+type RejectRefundRequest struct {
+	Reason string `json:"reason" validate:"required,min=1,max=255"`
+}
+
+type ShipOrderRequest struct {
+	TrackingNumber string `json:"trackingNumber" validate:"required,max=64"`
+}
+
+// CreateReviewRequest rates a product of a completed purchase. FileIds are
+// images the buyer uploaded to the file store.
+type CreateReviewRequest struct {
+	ProductID string   `json:"productId" validate:"required"`
+	Rating    int      `json:"rating" validate:"required,min=1,max=5"`
+	Comment   string   `json:"comment" validate:"max=2000"`
+	FileIds   []string `json:"fileIds" validate:"omitempty,max=5,dive,required,uuid"`
+}
+
+// DisputeMessageRequest opens a dispute or adds to its thread. FileIds are
+// evidence the sender uploaded to the file store.
+type DisputeMessageRequest struct {
+	Message string   `json:"message" validate:"required,min=1,max=2000"`
+	FileIds []string `json:"fileIds" validate:"omitempty,max=5,dive,required,uuid"`
+}
+
+// ResolveDisputeRequest closes a dispute. Outcome cancel or complete forces
+// the purchase into that status, none leaves it as it is.
+type ResolveDisputeRequest struct {
+	Outcome string `json:"outcome" validate:"required,oneof=cancel complete none"`
+	Note    string `json:"note" validate:"required,min=1,max=1000"`
+}
+
+// ConfirmReceiptRequest marks the parcel from one seller as received.
+// SellerID may be left out when the purchase has a single seller or no
+// parcels at all.
+type ConfirmReceiptRequest struct {
+	SellerID string `json:"sellerId" validate:"omitempty,uuid"`
+}
+
+// CreateVoucherRequest creates a seller or platform voucher. Percentage and
+// MaxDiscount apply to percentage vouchers, Amount to fixed ones; limits and
+// dates left out are unlimited.
+type CreateVoucherRequest struct {
+	Code         string        `json:"code" validate:"required,min=3,max=32,alphanum"`
+	Type         string        `json:"type" validate:"required,oneof=percentage fixed"`
+	Percentage   int           `json:"percentage" validate:"omitempty,min=1,max=100"`
+	Amount       money.Amount  `json:"amount"`
+	MaxDiscount  *money.Amount `json:"maxDiscount"`
+	Currency     string        `json:"currency" validate:"omitempty,len=3,uppercase"`
+	MinSpend     money.Amount  `json:"minSpend"`
+	UsageLimit   *int          `json:"usageLimit" validate:"omitempty,min=1"`
+	PerUserLimit *int          `json:"perUserLimit" validate:"omitempty,min=1"`
+	StartsAt     *time.Time    `json:"startsAt"`
+	EndsAt       *time.Time    `json:"endsAt"`
+}
+
+// ListPurchasesFilter holds the query parameters accepted by GET /purchase
+type ListPurchasesFilter struct {
+	Cursor    string
+	Limit     int
+	Status    string
+	From      *time.Time
+	To        *time.Time
+	SellerID  string
+	ProductID string
+	MinTotal  *money.Amount
+	MaxTotal  *money.Amount
+}
+
+// ExportPurchasesFilter holds the query parameters accepted by the export endpoints
+type ExportPurchasesFilter struct {
+	Format string
+	Status string
+	From   *time.Time
+	To     *time.Time
+}
+
+// SellerAnalyticsFilter holds the query parameters accepted by GET /seller/analytics
+type SellerAnalyticsFilter struct {
+	From     *time.Time
+	To       *time.Time
+	Interval string
+	Currency string
+	Limit    int
+}
+
+// PurchaseIDsRequest is the body of POST /purchase/batch, which other services
+// call to check which purchases exist
+type PurchaseIDsRequest struct {
+	PurchaseIDs []string `json:"purchaseIds" validate:"required,min=1,max=100,dive,uuid"`
 }
@@ -0,0 +1,57 @@
+package entities
+
+import (
+	"purchase-service/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Cart is a buyer's shopping cart, kept until it is checked out. Version is
+// bumped by every change to its items, so checkout can tell whether the cart
+// changed while it was being validated.
+type Cart struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null"`
+	Version   int       `gorm:"not null;default:0"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// CartItem is a product in a cart. Price is the unit price the buyer last
+// saw, which checkout compares against the current price.
+type CartItem struct {
+	ID        uuid.UUID    `gorm:"type:uuid;primaryKey"`
+	CartID    uuid.UUID    `gorm:"type:uuid;not null"`
+	ProductID string       `gorm:"type:varchar(255);not null"`
+	Qty       int          `gorm:"not null"`
+	Price     money.Amount `gorm:"type:decimal(10,2);not null"`
+	Currency  string       `gorm:"type:varchar(3);not null;default:IDR"`
+	CreatedAt time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt time.Time    `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (c *Cart) BeforeCreate(tx *gorm.DB) (err error) {
+	if c.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		c.ID = id
+	}
+	return nil
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (i *CartItem) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		i.ID = id
+	}
+	return nil
+}
@@ -0,0 +1,114 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DisputeStatus is where a dispute stands; every status but resolved is active
+type DisputeStatus string
+
+const (
+	DisputeStatusOpen           DisputeStatus = "open"
+	DisputeStatusAwaitingSeller DisputeStatus = "awaiting_seller"
+	DisputeStatusAwaitingBuyer  DisputeStatus = "awaiting_buyer"
+	DisputeStatusEscalated      DisputeStatus = "escalated"
+	DisputeStatusResolved       DisputeStatus = "resolved"
+)
+
+// DisputeRole is the side of a purchase a dispute participant is on
+type DisputeRole string
+
+const (
+	DisputeRoleBuyer  DisputeRole = "buyer"
+	DisputeRoleSeller DisputeRole = "seller"
+)
+
+// DisputeOutcome is what an admin resolution did to the purchase
+type DisputeOutcome string
+
+const (
+	DisputeOutcomeCancel   DisputeOutcome = "cancel"   // Purchase forced to cancelled
+	DisputeOutcomeComplete DisputeOutcome = "complete" // Purchase forced to completed
+	DisputeOutcomeNone     DisputeOutcome = "none"     // Purchase left as it was
+)
+
+// PurchaseDispute is a disagreement between the buyer and the sellers of a
+// purchase, such as a transfer the seller says never arrived. A purchase has
+// at most one active dispute.
+type PurchaseDispute struct {
+	ID             uuid.UUID      `gorm:"type:uuid;primaryKey"`
+	PurchaseID     uuid.UUID      `gorm:"type:uuid;not null"`
+	OpenedBy       uuid.UUID      `gorm:"type:uuid;not null"`
+	OpenedByRole   DisputeRole    `gorm:"type:varchar(16);not null"`
+	Status         DisputeStatus  `gorm:"type:varchar(32);not null;default:open"`
+	Outcome        DisputeOutcome `gorm:"type:varchar(16);not null;default:''"` // Set on resolution
+	ResolutionNote string         `gorm:"type:text;not null;default:''"`
+	ResolvedBy     *uuid.UUID     `gorm:"type:uuid"` // Admin who resolved the dispute
+	EscalatedAt    *time.Time     `gorm:"column:escalated_at"`
+	ResolvedAt     *time.Time     `gorm:"column:resolved_at"`
+	CreatedAt      time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// PurchaseDisputeMessage is one entry of a dispute's thread; the first one is
+// the reason the dispute was opened
+type PurchaseDisputeMessage struct {
+	ID         uuid.UUID   `gorm:"type:uuid;primaryKey"`
+	DisputeID  uuid.UUID   `gorm:"type:uuid;not null"`
+	SenderID   uuid.UUID   `gorm:"type:uuid;not null"`
+	SenderRole DisputeRole `gorm:"type:varchar(16);not null"`
+	Message    string      `gorm:"type:text;not null"`
+	CreatedAt  time.Time   `gorm:"column:created_at;autoCreateTime"`
+}
+
+// PurchaseDisputeEvidence is a file attached to a dispute message, such as a
+// screenshot of a bank transfer
+type PurchaseDisputeEvidence struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	DisputeID uuid.UUID `gorm:"type:uuid;not null"`
+	MessageID uuid.UUID `gorm:"type:uuid;not null"`
+	FileID    uuid.UUID `gorm:"type:uuid;not null"`
+	Position  int       `gorm:"not null;default:0"` // Order the files were attached in
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+func (PurchaseDisputeEvidence) TableName() string { return "purchase_dispute_evidence" }
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (d *PurchaseDispute) BeforeCreate(tx *gorm.DB) (err error) {
+	if d.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		d.ID = id
+	}
+	return nil
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (m *PurchaseDisputeMessage) BeforeCreate(tx *gorm.DB) (err error) {
+	if m.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		m.ID = id
+	}
+	return nil
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (e *PurchaseDisputeEvidence) BeforeCreate(tx *gorm.DB) (err error) {
+	if e.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		e.ID = id
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// IdempotencyKey remembers the outcome of a request sent with an
+// Idempotency-Key header so a retry gets the original response back
+type IdempotencyKey struct {
+	ID           uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID       uuid.UUID `gorm:"type:uuid;not null"`
+	Key          string    `gorm:"type:varchar(255);not null"`
+	RequestHash  string    `gorm:"type:varchar(64);not null"`
+	StatusCode   int       `gorm:"not null;default:0"` // 0 while the request is still being processed
+	ResponseBody []byte    `gorm:"type:bytea"`
+	ExpiresAt    time.Time `gorm:"not null"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// Completed reports whether a response has been stored for the key
+func (k *IdempotencyKey) Completed() bool {
+	return k.StatusCode != 0
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (k *IdempotencyKey) BeforeCreate(tx *gorm.DB) (err error) {
+	if k.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		k.ID = id
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PurchaseInvoice is the invoice a seller issues for their part of a purchase.
+// Sequence counts the seller's invoices from 1 without gaps; Number is the
+// human-readable form printed on the document.
+type PurchaseInvoice struct {
+	ID         uuid.UUID `gorm:"type:uuid;primaryKey"`
+	PurchaseID uuid.UUID `gorm:"type:uuid;not null"`
+	SellerID   uuid.UUID `gorm:"type:uuid;not null"`
+	Sequence   int       `gorm:"not null"`
+	Number     string    `gorm:"type:varchar(64);not null"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime"` // Issue date
+}
+
+// SellerInvoiceCounter holds the last invoice sequence allocated to a seller
+type SellerInvoiceCounter struct {
+	SellerID     uuid.UUID `gorm:"type:uuid;primaryKey"`
+	LastSequence int       `gorm:"not null"`
+	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (pi *PurchaseInvoice) BeforeCreate(tx *gorm.DB) (err error) {
+	if pi.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		pi.ID = id
+	}
+	return nil
+}
@@ -1,47 +1,156 @@
 package entities
 
 import (
+	"purchase-service/pkg/money"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
-// Purchase represents a purchase order
+// PurchaseStatus is the lifecycle state of a purchase
+type PurchaseStatus string
+
+const (
+	PurchaseStatusPendingPayment  PurchaseStatus = "pending_payment"
+	PurchaseStatusProofUploaded   PurchaseStatus = "proof_uploaded"
+	PurchaseStatusConfirmed       PurchaseStatus = "confirmed"
+	PurchaseStatusCompleted       PurchaseStatus = "completed"
+	PurchaseStatusCancelled       PurchaseStatus = "cancelled"
+	PurchaseStatusExpired         PurchaseStatus = "expired"
+	PurchaseStatusRefundRequested PurchaseStatus = "refund_requested"
+	PurchaseStatusRefunded        PurchaseStatus = "refunded"
+)
+
+// Purchase represents a purchase order. TotalPrice is what the buyer pays:
+// the items' line totals less the voucher Discount, plus the shipping cost
+// of every shipment.
 type Purchase struct {
-	ID              uuid.UUID `gorm:"type:uuid;primaryKey"`
-	UserID          uuid.UUID `gorm:"type:uuid;not null"`
-	PaymentProofIds string    `gorm:"type:text"` // JSON array of file IDs
-	CreatedAt       time.Time `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt       time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	ID                uuid.UUID      `gorm:"type:uuid;primaryKey"`
+	UserID            uuid.UUID      `gorm:"type:uuid;not null"`
+	Status            PurchaseStatus `gorm:"type:varchar(32);not null;default:pending_payment"`
+	TotalPrice        money.Amount   `gorm:"type:decimal(10,2);not null"`
+	Discount          money.Amount   `gorm:"type:decimal(10,2);not null;default:0"`
+	VoucherCode       string         `gorm:"type:varchar(32);not null;default:''"` // Empty when no voucher was applied
+	Currency          string         `gorm:"type:varchar(3);not null;default:IDR"`
+	ProofUploadedAt   *time.Time     `gorm:"column:proof_uploaded_at"`
+	ConfirmedAt       *time.Time     `gorm:"column:confirmed_at"`
+	CompletedAt       *time.Time     `gorm:"column:completed_at"`
+	CancelledAt       *time.Time     `gorm:"column:cancelled_at"`
+	ExpiredAt         *time.Time     `gorm:"column:expired_at"`
+	RefundRequestedAt *time.Time     `gorm:"column:refund_requested_at"`
+	RefundedAt        *time.Time     `gorm:"column:refunded_at"`
+	StatusReason      string         `gorm:"type:text"` // Why the purchase last changed status, if given
+	CreatedAt         time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt         time.Time      `gorm:"column:updated_at;autoUpdateTime"`
 }
 
-// PurchaseItem represents an item in a purchase
+// PurchaseItem represents an item in a purchase. Qty is the quantity the
+// buyer ordered; StockSnapshot is the product stock seen at checkout.
 type PurchaseItem struct {
-	ID           uuid.UUID `gorm:"type:uuid;primaryKey"`
-	PurchaseID   uuid.UUID `gorm:"type:uuid;not null"`
-	ProductID    string    `gorm:"type:varchar(255);not null"`
-	Name         string    `gorm:"type:varchar(255);not null"`
-	Category     string    `gorm:"type:varchar(255);not null"`
-	Qty          int       `gorm:"not null"`
-	Price        float64   `gorm:"type:decimal(10,2);not null"`
-	SKU          string    `gorm:"type:varchar(255)"`
-	FileID       string    `gorm:"type:varchar(255)"`
-	FileURI      string    `gorm:"type:text"`
-	FileThumbnailURI string `gorm:"type:text"`
-	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	ID               uuid.UUID    `gorm:"type:uuid;primaryKey"`
+	PurchaseID       uuid.UUID    `gorm:"type:uuid;not null"`
+	ProductID        string       `gorm:"type:varchar(255);not null"`
+	SellerID         uuid.UUID    `gorm:"type:uuid"`
+	Name             string       `gorm:"type:varchar(255);not null"`
+	Category         string       `gorm:"type:varchar(255);not null"`
+	Qty              int          `gorm:"not null"`
+	Price            money.Amount `gorm:"type:decimal(10,2);not null"` // Unit price
+	LineTotal        money.Amount `gorm:"type:decimal(10,2);not null"`
+	StockSnapshot    *int         `gorm:"column:stock_snapshot"`
+	SKU              string       `gorm:"type:varchar(255)"`
+	FileID           string       `gorm:"type:varchar(255)"`
+	FileURI          string       `gorm:"type:text"`
+	FileThumbnailURI string       `gorm:"type:text"`
+	CreatedAt        time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt        time.Time    `gorm:"column:updated_at;autoUpdateTime"`
 }
 
 // PurchaseSender represents sender information for a purchase
 type PurchaseSender struct {
-	ID                   uuid.UUID `gorm:"type:uuid;primaryKey"`
-	PurchaseID           uuid.UUID `gorm:"type:uuid;not null"`
-	SenderName           string    `gorm:"type:varchar(255);not null"`
-	SenderContactType    string    `gorm:"type:varchar(50);not null"` // "email" or "phone"
-	SenderContactDetail  string    `gorm:"type:varchar(255);not null"`
-	CreatedAt            time.Time `gorm:"column:created_at;autoCreateTime"`
-	UpdatedAt            time.Time `gorm:"column:updated_at;autoUpdateTime"`
+	ID                  uuid.UUID `gorm:"type:uuid;primaryKey"`
+	PurchaseID          uuid.UUID `gorm:"type:uuid;not null"`
+	SenderName          string    `gorm:"type:varchar(255);not null"`
+	SenderContactType   string    `gorm:"type:varchar(50);not null"` // "email" or "phone"
+	SenderContactDetail string    `gorm:"type:varchar(255);not null"`
+	CreatedAt           time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt           time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// PurchasePaymentDetail snapshots a seller's bank account and subtotal at
+// checkout. Status tracks the buyer's transfer to this seller only and is one
+// of pending_payment, proof_uploaded or confirmed.
+type PurchasePaymentDetail struct {
+	ID                uuid.UUID      `gorm:"type:uuid;primaryKey"`
+	PurchaseID        uuid.UUID      `gorm:"type:uuid;not null"`
+	SellerID          uuid.UUID      `gorm:"type:uuid;not null"`
+	BankAccountName   string         `gorm:"type:varchar(255);not null"`
+	BankAccountHolder string         `gorm:"type:varchar(255);not null"`
+	BankAccountNumber string         `gorm:"type:varchar(255);not null"`
+	TotalPrice        money.Amount   `gorm:"type:decimal(10,2);not null"`           // Payable to the seller, after Discount and with shipping
+	Discount          money.Amount   `gorm:"type:decimal(10,2);not null;default:0"` // The seller's share of the voucher discount
+	Status            PurchaseStatus `gorm:"type:varchar(32);not null;default:pending_payment"`
+	ProofUploadedAt   *time.Time     `gorm:"column:proof_uploaded_at"`
+	ConfirmedAt       *time.Time     `gorm:"column:confirmed_at"`
+	StatusReason      string         `gorm:"type:text;not null;default:''"`        // Seller's reason for rejecting the last proof
+	RefundStatus      RefundStatus   `gorm:"type:varchar(16);not null;default:''"` // Seller's decision on the latest refund request, empty if none was made
+	RefundReason      string         `gorm:"type:text;not null;default:''"`        // Seller's reason for rejecting the refund
+	RefundDecidedAt   *time.Time     `gorm:"column:refund_decided_at"`
+	CreatedAt         time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt         time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// PaymentProofStatus is the review state of an uploaded payment proof
+type PaymentProofStatus string
+
+const (
+	PaymentProofStatusPending  PaymentProofStatus = "pending"
+	PaymentProofStatusAccepted PaymentProofStatus = "accepted"
+	PaymentProofStatusRejected PaymentProofStatus = "rejected"
+	PaymentProofStatusReplaced PaymentProofStatus = "replaced" // superseded by a later upload before review
+)
+
+// PurchasePaymentProof is a file the buyer uploaded as proof of a transfer.
+// SellerID is the seller the transfer was made to, or nil when the proof
+// covers the whole purchase.
+type PurchasePaymentProof struct {
+	ID         uuid.UUID          `gorm:"type:uuid;primaryKey"`
+	PurchaseID uuid.UUID          `gorm:"type:uuid;not null"`
+	SellerID   *uuid.UUID         `gorm:"type:uuid"`
+	FileID     uuid.UUID          `gorm:"type:uuid;not null"`
+	Status     PaymentProofStatus `gorm:"type:varchar(16);not null;default:pending"`
+	ReviewedAt *time.Time         `gorm:"column:reviewed_at"`
+	CreatedAt  time.Time          `gorm:"column:created_at;autoCreateTime"` // Upload time
+	UpdatedAt  time.Time          `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// NotificationType identifies what a notification is about
+type NotificationType string
+
+const (
+	NotificationPaymentConfirmed  NotificationType = "payment_confirmed"
+	NotificationPaymentRejected   NotificationType = "payment_rejected"
+	NotificationPurchaseCancelled NotificationType = "purchase_cancelled"
+	NotificationRefundRequested   NotificationType = "refund_requested"
+	NotificationRefundApproved    NotificationType = "refund_approved"
+	NotificationRefundRejected    NotificationType = "refund_rejected"
+	NotificationOrderShipped      NotificationType = "order_shipped"
+	NotificationOrderReceived     NotificationType = "order_received"
+	NotificationReviewReceived    NotificationType = "review_received"
+	NotificationDisputeOpened     NotificationType = "dispute_opened"
+	NotificationDisputeMessage    NotificationType = "dispute_message"
+	NotificationDisputeEscalated  NotificationType = "dispute_escalated"
+	NotificationDisputeResolved   NotificationType = "dispute_resolved"
+)
+
+// PurchaseNotification is a message for a buyer or seller about a purchase
+type PurchaseNotification struct {
+	ID         uuid.UUID        `gorm:"type:uuid;primaryKey"`
+	UserID     uuid.UUID        `gorm:"type:uuid;not null"`
+	PurchaseID uuid.UUID        `gorm:"type:uuid;not null"`
+	Type       NotificationType `gorm:"type:varchar(50);not null"`
+	Message    string           `gorm:"type:text;not null"`
+	CreatedAt  time.Time        `gorm:"column:created_at;autoCreateTime"`
 }
 
 // BeforeCreate ensures UUID v7 is set by the application
@@ -79,3 +188,39 @@ func (ps *PurchaseSender) BeforeCreate(tx *gorm.DB) (err error) {
 	}
 	return nil
 }
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (pd *PurchasePaymentDetail) BeforeCreate(tx *gorm.DB) (err error) {
+	if pd.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		pd.ID = id
+	}
+	return nil
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (pp *PurchasePaymentProof) BeforeCreate(tx *gorm.DB) (err error) {
+	if pp.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		pp.ID = id
+	}
+	return nil
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (pn *PurchaseNotification) BeforeCreate(tx *gorm.DB) (err error) {
+	if pn.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		pn.ID = id
+	}
+	return nil
+}
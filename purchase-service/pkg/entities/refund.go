@@ -0,0 +1,45 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// RefundStatus is the state of a buyer's refund request
+type RefundStatus string
+
+const (
+	RefundStatusRequested RefundStatus = "requested"
+	RefundStatusApproved  RefundStatus = "approved"
+	RefundStatusRejected  RefundStatus = "rejected"
+)
+
+// PurchaseRefund is a buyer's request to get their payment back after it was
+// confirmed, together with the outcome of it. Every seller of the purchase
+// decides on their own payment detail; the request is approved once all of
+// them approved and rejected once all decided and any of them rejected.
+type PurchaseRefund struct {
+	ID               uuid.UUID    `gorm:"type:uuid;primaryKey"`
+	PurchaseID       uuid.UUID    `gorm:"type:uuid;not null"`
+	Status           RefundStatus `gorm:"type:varchar(16);not null;default:requested"`
+	Reason           string       `gorm:"type:text;not null"`
+	ResolutionReason string       `gorm:"type:text;not null;default:''"` // Sellers' reasons for rejecting
+	ResolvedBy       *uuid.UUID   `gorm:"type:uuid"`                     // Seller who made the last decision, or the admin who resolved a dispute
+	ResolvedAt       *time.Time   `gorm:"column:resolved_at"`
+	CreatedAt        time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt        time.Time    `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (pr *PurchaseRefund) BeforeCreate(tx *gorm.DB) (err error) {
+	if pr.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		pr.ID = id
+	}
+	return nil
+}
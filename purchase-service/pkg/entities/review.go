@@ -0,0 +1,66 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ProductReview is a buyer's rating of a product they received. A product can
+// be reviewed once per purchase, and only after the purchase is completed.
+type ProductReview struct {
+	ID          uuid.UUID `gorm:"type:uuid;primaryKey"`
+	PurchaseID  uuid.UUID `gorm:"type:uuid;not null"`
+	ProductID   string    `gorm:"type:varchar(255);not null"`
+	SellerID    uuid.UUID `gorm:"type:uuid;not null"`
+	UserID      uuid.UUID `gorm:"type:uuid;not null"`            // Reviewer, the buyer of the purchase
+	ProductName string    `gorm:"type:varchar(255);not null"`    // From the purchase item snapshot
+	Rating      int       `gorm:"not null"`                      // 1 to 5 stars
+	Comment     string    `gorm:"type:text;not null;default:''"` // Empty for a rating without text
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// ProductReviewImage is a photo the buyer attached to a review
+type ProductReviewImage struct {
+	ID        uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ReviewID  uuid.UUID `gorm:"type:uuid;not null"`
+	FileID    uuid.UUID `gorm:"type:uuid;not null"`
+	Position  int       `gorm:"not null;default:0"` // Order the buyer attached the images in
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime"`
+}
+
+// SellerRating is the running total of the ratings a seller received, updated
+// with every new review so the average never has to be recomputed
+type SellerRating struct {
+	SellerID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ReviewCount int       `gorm:"not null;default:0"`
+	RatingTotal int       `gorm:"not null;default:0"` // Sum of the stars of all reviews
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt   time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (r *ProductReview) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		r.ID = id
+	}
+	return nil
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (i *ProductReviewImage) BeforeCreate(tx *gorm.DB) (err error) {
+	if i.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		i.ID = id
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package entities
+
+import (
+	"purchase-service/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ShipmentStatus is how far a seller's parcel of a purchase got
+type ShipmentStatus string
+
+const (
+	ShipmentStatusPending   ShipmentStatus = "pending"
+	ShipmentStatusShipped   ShipmentStatus = "shipped"
+	ShipmentStatusReceived  ShipmentStatus = "received"
+	ShipmentStatusCancelled ShipmentStatus = "cancelled" // The seller's part of the purchase was called off
+)
+
+// PurchaseShippingAddress snapshots the buyer's address book entry at
+// checkout, so later edits to the address book leave the purchase alone
+type PurchaseShippingAddress struct {
+	ID            uuid.UUID `gorm:"type:uuid;primaryKey"`
+	PurchaseID    uuid.UUID `gorm:"type:uuid;not null"`
+	AddressID     uuid.UUID `gorm:"type:uuid;not null"` // Address book entry the snapshot was taken from
+	RecipientName string    `gorm:"type:varchar(64);not null"`
+	Phone         string    `gorm:"type:varchar(20);not null"`
+	AddressLine1  string    `gorm:"type:varchar(255);not null"`
+	AddressLine2  string    `gorm:"type:varchar(255);not null;default:''"`
+	City          string    `gorm:"type:varchar(64);not null"`
+	Province      string    `gorm:"type:varchar(64);not null"`
+	PostalCode    string    `gorm:"type:varchar(10);not null"`
+	Country       string    `gorm:"type:varchar(2);not null"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// PurchaseShipment is the delivery of one seller's items of a purchase. Cost
+// is paid to the seller on top of their items.
+type PurchaseShipment struct {
+	ID             uuid.UUID      `gorm:"type:uuid;primaryKey"`
+	PurchaseID     uuid.UUID      `gorm:"type:uuid;not null"`
+	SellerID       uuid.UUID      `gorm:"type:uuid;not null"`
+	Method         string         `gorm:"type:varchar(32);not null"`
+	Cost           money.Amount   `gorm:"type:decimal(10,2);not null"`
+	TrackingNumber string         `gorm:"type:varchar(64);not null;default:''"`
+	Status         ShipmentStatus `gorm:"type:varchar(16);not null;default:pending"`
+	ShippedAt      *time.Time     `gorm:"column:shipped_at"`
+	ReceivedAt     *time.Time     `gorm:"column:received_at"`
+	CreatedAt      time.Time      `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt      time.Time      `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (a *PurchaseShippingAddress) BeforeCreate(tx *gorm.DB) (err error) {
+	if a.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		a.ID = id
+	}
+	return nil
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (s *PurchaseShipment) BeforeCreate(tx *gorm.DB) (err error) {
+	if s.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		s.ID = id
+	}
+	return nil
+}
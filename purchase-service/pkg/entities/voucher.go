@@ -0,0 +1,78 @@
+package entities
+
+import (
+	"purchase-service/pkg/money"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VoucherType is how a voucher's discount is worked out
+type VoucherType string
+
+const (
+	VoucherTypePercentage VoucherType = "percentage"
+	VoucherTypeFixed      VoucherType = "fixed"
+)
+
+// Voucher is a discount code. A seller's voucher only discounts that seller's
+// items; a platform voucher (no SellerID) discounts the whole purchase. Limits
+// left nil are unlimited, and UsedCount counts redemptions not released by a
+// cancellation or expiry.
+type Voucher struct {
+	ID           uuid.UUID     `gorm:"type:uuid;primaryKey"`
+	Code         string        `gorm:"type:varchar(32);not null"` // Stored in upper case
+	SellerID     *uuid.UUID    `gorm:"type:uuid"`
+	Type         VoucherType   `gorm:"type:varchar(16);not null"`
+	Percentage   int           `gorm:"not null;default:0"`                    // Whole percent off, for percentage vouchers
+	Amount       money.Amount  `gorm:"type:decimal(10,2);not null;default:0"` // Amount off, for fixed vouchers
+	MaxDiscount  *money.Amount `gorm:"type:decimal(10,2)"`                    // Cap of a percentage discount
+	Currency     string        `gorm:"type:varchar(3);not null;default:IDR"`
+	MinSpend     money.Amount  `gorm:"type:decimal(10,2);not null;default:0"` // On the discounted subtotal
+	UsageLimit   *int          `gorm:"column:usage_limit"`
+	PerUserLimit *int          `gorm:"column:per_user_limit"`
+	UsedCount    int           `gorm:"not null;default:0"`
+	StartsAt     *time.Time    `gorm:"column:starts_at"`
+	EndsAt       *time.Time    `gorm:"column:ends_at"`
+	DisabledAt   *time.Time    `gorm:"column:disabled_at"`
+	CreatedAt    time.Time     `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt    time.Time     `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// VoucherRedemption records the use of a voucher on a purchase. It is
+// released when the purchase is cancelled or expires, giving the use back.
+type VoucherRedemption struct {
+	ID         uuid.UUID    `gorm:"type:uuid;primaryKey"`
+	VoucherID  uuid.UUID    `gorm:"type:uuid;not null"`
+	PurchaseID uuid.UUID    `gorm:"type:uuid;not null"`
+	UserID     uuid.UUID    `gorm:"type:uuid;not null"`
+	Discount   money.Amount `gorm:"type:decimal(10,2);not null"`
+	ReleasedAt *time.Time   `gorm:"column:released_at"`
+	CreatedAt  time.Time    `gorm:"column:created_at;autoCreateTime"`
+	UpdatedAt  time.Time    `gorm:"column:updated_at;autoUpdateTime"`
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (v *Voucher) BeforeCreate(tx *gorm.DB) (err error) {
+	if v.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		v.ID = id
+	}
+	return nil
+}
+
+// BeforeCreate ensures UUID v7 is set by the application
+func (r *VoucherRedemption) BeforeCreate(tx *gorm.DB) (err error) {
+	if r.ID == uuid.Nil {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		r.ID = id
+	}
+	return nil
+}
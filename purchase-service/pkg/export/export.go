@@ -0,0 +1,62 @@
+// Package export writes tables as spreadsheet files row by row, so exports of
+// any size can be streamed to the client without holding them in memory.
+package export
+
+import (
+	"errors"
+	"io"
+)
+
+// Supported file formats
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// Cell is one value of a row. Numeric cells are stored as numbers in formats
+// that distinguish them, so spreadsheets can sum them up.
+type Cell struct {
+	Value   string
+	Numeric bool
+}
+
+func Text(value string) Cell {
+	return Cell{Value: value}
+}
+
+func Number(value string) Cell {
+	return Cell{Value: value, Numeric: true}
+}
+
+// Writer writes the rows of a single table. Close must be called to finish
+// the file; it does not close the underlying writer.
+type Writer interface {
+	WriteRow(cells []Cell) error
+	Close() error
+}
+
+// NewWriter returns a writer for the given format
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	}
+	return nil, ErrUnknownFormat
+}
+
+// IsValidFormat reports whether the format is supported
+func IsValidFormat(format string) bool {
+	return format == FormatCSV || format == FormatXLSX
+}
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -11,6 +12,33 @@ import (
 	"time"
 )
 
+// StatusError is returned when a service answers with an unexpected status code
+type StatusError struct {
+	Service    string
+	StatusCode int
+	Body       string
+}
+
+func newStatusError(service string, resp *http.Response) *StatusError {
+	body, _ := io.ReadAll(resp.Body)
+	return &StatusError{Service: service, StatusCode: resp.StatusCode, Body: string(body)}
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("%s returned status %d: %s", e.Service, e.StatusCode, e.Body)
+}
+
+// Message returns the "error" field of the response body, if there is one
+func (e *StatusError) Message() string {
+	var body struct {
+		Error string `json:"error"`
+	}
+	if err := json.Unmarshal([]byte(e.Body), &body); err != nil {
+		return ""
+	}
+	return body.Error
+}
+
 type Client struct {
 	baseURL        string
 	httpClient     *http.Client
@@ -53,8 +81,7 @@ func (c *Client) GetUserDetail(ctx context.Context, userID, authenticatedUserID
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("user service returned status %d: %s", resp.StatusCode, string(body))
+		return nil, newStatusError("user service", resp)
 	}
 
 	var user presenter.ExternalUserResponse
@@ -65,6 +92,119 @@ func (c *Client) GetUserDetail(ctx context.Context, userID, authenticatedUserID
 	return &user, nil
 }
 
+// GetUserDetails fetches many users from user service in a single request
+func (c *Client) GetUserDetails(ctx context.Context, userIDs []string, authenticatedUserID string) (map[string]*presenter.ExternalUserResponse, error) {
+	url := fmt.Sprintf("%s/user/batch", c.baseURL)
+
+	jsonBody, err := json.Marshal(map[string]interface{}{
+		"ids": userIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	c.setInternalHeaders(req, authenticatedUserID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError("user service", resp)
+	}
+
+	var users presenter.ExternalUsersResponse
+	if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	userMap := make(map[string]*presenter.ExternalUserResponse, len(users.Users))
+	for i := range users.Users {
+		userMap[users.Users[i].ID] = &users.Users[i]
+	}
+
+	return userMap, nil
+}
+
+// GetFileDetails fetches many files and their uploaders from user service in a single request
+func (c *Client) GetFileDetails(ctx context.Context, fileIDs []string, authenticatedUserID string) (map[string]*presenter.ExternalFileResponse, error) {
+	url := fmt.Sprintf("%s/file/batch", c.baseURL)
+
+	jsonBody, err := json.Marshal(map[string]interface{}{
+		"ids": fileIDs,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request body: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonBody))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	c.setInternalHeaders(req, authenticatedUserID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError("user service", resp)
+	}
+
+	var files presenter.ExternalFilesResponse
+	if err := json.NewDecoder(resp.Body).Decode(&files); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	fileMap := make(map[string]*presenter.ExternalFileResponse, len(files.Files))
+	for i := range files.Files {
+		fileMap[files.Files[i].ID] = &files.Files[i]
+	}
+
+	return fileMap, nil
+}
+
+// GetAddressDetail fetches an address book entry and its owner from user service
+func (c *Client) GetAddressDetail(ctx context.Context, addressID, authenticatedUserID string) (*presenter.ExternalAddressResponse, error) {
+	url := fmt.Sprintf("%s/address/%s", c.baseURL, addressID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	c.setInternalHeaders(req, authenticatedUserID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newStatusError("user service", resp)
+	}
+
+	var address presenter.ExternalAddressResponse
+	if err := json.NewDecoder(resp.Body).Decode(&address); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	return &address, nil
+}
+
 // GetProductDetail fetches product details from product service
 func (c *Client) GetProductDetail(ctx context.Context, productID, authenticatedUserID string) (*presenter.ProductResponse, error) {
 	url := fmt.Sprintf("%s/product/%s", c.baseURL, productID)
@@ -84,8 +224,7 @@ func (c *Client) GetProductDetail(ctx context.Context, productID, authenticatedU
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("product service returned status %d: %s", resp.StatusCode, string(body))
+		return nil, newStatusError("product service", resp)
 	}
 
 	var product presenter.ProductResponse
@@ -98,6 +237,16 @@ func (c *Client) GetProductDetail(ctx context.Context, productID, authenticatedU
 
 // GetProductDetails fetches multiple product details in parallel
 func (c *Client) GetProductDetails(ctx context.Context, productIDs []string, authenticatedUserID string) (map[string]*presenter.ProductResponse, error) {
+	return c.getProductDetails(ctx, productIDs, authenticatedUserID, false)
+}
+
+// FindProductDetails is like GetProductDetails, but products that no longer
+// exist are left out of the result instead of failing the whole request
+func (c *Client) FindProductDetails(ctx context.Context, productIDs []string, authenticatedUserID string) (map[string]*presenter.ProductResponse, error) {
+	return c.getProductDetails(ctx, productIDs, authenticatedUserID, true)
+}
+
+func (c *Client) getProductDetails(ctx context.Context, productIDs []string, authenticatedUserID string, skipMissing bool) (map[string]*presenter.ProductResponse, error) {
 	type result struct {
 		productID string
 		product   *presenter.ProductResponse
@@ -118,6 +267,10 @@ func (c *Client) GetProductDetails(ctx context.Context, productIDs []string, aut
 	productMap := make(map[string]*presenter.ProductResponse)
 	for i := 0; i < len(productIDs); i++ {
 		res := <-results
+		var statusErr *StatusError
+		if skipMissing && errors.As(res.err, &statusErr) && statusErr.StatusCode == http.StatusNotFound {
+			continue
+		}
 		if res.err != nil {
 			return nil, fmt.Errorf("failed to fetch product %s: %w", res.productID, res.err)
 		}
@@ -155,9 +308,64 @@ func (c *Client) DecreaseProductQuantity(ctx context.Context, productID string,
 	defer resp.Body.Close()
 	
 	if resp.StatusCode != http.StatusOK {
-		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("product service returned status %d: %s", resp.StatusCode, string(body))
+		return newStatusError("product service", resp)
 	}
 	
 	return nil
 }
+
+// ReserveProductQuantity holds stock of a product for a purchase
+func (c *Client) ReserveProductQuantity(ctx context.Context, productID, purchaseID string, quantity int, authenticatedUserID string) error {
+	url := fmt.Sprintf("%s/product/%s/reserve", c.baseURL, productID)
+
+	return c.postJSON(ctx, url, map[string]interface{}{
+		"purchaseId": purchaseID,
+		"quantity":   quantity,
+	}, authenticatedUserID)
+}
+
+// CommitProductReservation marks the stock held for a purchase as sold
+func (c *Client) CommitProductReservation(ctx context.Context, productID, purchaseID, authenticatedUserID string) error {
+	url := fmt.Sprintf("%s/product/%s/reservations/%s/commit", c.baseURL, productID, purchaseID)
+
+	return c.postJSON(ctx, url, nil, authenticatedUserID)
+}
+
+// ReleaseProductReservation returns the stock held for a purchase to the product
+func (c *Client) ReleaseProductReservation(ctx context.Context, productID, purchaseID, authenticatedUserID string) error {
+	url := fmt.Sprintf("%s/product/%s/reservations/%s/release", c.baseURL, productID, purchaseID)
+
+	return c.postJSON(ctx, url, nil, authenticatedUserID)
+}
+
+// postJSON sends a POST request with an optional JSON body and expects 200 OK
+func (c *Client) postJSON(ctx context.Context, url string, body interface{}, authenticatedUserID string) error {
+	var reader io.Reader
+	if body != nil {
+		jsonBody, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request body: %w", err)
+		}
+		reader = bytes.NewBuffer(jsonBody)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	c.setInternalHeaders(req, authenticatedUserID)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newStatusError("service", resp)
+	}
+
+	return nil
+}
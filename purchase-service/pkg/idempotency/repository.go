@@ -0,0 +1,65 @@
+package idempotency
+
+import (
+	"context"
+	"purchase-service/pkg/entities"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	// CreateKey inserts the key unless the user already has one with the same
+	// value; it reports whether the row was inserted
+	CreateKey(ctx context.Context, key *entities.IdempotencyKey) (bool, error)
+	GetKey(ctx context.Context, userID, key string) (*entities.IdempotencyKey, error)
+	CompleteKey(ctx context.Context, id string, statusCode int, body []byte) error
+	DeleteKey(ctx context.Context, id string) error
+	DeleteExpiredKey(ctx context.Context, userID, key string, now time.Time) error
+}
+
+type GormRepository struct {
+	db *gorm.DB
+}
+
+func NewGormRepository(db *gorm.DB) *GormRepository {
+	return &GormRepository{db: db}
+}
+
+func (r *GormRepository) CreateKey(ctx context.Context, key *entities.IdempotencyKey) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(key)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *GormRepository) GetKey(ctx context.Context, userID, key string) (*entities.IdempotencyKey, error) {
+	var record entities.IdempotencyKey
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND key = ?", userID, key).First(&record).Error; err != nil {
+		return nil, err
+	}
+	return &record, nil
+}
+
+func (r *GormRepository) CompleteKey(ctx context.Context, id string, statusCode int, body []byte) error {
+	return r.db.WithContext(ctx).Model(&entities.IdempotencyKey{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"status_code":   statusCode,
+			"response_body": body,
+		}).Error
+}
+
+func (r *GormRepository) DeleteKey(ctx context.Context, id string) error {
+	return r.db.WithContext(ctx).Where("id = ?", id).Delete(&entities.IdempotencyKey{}).Error
+}
+
+func (r *GormRepository) DeleteExpiredKey(ctx context.Context, userID, key string, now time.Time) error {
+	return r.db.WithContext(ctx).
+		Where("user_id = ? AND key = ? AND expires_at <= ?", userID, key, now).
+		Delete(&entities.IdempotencyKey{}).Error
+}
@@ -0,0 +1,111 @@
+package idempotency
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"purchase-service/pkg/entities"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrKeyReused is returned when a key is sent again with a different request
+	ErrKeyReused = errors.New("idempotency key was already used for a different request")
+	// ErrRequestInProgress is returned when the first request with a key has not finished yet
+	ErrRequestInProgress = errors.New("a request with this idempotency key is still being processed")
+)
+
+type Service interface {
+	// Begin claims the key for a request. When the key was already completed
+	// with the same request, the stored record is returned for replay.
+	Begin(ctx context.Context, userID, key string, requestHash string) (*entities.IdempotencyKey, error)
+	// Complete stores the response so later retries can replay it
+	Complete(ctx context.Context, record *entities.IdempotencyKey, statusCode int, body []byte) error
+	// Abandon releases the key so the request can be retried from scratch
+	Abandon(ctx context.Context, record *entities.IdempotencyKey) error
+}
+
+type service struct {
+	repo Repository
+	ttl  time.Duration
+}
+
+func NewService(repo Repository, ttl time.Duration) Service {
+	return &service{repo: repo, ttl: ttl}
+}
+
+func (s *service) Begin(ctx context.Context, userID, key string, requestHash string) (*entities.IdempotencyKey, error) {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid user ID: %w", err)
+	}
+
+	// Two attempts: the second one runs after an expired key was cleared
+	for attempt := 0; attempt < 2; attempt++ {
+		now := time.Now()
+		record := &entities.IdempotencyKey{
+			UserID:      parsedUserID,
+			Key:         key,
+			RequestHash: requestHash,
+			ExpiresAt:   now.Add(s.ttl),
+		}
+		created, err := s.repo.CreateKey(ctx, record)
+		if err != nil {
+			return nil, fmt.Errorf("failed to store idempotency key: %w", err)
+		}
+		if created {
+			return record, nil
+		}
+
+		existing, err := s.repo.GetKey(ctx, userID, key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+		}
+
+		if !existing.ExpiresAt.After(now) {
+			if err := s.repo.DeleteExpiredKey(ctx, userID, key, now); err != nil {
+				return nil, fmt.Errorf("failed to delete expired idempotency key: %w", err)
+			}
+			continue
+		}
+
+		if existing.RequestHash != requestHash {
+			return nil, ErrKeyReused
+		}
+		if !existing.Completed() {
+			return nil, ErrRequestInProgress
+		}
+		return existing, nil
+	}
+
+	return nil, ErrRequestInProgress
+}
+
+func (s *service) Complete(ctx context.Context, record *entities.IdempotencyKey, statusCode int, body []byte) error {
+	if err := s.repo.CompleteKey(ctx, record.ID.String(), statusCode, body); err != nil {
+		return fmt.Errorf("failed to store idempotent response: %w", err)
+	}
+	return nil
+}
+
+func (s *service) Abandon(ctx context.Context, record *entities.IdempotencyKey) error {
+	if err := s.repo.DeleteKey(ctx, record.ID.String()); err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+	return nil
+}
+
+// HashRequest fingerprints the parts of a request that must match on replay
+func HashRequest(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(path))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
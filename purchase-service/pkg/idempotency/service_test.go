@@ -0,0 +1,196 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"purchase-service/pkg/entities"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// memoryRepository keeps idempotency keys in memory, unique per user and key
+// like the table
+type memoryRepository struct {
+	keys map[string]*entities.IdempotencyKey
+}
+
+func newMemoryRepository() *memoryRepository {
+	return &memoryRepository{keys: make(map[string]*entities.IdempotencyKey)}
+}
+
+func (r *memoryRepository) CreateKey(ctx context.Context, key *entities.IdempotencyKey) (bool, error) {
+	if _, ok := r.keys[key.UserID.String()+"/"+key.Key]; ok {
+		return false, nil
+	}
+	key.ID = uuid.New()
+	stored := *key
+	r.keys[key.UserID.String()+"/"+key.Key] = &stored
+	return true, nil
+}
+
+func (r *memoryRepository) GetKey(ctx context.Context, userID, key string) (*entities.IdempotencyKey, error) {
+	record, ok := r.keys[userID+"/"+key]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *record
+	return &found, nil
+}
+
+func (r *memoryRepository) CompleteKey(ctx context.Context, id string, statusCode int, body []byte) error {
+	for _, record := range r.keys {
+		if record.ID.String() == id {
+			record.StatusCode = statusCode
+			record.ResponseBody = body
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) DeleteKey(ctx context.Context, id string) error {
+	for k, record := range r.keys {
+		if record.ID.String() == id {
+			delete(r.keys, k)
+		}
+	}
+	return nil
+}
+
+func (r *memoryRepository) DeleteExpiredKey(ctx context.Context, userID, key string, now time.Time) error {
+	if record, ok := r.keys[userID+"/"+key]; ok && !record.ExpiresAt.After(now) {
+		delete(r.keys, userID+"/"+key)
+	}
+	return nil
+}
+
+func TestBegin(t *testing.T) {
+	const userID = "0199a3f2-0000-7000-8000-000000000001"
+	hash := HashRequest("POST", "/v1/purchase", []byte(`{"items":[]}`))
+	otherHash := HashRequest("POST", "/v1/purchase", []byte(`{"items":[1]}`))
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		// prepare makes an earlier request with the key, if any
+		prepare    func(t *testing.T, s Service, repo *memoryRepository)
+		hash       string
+		wantErr    error
+		wantReplay bool
+	}{
+		{
+			name: "new key is claimed",
+			hash: hash,
+		},
+		{
+			name: "first request still running",
+			prepare: func(t *testing.T, s Service, repo *memoryRepository) {
+				if _, err := s.Begin(ctx, userID, "key", hash); err != nil {
+					t.Fatalf("Begin() error = %v", err)
+				}
+			},
+			hash:    hash,
+			wantErr: ErrRequestInProgress,
+		},
+		{
+			name: "completed request is replayed",
+			prepare: func(t *testing.T, s Service, repo *memoryRepository) {
+				record, err := s.Begin(ctx, userID, "key", hash)
+				if err != nil {
+					t.Fatalf("Begin() error = %v", err)
+				}
+				if err := s.Complete(ctx, record, 201, []byte(`{"id":"1"}`)); err != nil {
+					t.Fatalf("Complete() error = %v", err)
+				}
+			},
+			hash:       hash,
+			wantReplay: true,
+		},
+		{
+			name: "key sent with another request",
+			prepare: func(t *testing.T, s Service, repo *memoryRepository) {
+				record, err := s.Begin(ctx, userID, "key", hash)
+				if err != nil {
+					t.Fatalf("Begin() error = %v", err)
+				}
+				if err := s.Complete(ctx, record, 201, nil); err != nil {
+					t.Fatalf("Complete() error = %v", err)
+				}
+			},
+			hash:    otherHash,
+			wantErr: ErrKeyReused,
+		},
+		{
+			name: "abandoned key is claimed again",
+			prepare: func(t *testing.T, s Service, repo *memoryRepository) {
+				record, err := s.Begin(ctx, userID, "key", hash)
+				if err != nil {
+					t.Fatalf("Begin() error = %v", err)
+				}
+				if err := s.Abandon(ctx, record); err != nil {
+					t.Fatalf("Abandon() error = %v", err)
+				}
+			},
+			hash: otherHash,
+		},
+		{
+			name: "expired key is claimed again",
+			prepare: func(t *testing.T, s Service, repo *memoryRepository) {
+				if _, err := s.Begin(ctx, userID, "key", hash); err != nil {
+					t.Fatalf("Begin() error = %v", err)
+				}
+				for _, record := range repo.keys {
+					record.ExpiresAt = time.Now().Add(-time.Minute)
+				}
+			},
+			hash: otherHash,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newMemoryRepository()
+			s := NewService(repo, time.Hour)
+			if tt.prepare != nil {
+				tt.prepare(t, s, repo)
+			}
+
+			record, err := s.Begin(ctx, userID, "key", tt.hash)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Begin() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Begin() error = %v", err)
+			}
+			if record.RequestHash != tt.hash {
+				t.Errorf("RequestHash = %s, want %s", record.RequestHash, tt.hash)
+			}
+			if record.Completed() != tt.wantReplay {
+				t.Errorf("Completed() = %v, want %v", record.Completed(), tt.wantReplay)
+			}
+		})
+	}
+}
+
+func TestHashRequest(t *testing.T) {
+	base := HashRequest("POST", "/v1/purchase", []byte("body"))
+	if HashRequest("POST", "/v1/purchase", []byte("body")) != base {
+		t.Error("HashRequest() differs for the same request")
+	}
+	// The separators keep the parts from running into each other
+	for _, other := range []string{
+		HashRequest("PUT", "/v1/purchase", []byte("body")),
+		HashRequest("POST", "/v1/purchase/x", []byte("body")),
+		HashRequest("POST", "/v1/purchase", []byte("other")),
+		HashRequest("POST", "/v1/purchasebody", nil),
+	} {
+		if other == base {
+			t.Error("HashRequest() matches a different request")
+		}
+	}
+}
@@ -0,0 +1,175 @@
+// Package money provides exact arithmetic for prices and totals. Amounts are
+// kept as integer minor units so adding up many items never drifts the way
+// float64 does, and they map one-to-one onto decimal(10,2) columns.
+package money
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+const (
+	// Scale is the number of fractional digits kept for every amount
+	Scale = 2
+	// DefaultCurrency is assumed when an upstream service does not report one
+	DefaultCurrency = "IDR"
+
+	minorPerUnit = 100
+)
+
+var ErrInvalidAmount = errors.New("invalid money amount")
+
+// Amount is a monetary value in minor units (1/100 of the currency unit)
+type Amount int64
+
+// Parse reads a plain decimal such as "15000", "-2.5" or "99.90". Digits
+// beyond the scale are only accepted when they are zero, so no value is
+// ever silently rounded.
+func Parse(s string) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, ErrInvalidAmount
+	}
+
+	negative := false
+	switch s[0] {
+	case '-':
+		negative = true
+		s = s[1:]
+	case '+':
+		s = s[1:]
+	}
+
+	whole, frac, _ := strings.Cut(s, ".")
+	if whole == "" && frac == "" {
+		return 0, ErrInvalidAmount
+	}
+	if whole == "" {
+		whole = "0"
+	}
+	if len(frac) > Scale {
+		if strings.Trim(frac[Scale:], "0") != "" {
+			return 0, fmt.Errorf("%w: more than %d decimal places", ErrInvalidAmount, Scale)
+		}
+		frac = frac[:Scale]
+	}
+	frac += strings.Repeat("0", Scale-len(frac))
+
+	if !isDigits(whole) || !isDigits(frac) {
+		return 0, ErrInvalidAmount
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > math.MaxInt64/minorPerUnit-1 {
+		return 0, fmt.Errorf("%w: out of range", ErrInvalidAmount)
+	}
+	cents, _ := strconv.ParseInt(frac, 10, 64)
+
+	minor := units*minorPerUnit + cents
+	if negative {
+		minor = -minor
+	}
+	return Amount(minor), nil
+}
+
+// Minor returns the amount in minor units
+func (a Amount) Minor() int64 {
+	return int64(a)
+}
+
+// Add returns a + b
+func (a Amount) Add(b Amount) Amount {
+	return a + b
+}
+
+// Sub returns a - b
+func (a Amount) Sub(b Amount) Amount {
+	return a - b
+}
+
+// Mul returns the amount multiplied by a quantity
+func (a Amount) Mul(qty int) Amount {
+	return a * Amount(qty)
+}
+
+// String formats the amount as a plain decimal with exactly Scale digits
+func (a Amount) String() string {
+	minor := int64(a)
+	sign := ""
+	if minor < 0 {
+		sign = "-"
+		minor = -minor
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, minor/minorPerUnit, minor%minorPerUnit)
+}
+
+// MarshalJSON encodes the amount as a JSON number without going through float64
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalJSON accepts a JSON number or a quoted decimal string
+func (a *Amount) UnmarshalJSON(data []byte) error {
+	s := string(data)
+	if s == "null" {
+		*a = 0
+		return nil
+	}
+	s = strings.Trim(s, `"`)
+
+	parsed, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// Value stores the amount as a decimal string so the database keeps it exact
+func (a Amount) Value() (driver.Value, error) {
+	return a.String(), nil
+}
+
+// Scan reads a decimal column
+func (a *Amount) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*a = 0
+		return nil
+	case []byte:
+		parsed, err := Parse(string(v))
+		if err != nil {
+			return err
+		}
+		*a = parsed
+		return nil
+	case string:
+		parsed, err := Parse(v)
+		if err != nil {
+			return err
+		}
+		*a = parsed
+		return nil
+	case int64:
+		*a = Amount(v * minorPerUnit)
+		return nil
+	case float64:
+		*a = Amount(math.Round(v * minorPerUnit))
+		return nil
+	default:
+		return fmt.Errorf("%w: cannot scan %T", ErrInvalidAmount, src)
+	}
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,232 @@
+package purchase
+
+import (
+	"context"
+	"fmt"
+	"purchase-service/api/presenter"
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/money"
+	"time"
+)
+
+// Analytics intervals; they match the units of PostgreSQL's date_trunc
+const (
+	IntervalDay   = "day"
+	IntervalWeek  = "week"
+	IntervalMonth = "month"
+)
+
+const (
+	defaultAnalyticsRange = 30 * 24 * time.Hour
+	maxAnalyticsPeriods   = 1000
+	defaultTopLimit       = 5
+	maxTopLimit           = 50
+)
+
+// salesQuery selects the sales of one seller for the analytics queries
+type salesQuery struct {
+	SellerID string
+	Currency string
+	From     time.Time
+	To       time.Time
+	Interval string
+}
+
+// revenuePeriod is the seller's sales in one interval, starting at Period (UTC)
+type revenuePeriod struct {
+	Period    time.Time
+	Revenue   money.Amount
+	Orders    int
+	ItemsSold int
+}
+
+type productSales struct {
+	ProductID string
+	Name      string
+	QtySold   int
+	Revenue   money.Amount
+}
+
+type categorySales struct {
+	Category string
+	QtySold  int
+	Revenue  money.Amount
+}
+
+type conversionCounts struct {
+	Created   int
+	Confirmed int
+}
+
+// IsValidInterval reports whether the analytics interval is supported
+func IsValidInterval(interval string) bool {
+	return interval == IntervalDay || interval == IntervalWeek || interval == IntervalMonth
+}
+
+// GetSellerAnalytics aggregates the seller's sales over a date range. Periods
+// without sales are included with zero values, so the series can be charted
+// as is. Purchases are attributed to the period they were created in.
+func (s *service) GetSellerAnalytics(ctx context.Context, filter dtos.SellerAnalyticsFilter) (*presenter.SellerAnalyticsResponse, error) {
+	sellerID, ok := ctx.Value("user_id").(string)
+	if !ok || sellerID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	query := salesQuery{
+		SellerID: sellerID,
+		Currency: filter.Currency,
+		Interval: filter.Interval,
+		To:       time.Now().UTC(),
+	}
+	if query.Currency == "" {
+		query.Currency = money.DefaultCurrency
+	}
+	if query.Interval == "" {
+		query.Interval = IntervalDay
+	}
+	if !IsValidInterval(query.Interval) {
+		return nil, fmt.Errorf("%w: unknown interval %s", ErrInvalidInput, query.Interval)
+	}
+	if filter.To != nil {
+		query.To = filter.To.UTC()
+	}
+	query.From = query.To.Add(-defaultAnalyticsRange)
+	if filter.From != nil {
+		query.From = filter.From.UTC()
+	}
+	if !query.From.Before(query.To) {
+		return nil, fmt.Errorf("%w: from must be before to", ErrInvalidInput)
+	}
+
+	periodStarts := analyticsPeriods(query.From, query.To, query.Interval)
+	if len(periodStarts) > maxAnalyticsPeriods {
+		return nil, fmt.Errorf("%w: date range is too long for a %s interval", ErrInvalidInput, query.Interval)
+	}
+
+	limit := filter.Limit
+	if limit <= 0 || limit > maxTopLimit {
+		limit = defaultTopLimit
+	}
+
+	periods, err := s.repo.GetSellerRevenueByPeriod(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revenue: %w", err)
+	}
+	products, err := s.repo.GetSellerTopProducts(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top products: %w", err)
+	}
+	categories, err := s.repo.GetSellerTopCategories(ctx, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get top categories: %w", err)
+	}
+	conversion, err := s.repo.GetSellerConversion(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get conversion: %w", err)
+	}
+
+	response := &presenter.SellerAnalyticsResponse{
+		From:            query.From.Format(time.RFC3339),
+		To:              query.To.Format(time.RFC3339),
+		Interval:        query.Interval,
+		Currency:        query.Currency,
+		RevenueByPeriod: make([]presenter.RevenuePeriod, 0, len(periodStarts)),
+		TopProducts:     make([]presenter.TopProduct, 0, len(products)),
+		TopCategories:   make([]presenter.TopCategory, 0, len(categories)),
+		Conversion: presenter.ConversionResponse{
+			Created:   conversion.Created,
+			Confirmed: conversion.Confirmed,
+		},
+	}
+
+	// A purchase is created in exactly one period, so orders add up across them
+	byStart := make(map[time.Time]*revenuePeriod, len(periods))
+	for _, period := range periods {
+		byStart[period.Period.UTC()] = period
+		response.Revenue = response.Revenue.Add(period.Revenue)
+		response.Orders += period.Orders
+		response.ItemsSold += period.ItemsSold
+	}
+	for _, start := range periodStarts {
+		period := presenter.RevenuePeriod{Period: start.Format(time.DateOnly)}
+		if sales, ok := byStart[start]; ok {
+			period.Revenue = sales.Revenue
+			period.Orders = sales.Orders
+			period.ItemsSold = sales.ItemsSold
+		}
+		response.RevenueByPeriod = append(response.RevenueByPeriod, period)
+	}
+
+	if response.Orders > 0 {
+		response.AverageOrderValue = averageAmount(response.Revenue, response.Orders)
+	}
+	if conversion.Created > 0 {
+		response.Conversion.Rate = float64(conversion.Confirmed) / float64(conversion.Created)
+	}
+
+	for _, product := range products {
+		response.TopProducts = append(response.TopProducts, presenter.TopProduct{
+			ProductID: product.ProductID,
+			Name:      product.Name,
+			QtySold:   product.QtySold,
+			Revenue:   product.Revenue,
+		})
+	}
+	for _, category := range categories {
+		response.TopCategories = append(response.TopCategories, presenter.TopCategory{
+			Category: category.Category,
+			QtySold:  category.QtySold,
+			Revenue:  category.Revenue,
+		})
+	}
+
+	return response, nil
+}
+
+// analyticsPeriods lists the start of every interval overlapping [from, to),
+// truncated the way date_trunc does: weeks start on Monday, months on the 1st
+func analyticsPeriods(from, to time.Time, interval string) []time.Time {
+	start := truncateToInterval(from, interval)
+
+	var starts []time.Time
+	for t := start; t.Before(to); {
+		starts = append(starts, t)
+		// Stop counting once the caller is going to reject the range anyway
+		if len(starts) > maxAnalyticsPeriods {
+			break
+		}
+		switch interval {
+		case IntervalWeek:
+			t = t.AddDate(0, 0, 7)
+		case IntervalMonth:
+			t = t.AddDate(0, 1, 0)
+		default:
+			t = t.AddDate(0, 0, 1)
+		}
+	}
+	return starts
+}
+
+func truncateToInterval(t time.Time, interval string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch interval {
+	case IntervalWeek:
+		// Go weeks start on Sunday (0); ISO weeks on Monday
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case IntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+	return day
+}
+
+// averageAmount divides an amount evenly, rounding half away from zero to the
+// nearest minor unit
+func averageAmount(total money.Amount, count int) money.Amount {
+	minor := total.Minor()
+	n := int64(count)
+	if minor >= 0 {
+		return money.Amount((minor + n/2) / n)
+	}
+	return money.Amount((minor - n/2) / n)
+}
@@ -0,0 +1,256 @@
+package purchase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"purchase-service/api/presenter"
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/entities"
+	"purchase-service/pkg/money"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB connects to the Postgres database in TEST_DATABASE_URL and runs
+// the migrations in a schema of its own, dropped when the test ends. Tests
+// that need the database are skipped when the variable is not set.
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to database: %v", err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatalf("failed to get database handle: %v", err)
+	}
+	// A single connection keeps the search path below for every query
+	sqlDB.SetMaxOpenConns(1)
+
+	schema := fmt.Sprintf("purchase_test_%d", time.Now().UnixNano())
+	if err := db.Exec("CREATE SCHEMA " + schema).Error; err != nil {
+		t.Fatalf("failed to create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DROP SCHEMA " + schema + " CASCADE")
+		sqlDB.Close()
+	})
+	if err := db.Exec("SET search_path TO " + schema + ", public").Error; err != nil {
+		t.Fatalf("failed to set search path: %v", err)
+	}
+
+	migrations, err := filepath.Glob("../../migrations/db/*.up.sql")
+	if err != nil || len(migrations) == 0 {
+		t.Fatalf("failed to find migrations: %v", err)
+	}
+	sort.Strings(migrations)
+	for _, migration := range migrations {
+		sql, err := os.ReadFile(migration)
+		if err != nil {
+			t.Fatalf("failed to read %s: %v", migration, err)
+		}
+		if err := db.Exec(string(sql)).Error; err != nil {
+			t.Fatalf("failed to run %s: %v", filepath.Base(migration), err)
+		}
+	}
+	return db
+}
+
+func mustAmount(t *testing.T, s string) money.Amount {
+	t.Helper()
+	amount, err := money.Parse(s)
+	if err != nil {
+		t.Fatalf("invalid amount %q: %v", s, err)
+	}
+	return amount
+}
+
+// seedLine is one item of a seeded purchase
+type seedLine struct {
+	sellerID  uuid.UUID
+	productID string
+	name      string
+	category  string
+	qty       int
+	price     string
+}
+
+// seedPurchase writes a purchase with its items and one payment detail per
+// seller, as placeOrder would, with the given status and creation time
+func seedPurchase(t *testing.T, db *gorm.DB, createdAt time.Time, currency string, status entities.PurchaseStatus, detailStatus map[uuid.UUID]entities.PurchaseStatus, discounts, shipping map[uuid.UUID]string, lines ...seedLine) *entities.Purchase {
+	t.Helper()
+	purchase := &entities.Purchase{
+		UserID:    uuid.New(),
+		Status:    status,
+		Currency:  currency,
+		CreatedAt: createdAt,
+	}
+
+	var items []*entities.PurchaseItem
+	subtotals := make(map[uuid.UUID]money.Amount)
+	var sellers []uuid.UUID
+	for _, line := range lines {
+		price := mustAmount(t, line.price)
+		items = append(items, &entities.PurchaseItem{
+			ProductID: line.productID,
+			SellerID:  line.sellerID,
+			Name:      line.name,
+			Category:  line.category,
+			Qty:       line.qty,
+			Price:     price,
+			LineTotal: price.Mul(line.qty),
+			CreatedAt: createdAt,
+		})
+		if _, ok := subtotals[line.sellerID]; !ok {
+			sellers = append(sellers, line.sellerID)
+		}
+		subtotals[line.sellerID] = subtotals[line.sellerID].Add(price.Mul(line.qty))
+	}
+
+	var details []*entities.PurchasePaymentDetail
+	var shipments []*entities.PurchaseShipment
+	for _, sellerID := range sellers {
+		var discount, cost money.Amount
+		if s, ok := discounts[sellerID]; ok {
+			discount = mustAmount(t, s)
+		}
+		if s, ok := shipping[sellerID]; ok {
+			cost = mustAmount(t, s)
+			shipments = append(shipments, &entities.PurchaseShipment{SellerID: sellerID, Method: "regular", Cost: cost})
+		}
+		details = append(details, &entities.PurchasePaymentDetail{
+			SellerID:          sellerID,
+			BankAccountName:   "Bank",
+			BankAccountHolder: "Seller",
+			BankAccountNumber: "1234567890",
+			TotalPrice:        subtotals[sellerID].Sub(discount).Add(cost),
+			Discount:          discount,
+			Status:            detailStatus[sellerID],
+		})
+		purchase.Discount = purchase.Discount.Add(discount)
+		purchase.TotalPrice = purchase.TotalPrice.Add(subtotals[sellerID].Sub(discount).Add(cost))
+	}
+
+	repo := NewGormRepository(db)
+	ctx := context.Background()
+	if err := repo.CreatePurchase(ctx, purchase); err != nil {
+		t.Fatalf("failed to seed purchase: %v", err)
+	}
+	for _, item := range items {
+		item.PurchaseID = purchase.ID
+	}
+	for _, detail := range details {
+		detail.PurchaseID = purchase.ID
+	}
+	for _, shipment := range shipments {
+		shipment.PurchaseID = purchase.ID
+	}
+	if err := repo.CreatePurchaseItems(ctx, items); err != nil {
+		t.Fatalf("failed to seed purchase items: %v", err)
+	}
+	if err := repo.CreatePurchasePaymentDetails(ctx, details); err != nil {
+		t.Fatalf("failed to seed payment details: %v", err)
+	}
+	if len(shipments) > 0 {
+		if err := repo.CreatePurchaseShipments(ctx, shipments); err != nil {
+			t.Fatalf("failed to seed shipments: %v", err)
+		}
+	}
+	return purchase
+}
+
+func TestGetSellerAnalytics(t *testing.T) {
+	db := openTestDB(t)
+
+	seller := uuid.New()
+	otherSeller := uuid.New()
+	const (
+		keyboard = "keyboard"
+		mousePad = "mouse-pad"
+		monitor  = "monitor"
+	)
+	day := func(d, hour int) time.Time { return time.Date(2025, time.September, d, hour, 0, 0, 0, time.UTC) }
+	confirmed := map[uuid.UUID]entities.PurchaseStatus{seller: entities.PurchaseStatusConfirmed, otherSeller: entities.PurchaseStatusConfirmed}
+
+	// Sep 1: the seller is paid 225.00 for 3 items after a 25.00 discount and
+	// 10.00 of shipping; the other seller's item is not theirs
+	seedPurchase(t, db, day(1, 10), "IDR", entities.PurchaseStatusConfirmed, confirmed,
+		map[uuid.UUID]string{seller: "25.00"}, map[uuid.UUID]string{seller: "10.00"},
+		seedLine{seller, keyboard, "Keyboard", "electronics", 2, "100.00"},
+		seedLine{seller, mousePad, "Mouse pad", "accessories", 1, "50.00"},
+		seedLine{otherSeller, monitor, "Monitor", "electronics", 1, "300.00"})
+	// Sep 3: 100.00 for one item, without discount or shipping
+	seedPurchase(t, db, day(3, 9), "IDR", entities.PurchaseStatusCompleted, confirmed, nil, nil,
+		seedLine{seller, keyboard, "Keyboard", "electronics", 1, "100.00"})
+	// Sep 2: created but not paid yet, so only counted in the conversion
+	seedPurchase(t, db, day(2, 8), "IDR", entities.PurchaseStatusPendingPayment,
+		map[uuid.UUID]entities.PurchaseStatus{seller: entities.PurchaseStatusPendingPayment}, nil, nil,
+		seedLine{seller, mousePad, "Mouse pad", "accessories", 3, "50.00"})
+	// Sep 2: confirmed and then refunded, so not a sale but still a conversion
+	seedPurchase(t, db, day(2, 12), "IDR", entities.PurchaseStatusRefunded, confirmed, nil, nil,
+		seedLine{seller, keyboard, "Keyboard", "electronics", 1, "100.00"})
+	// Outside the range and in another currency
+	seedPurchase(t, db, day(1, 0).Add(-time.Hour), "IDR", entities.PurchaseStatusConfirmed, confirmed, nil, nil,
+		seedLine{seller, keyboard, "Keyboard", "electronics", 5, "100.00"})
+	seedPurchase(t, db, day(2, 15), "USD", entities.PurchaseStatusConfirmed, confirmed, nil, nil,
+		seedLine{seller, keyboard, "Keyboard", "electronics", 1, "10.00"})
+
+	from, to := day(1, 0), day(4, 0)
+	s := &service{repo: NewGormRepository(db)}
+	ctx := context.WithValue(context.Background(), "user_id", seller.String())
+	analytics, err := s.GetSellerAnalytics(ctx, dtos.SellerAnalyticsFilter{From: &from, To: &to, Interval: IntervalDay})
+	if err != nil {
+		t.Fatalf("GetSellerAnalytics() error = %v", err)
+	}
+
+	wantPeriods := []presenter.RevenuePeriod{
+		{Period: "2025-09-01", Revenue: mustAmount(t, "225.00"), Orders: 1, ItemsSold: 3},
+		{Period: "2025-09-02"},
+		{Period: "2025-09-03", Revenue: mustAmount(t, "100.00"), Orders: 1, ItemsSold: 1},
+	}
+	if !reflect.DeepEqual(analytics.RevenueByPeriod, wantPeriods) {
+		t.Errorf("RevenueByPeriod = %+v, want %+v", analytics.RevenueByPeriod, wantPeriods)
+	}
+	if analytics.Revenue != mustAmount(t, "325.00") || analytics.Orders != 2 || analytics.ItemsSold != 4 {
+		t.Errorf("totals = %v revenue, %d orders, %d items, want 325.00, 2 and 4", analytics.Revenue, analytics.Orders, analytics.ItemsSold)
+	}
+	if analytics.AverageOrderValue != mustAmount(t, "162.50") {
+		t.Errorf("AverageOrderValue = %v, want 162.50", analytics.AverageOrderValue)
+	}
+
+	// The 25.00 discount is split 20.00 to the keyboards and 5.00 to the mouse
+	// pad, so the top lists add up to the revenue
+	wantProducts := []presenter.TopProduct{
+		{ProductID: keyboard, Name: "Keyboard", QtySold: 3, Revenue: mustAmount(t, "280.00")},
+		{ProductID: mousePad, Name: "Mouse pad", QtySold: 1, Revenue: mustAmount(t, "45.00")},
+	}
+	if !reflect.DeepEqual(analytics.TopProducts, wantProducts) {
+		t.Errorf("TopProducts = %+v, want %+v", analytics.TopProducts, wantProducts)
+	}
+	wantCategories := []presenter.TopCategory{
+		{Category: "electronics", QtySold: 3, Revenue: mustAmount(t, "280.00")},
+		{Category: "accessories", QtySold: 1, Revenue: mustAmount(t, "45.00")},
+	}
+	if !reflect.DeepEqual(analytics.TopCategories, wantCategories) {
+		t.Errorf("TopCategories = %+v, want %+v", analytics.TopCategories, wantCategories)
+	}
+
+	wantConversion := presenter.ConversionResponse{Created: 4, Confirmed: 3, Rate: 0.75}
+	if analytics.Conversion != wantConversion {
+		t.Errorf("Conversion = %+v, want %+v", analytics.Conversion, wantConversion)
+	}
+}
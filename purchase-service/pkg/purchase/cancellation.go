@@ -0,0 +1,430 @@
+package purchase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"purchase-service/pkg/entities"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CancelPurchase lets the buyer call off a purchase before it is paid. The
+// reserved stock is released in the same transaction, so the purchase is only
+// cancelled once the stock is back on sale. When some sellers already
+// confirmed their payment, only the part owed to the other sellers is called
+// off and the purchase goes on as confirmed with the sellers that were paid.
+func (s *service) CancelPurchase(ctx context.Context, purchaseID, reason string) error {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return ErrUnauthenticated
+	}
+
+	return s.repo.WithTransaction(ctx, func(tx Repository) error {
+		if _, err := s.getBuyerPurchase(ctx, tx, purchaseID, userID); err != nil {
+			return err
+		}
+
+		purchase, err := lockPayablePurchase(ctx, tx, purchaseID, entities.PurchaseStatusCancelled)
+		if err != nil {
+			return err
+		}
+		paymentDetails, err := tx.GetPurchasePaymentDetailsByPurchaseID(ctx, purchaseID)
+		if err != nil {
+			return fmt.Errorf("failed to get purchase payment details: %w", err)
+		}
+
+		items, err := tx.GetPurchaseItemsByPurchaseID(ctx, purchaseID)
+		if err != nil {
+			return fmt.Errorf("failed to get purchase items: %w", err)
+		}
+
+		message := "A purchase of your products was cancelled by the buyer"
+		if reason != "" {
+			message += ": " + reason
+		}
+
+		if hasConfirmedPayment(paymentDetails) {
+			cancelledItems, err := s.cancelUnconfirmedPayments(ctx, tx, purchase, paymentDetails, items, reason)
+			if err != nil {
+				return err
+			}
+			if err := s.tryReleaseStock(ctx, purchaseID, itemProductIDs(cancelledItems), userID); err != nil {
+				return upstreamError(err, "failed to release reserved stock")
+			}
+			return notifySellers(ctx, tx, purchase, cancelledItems, entities.NotificationPurchaseCancelled, message)
+		}
+
+		if err := s.transitionStatus(ctx, tx, purchase, entities.PurchaseStatusCancelled, map[string]interface{}{
+			"status_reason": reason,
+		}); err != nil {
+			return fmt.Errorf("failed to cancel purchase: %w", err)
+		}
+		if err := tx.ReleaseVoucherRedemption(ctx, purchaseID); err != nil {
+			return fmt.Errorf("failed to release voucher: %w", err)
+		}
+
+		if err := s.tryReleaseStock(ctx, purchaseID, itemProductIDs(items), userID); err != nil {
+			return upstreamError(err, "failed to release reserved stock")
+		}
+		return notifySellers(ctx, tx, purchase, items, entities.NotificationPurchaseCancelled, message)
+	})
+}
+
+// cancelUnconfirmedPayments calls off the payments to the sellers of a
+// purchase that have not confirmed theirs, when other sellers already did.
+// Their payment details and parcels are cancelled, the buyer no longer owes
+// them, and the purchase moves on to confirmed. It must run under the
+// purchase lock and returns the items of the cancelled sellers, whose stock
+// the caller releases.
+func (s *service) cancelUnconfirmedPayments(ctx context.Context, tx Repository, purchase *entities.Purchase, details []*entities.PurchasePaymentDetail, items []*entities.PurchaseItem, reason string) ([]*entities.PurchaseItem, error) {
+	purchaseID := purchase.ID.String()
+	now := time.Now()
+
+	totalPrice := purchase.TotalPrice
+	discount := purchase.Discount
+	cancelled := make(map[uuid.UUID]bool)
+	for _, detail := range details {
+		if detail.Status == entities.PurchaseStatusConfirmed || detail.Status == entities.PurchaseStatusCancelled {
+			continue
+		}
+		sellerID := detail.SellerID.String()
+		if err := tx.UpdatePaymentDetailStatus(ctx, purchaseID, sellerID, detail.Status, entities.PurchaseStatusCancelled, map[string]interface{}{
+			"status_reason": reason,
+		}); err != nil {
+			return nil, fmt.Errorf("failed to cancel payment to seller %s: %w", sellerID, err)
+		}
+		if err := tx.UpdatePaymentProofStatus(ctx, purchaseID, sellerID, entities.PaymentProofStatusPending, entities.PaymentProofStatusRejected, &now); err != nil {
+			return nil, fmt.Errorf("failed to reject payment proofs: %w", err)
+		}
+		totalPrice = totalPrice.Sub(detail.TotalPrice)
+		discount = discount.Sub(detail.Discount)
+		cancelled[detail.SellerID] = true
+	}
+
+	shipments, err := tx.LockShipmentsByPurchaseID(ctx, purchaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock shipments: %w", err)
+	}
+	for _, shipment := range shipments {
+		if !cancelled[shipment.SellerID] {
+			continue
+		}
+		if err := tx.UpdateShipmentStatus(ctx, purchaseID, shipment.SellerID.String(), shipment.Status, entities.ShipmentStatusCancelled, nil); err != nil {
+			return nil, fmt.Errorf("failed to cancel shipment: %w", err)
+		}
+	}
+
+	if err := s.transitionStatus(ctx, tx, purchase, entities.PurchaseStatusConfirmed, map[string]interface{}{
+		"total_price":   totalPrice,
+		"discount":      discount,
+		"status_reason": reason,
+	}); err != nil {
+		return nil, fmt.Errorf("failed to update purchase status: %w", err)
+	}
+
+	cancelledItems := make([]*entities.PurchaseItem, 0, len(items))
+	for _, item := range items {
+		if cancelled[item.SellerID] {
+			cancelledItems = append(cancelledItems, item)
+		}
+	}
+	return cancelledItems, nil
+}
+
+// hasConfirmedPayment reports whether any seller confirmed their payment
+func hasConfirmedPayment(details []*entities.PurchasePaymentDetail) bool {
+	for _, detail := range details {
+		if detail.Status == entities.PurchaseStatusConfirmed {
+			return true
+		}
+	}
+	return false
+}
+
+// itemProductIDs lists the products of the given items
+func itemProductIDs(items []*entities.PurchaseItem) []string {
+	productIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	return productIDs
+}
+
+// RequestRefund asks the sellers to give back a confirmed payment
+func (s *service) RequestRefund(ctx context.Context, purchaseID, reason string) error {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return ErrUnauthenticated
+	}
+
+	return s.repo.WithTransaction(ctx, func(tx Repository) error {
+		purchase, err := s.getBuyerPurchase(ctx, tx, purchaseID, userID)
+		if err != nil {
+			return err
+		}
+
+		// A rejected refund is not asked again; the buyer takes it to a dispute
+		refund, err := tx.GetLatestRefundByPurchaseID(ctx, purchaseID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to get refund request: %w", err)
+		}
+		if err == nil && refund.Status == entities.RefundStatusRejected {
+			return fmt.Errorf("%w: refund was already rejected, open a dispute instead", ErrConflict)
+		}
+
+		if err := s.transitionStatus(ctx, tx, purchase, entities.PurchaseStatusRefundRequested, map[string]interface{}{
+			"status_reason": reason,
+		}); err != nil {
+			return fmt.Errorf("failed to request refund: %w", err)
+		}
+
+		if err := tx.CreateRefund(ctx, &entities.PurchaseRefund{
+			PurchaseID: purchase.ID,
+			Status:     entities.RefundStatusRequested,
+			Reason:     reason,
+		}); err != nil {
+			return fmt.Errorf("failed to create refund request: %w", err)
+		}
+
+		// Every seller that was paid decides on their own share of the refund
+		details, err := tx.GetPurchasePaymentDetailsByPurchaseID(ctx, purchaseID)
+		if err != nil {
+			return fmt.Errorf("failed to get purchase payment details: %w", err)
+		}
+		for _, detail := range details {
+			if detail.Status != entities.PurchaseStatusConfirmed {
+				continue
+			}
+			if err := tx.UpdatePaymentDetailRefundStatus(ctx, purchaseID, detail.SellerID.String(), detail.RefundStatus, entities.RefundStatusRequested, map[string]interface{}{
+				"refund_reason":     "",
+				"refund_decided_at": nil,
+			}); err != nil {
+				return fmt.Errorf("failed to request refund from seller %s: %w", detail.SellerID, err)
+			}
+		}
+
+		items, err := tx.GetPurchaseItemsByPurchaseID(ctx, purchaseID)
+		if err != nil {
+			return fmt.Errorf("failed to get purchase items: %w", err)
+		}
+		return notifySellers(ctx, tx, purchase, items, entities.NotificationRefundRequested,
+			"The buyer requested a refund: "+reason)
+	})
+}
+
+// ApproveRefund records the seller's approval of the buyer's refund request.
+// The purchase is refunded once every seller that was paid approved it. Stock
+// already sold is not returned to the product, since the goods may have been
+// shipped.
+func (s *service) ApproveRefund(ctx context.Context, purchaseID string) error {
+	sellerID, ok := ctx.Value("user_id").(string)
+	if !ok || sellerID == "" {
+		return ErrUnauthenticated
+	}
+
+	return s.repo.WithTransaction(ctx, func(tx Repository) error {
+		purchase, err := s.lockRefundRequest(ctx, tx, purchaseID, sellerID, entities.PurchaseStatusRefunded)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.UpdatePaymentDetailRefundStatus(ctx, purchaseID, sellerID, entities.RefundStatusRequested, entities.RefundStatusApproved, map[string]interface{}{
+			"refund_reason":     "",
+			"refund_decided_at": time.Now(),
+		}); err != nil {
+			return fmt.Errorf("failed to approve refund: %w", err)
+		}
+
+		if err := tx.CreateNotification(ctx, &entities.PurchaseNotification{
+			UserID:     purchase.UserID,
+			PurchaseID: purchase.ID,
+			Type:       entities.NotificationRefundApproved,
+			Message:    "Your refund request has been approved by the seller",
+		}); err != nil {
+			return err
+		}
+
+		return s.settleRefund(ctx, tx, purchase, sellerID)
+	})
+}
+
+// RejectRefund records the seller's rejection of the buyer's refund request.
+// Once every seller that was paid has decided, the purchase goes back to
+// confirmed and keeps its original confirmation time.
+func (s *service) RejectRefund(ctx context.Context, purchaseID, reason string) error {
+	sellerID, ok := ctx.Value("user_id").(string)
+	if !ok || sellerID == "" {
+		return ErrUnauthenticated
+	}
+
+	return s.repo.WithTransaction(ctx, func(tx Repository) error {
+		purchase, err := s.lockRefundRequest(ctx, tx, purchaseID, sellerID, entities.PurchaseStatusConfirmed)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.UpdatePaymentDetailRefundStatus(ctx, purchaseID, sellerID, entities.RefundStatusRequested, entities.RefundStatusRejected, map[string]interface{}{
+			"refund_reason":     reason,
+			"refund_decided_at": time.Now(),
+		}); err != nil {
+			return fmt.Errorf("failed to reject refund: %w", err)
+		}
+
+		if err := tx.CreateNotification(ctx, &entities.PurchaseNotification{
+			UserID:     purchase.UserID,
+			PurchaseID: purchase.ID,
+			Type:       entities.NotificationRefundRejected,
+			Message:    "Your refund request was rejected by a seller: " + reason,
+		}); err != nil {
+			return err
+		}
+
+		return s.settleRefund(ctx, tx, purchase, sellerID)
+	})
+}
+
+// lockRefundRequest locks a purchase with a pending refund request on which
+// the seller still has to decide. The lock makes the sellers' decisions
+// settle the request one at a time.
+func (s *service) lockRefundRequest(ctx context.Context, tx Repository, purchaseID, sellerID string, to entities.PurchaseStatus) (*entities.Purchase, error) {
+	if _, _, err := s.getSellerPurchase(ctx, tx, purchaseID, sellerID); err != nil {
+		return nil, err
+	}
+
+	purchase, err := tx.LockPurchaseByID(ctx, purchaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock purchase: %w", err)
+	}
+	if purchase.Status != entities.PurchaseStatusRefundRequested {
+		return nil, &InvalidTransitionError{From: purchase.Status, To: to}
+	}
+
+	detail, err := getSellerPaymentDetail(ctx, tx, purchaseID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+	if detail.RefundStatus != entities.RefundStatusRequested {
+		return nil, fmt.Errorf("%w: seller has no refund decision to make", ErrConflict)
+	}
+	return purchase, nil
+}
+
+// settleRefund derives the outcome of a refund request from the sellers'
+// decisions. Nothing changes while a seller has not decided; then the
+// purchase is refunded if all sellers approved, and goes back to confirmed
+// with the sellers' reasons if any of them rejected.
+func (s *service) settleRefund(ctx context.Context, tx Repository, purchase *entities.Purchase, sellerID string) error {
+	purchaseID := purchase.ID.String()
+	details, err := tx.GetPurchasePaymentDetailsByPurchaseID(ctx, purchaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get purchase payment details: %w", err)
+	}
+
+	outcome, reason, settled := refundOutcome(details)
+	if !settled {
+		return nil
+	}
+
+	if outcome == entities.RefundStatusApproved {
+		if err := s.transitionStatus(ctx, tx, purchase, entities.PurchaseStatusRefunded, nil); err != nil {
+			return fmt.Errorf("failed to approve refund: %w", err)
+		}
+		return resolveRefund(ctx, tx, purchaseID, sellerID, entities.RefundStatusApproved, "")
+	}
+
+	if err := s.transitionStatus(ctx, tx, purchase, entities.PurchaseStatusConfirmed, map[string]interface{}{
+		"confirmed_at":  purchase.ConfirmedAt,
+		"status_reason": reason,
+	}); err != nil {
+		return fmt.Errorf("failed to reject refund: %w", err)
+	}
+	return resolveRefund(ctx, tx, purchaseID, sellerID, entities.RefundStatusRejected, reason)
+}
+
+// refundOutcome tells how the sellers' decisions settle a refund request. It
+// is approved when every seller asked approved it, and rejected with their
+// reasons when any of them rejected it. Settled is false while a seller has
+// not decided yet.
+func refundOutcome(details []*entities.PurchasePaymentDetail) (outcome entities.RefundStatus, reason string, settled bool) {
+	var reasons []string
+	for _, detail := range details {
+		switch detail.RefundStatus {
+		case entities.RefundStatusRequested:
+			return "", "", false
+		case entities.RefundStatusRejected:
+			reasons = append(reasons, detail.RefundReason)
+		}
+	}
+
+	if len(reasons) == 0 {
+		return entities.RefundStatusApproved, "", true
+	}
+	return entities.RefundStatusRejected, strings.Join(reasons, "; "), true
+}
+
+// decidePendingRefunds applies an admin's decision on a refund request to the
+// sellers that had not decided on it yet
+func decidePendingRefunds(ctx context.Context, repo Repository, purchaseID string, details []*entities.PurchasePaymentDetail, status entities.RefundStatus, reason string) error {
+	now := time.Now()
+	for _, detail := range details {
+		if detail.RefundStatus != entities.RefundStatusRequested {
+			continue
+		}
+		fields := map[string]interface{}{"refund_reason": "", "refund_decided_at": now}
+		if status == entities.RefundStatusRejected {
+			fields["refund_reason"] = reason
+		}
+		if err := repo.UpdatePaymentDetailRefundStatus(ctx, purchaseID, detail.SellerID.String(), entities.RefundStatusRequested, status, fields); err != nil {
+			return fmt.Errorf("failed to record refund decision: %w", err)
+		}
+	}
+	return nil
+}
+
+// resolveRefund records the final decision on the pending refund request
+func resolveRefund(ctx context.Context, repo Repository, purchaseID, sellerID string, status entities.RefundStatus, reason string) error {
+	refund, err := repo.GetLatestRefundByPurchaseID(ctx, purchaseID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && refund.Status != entities.RefundStatusRequested) {
+		return fmt.Errorf("%w: purchase has no pending refund request", ErrConflict)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get refund request: %w", err)
+	}
+
+	resolvedBy := uuid.MustParse(sellerID)
+	now := time.Now()
+	refund.Status = status
+	refund.ResolutionReason = reason
+	refund.ResolvedBy = &resolvedBy
+	refund.ResolvedAt = &now
+	if err := repo.UpdateRefund(ctx, refund); err != nil {
+		return fmt.Errorf("failed to update refund request: %w", err)
+	}
+	return nil
+}
+
+// notifySellers sends the same notification to every seller of a purchase
+func notifySellers(ctx context.Context, repo Repository, purchase *entities.Purchase, items []*entities.PurchaseItem, notificationType entities.NotificationType, message string) error {
+	notified := make(map[uuid.UUID]bool)
+	for _, item := range items {
+		// Items that could not be attributed to a seller have nobody to tell
+		if item.SellerID == uuid.Nil || notified[item.SellerID] {
+			continue
+		}
+		notified[item.SellerID] = true
+
+		if err := repo.CreateNotification(ctx, &entities.PurchaseNotification{
+			UserID:     item.SellerID,
+			PurchaseID: purchase.ID,
+			Type:       notificationType,
+			Message:    message,
+		}); err != nil {
+			return fmt.Errorf("failed to notify seller %s: %w", item.SellerID, err)
+		}
+	}
+	return nil
+}
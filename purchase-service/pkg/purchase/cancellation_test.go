@@ -0,0 +1,131 @@
+package purchase
+
+import (
+	"context"
+	"errors"
+	"purchase-service/pkg/entities"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestRefundOutcome(t *testing.T) {
+	detail := func(status entities.RefundStatus, reason string) *entities.PurchasePaymentDetail {
+		return &entities.PurchasePaymentDetail{RefundStatus: status, RefundReason: reason}
+	}
+
+	tests := []struct {
+		name        string
+		details     []*entities.PurchasePaymentDetail
+		wantOutcome entities.RefundStatus
+		wantReason  string
+		wantSettled bool
+	}{
+		{
+			name:        "single seller approves",
+			details:     []*entities.PurchasePaymentDetail{detail(entities.RefundStatusApproved, "")},
+			wantOutcome: entities.RefundStatusApproved,
+			wantSettled: true,
+		},
+		{
+			name:        "single seller rejects",
+			details:     []*entities.PurchasePaymentDetail{detail(entities.RefundStatusRejected, "item was used")},
+			wantOutcome: entities.RefundStatusRejected,
+			wantReason:  "item was used",
+			wantSettled: true,
+		},
+		{
+			name: "waits for a seller that has not decided",
+			details: []*entities.PurchasePaymentDetail{
+				detail(entities.RefundStatusApproved, ""),
+				detail(entities.RefundStatusRequested, ""),
+			},
+		},
+		{
+			name: "waits even after a rejection",
+			details: []*entities.PurchasePaymentDetail{
+				detail(entities.RefundStatusRejected, "item was used"),
+				detail(entities.RefundStatusRequested, ""),
+			},
+		},
+		{
+			name: "every seller approves",
+			details: []*entities.PurchasePaymentDetail{
+				detail(entities.RefundStatusApproved, ""),
+				detail(entities.RefundStatusApproved, ""),
+			},
+			wantOutcome: entities.RefundStatusApproved,
+			wantSettled: true,
+		},
+		{
+			name: "one rejection rejects the request",
+			details: []*entities.PurchasePaymentDetail{
+				detail(entities.RefundStatusApproved, ""),
+				detail(entities.RefundStatusRejected, "item was used"),
+			},
+			wantOutcome: entities.RefundStatusRejected,
+			wantReason:  "item was used",
+			wantSettled: true,
+		},
+		{
+			name: "reasons of every rejection are kept",
+			details: []*entities.PurchasePaymentDetail{
+				detail(entities.RefundStatusRejected, "item was used"),
+				detail(entities.RefundStatusRejected, "outside the return window"),
+			},
+			wantOutcome: entities.RefundStatusRejected,
+			wantReason:  "item was used; outside the return window",
+			wantSettled: true,
+		},
+		{
+			name: "sellers called off before the request are not asked",
+			details: []*entities.PurchasePaymentDetail{
+				detail("", ""),
+				detail(entities.RefundStatusApproved, ""),
+			},
+			wantOutcome: entities.RefundStatusApproved,
+			wantSettled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outcome, reason, settled := refundOutcome(tt.details)
+			if outcome != tt.wantOutcome || reason != tt.wantReason || settled != tt.wantSettled {
+				t.Errorf("refundOutcome() = %q, %q, %v, want %q, %q, %v",
+					outcome, reason, settled, tt.wantOutcome, tt.wantReason, tt.wantSettled)
+			}
+		})
+	}
+}
+
+func TestRequestRefundAfterRejection(t *testing.T) {
+	db := openTestDB(t)
+	s := &service{repo: NewGormRepository(db)}
+
+	seller := uuid.New()
+	purchase := seedPurchase(t, db, time.Now(), "IDR", entities.PurchaseStatusConfirmed,
+		map[uuid.UUID]entities.PurchaseStatus{seller: entities.PurchaseStatusConfirmed}, nil, nil,
+		seedLine{seller, "keyboard", "Keyboard", "electronics", 1, "100.00"})
+	buyerCtx := context.WithValue(context.Background(), "user_id", purchase.UserID.String())
+	sellerCtx := context.WithValue(context.Background(), "user_id", seller.String())
+
+	if err := s.RequestRefund(buyerCtx, purchase.ID.String(), "arrived broken"); err != nil {
+		t.Fatalf("RequestRefund() error = %v", err)
+	}
+	if err := s.RejectRefund(sellerCtx, purchase.ID.String(), "broken in use"); err != nil {
+		t.Fatalf("RejectRefund() error = %v", err)
+	}
+	if err := s.RequestRefund(buyerCtx, purchase.ID.String(), "arrived broken, really"); !errors.Is(err, ErrConflict) {
+		t.Fatalf("RequestRefund() after a rejection error = %v, want ErrConflict", err)
+	}
+
+	var got entities.Purchase
+	if err := db.First(&got, "id = ?", purchase.ID).Error; err != nil {
+		t.Fatalf("failed to read purchase: %v", err)
+	}
+	if got.Status != entities.PurchaseStatusConfirmed {
+		t.Errorf("purchase is %s, want %s", got.Status, entities.PurchaseStatusConfirmed)
+	}
+}
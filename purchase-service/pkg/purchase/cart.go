@@ -0,0 +1,370 @@
+package purchase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"purchase-service/api/presenter"
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/entities"
+	"purchase-service/pkg/money"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// maxCartItems caps the number of different products in a cart
+const maxCartItems = 50
+
+// errCartChanged is returned when the cart was changed by another request
+// while checkout was validating it
+var errCartChanged = fmt.Errorf("%w: the cart changed during checkout, review it and check out again", ErrConflict)
+
+// GetCart returns the buyer's cart priced with the current product details
+func (s *service) GetCart(ctx context.Context) (*presenter.CartResponse, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return nil, ErrUnauthenticated
+	}
+	return s.cartResponse(ctx, userID)
+}
+
+// AddCartItem adds a product to the cart, on top of the qty already in it.
+// The buyer is shown the current price, so it becomes the saved price.
+func (s *service) AddCartItem(ctx context.Context, req dtos.AddCartItemRequest) (*presenter.CartResponse, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	product, err := s.productClient.GetProductDetail(ctx, req.ProductID, userID)
+	if err != nil {
+		return nil, upstreamError(err, "failed to fetch product")
+	}
+	currency := product.Currency
+	if currency == "" {
+		currency = money.DefaultCurrency
+	}
+
+	if err := s.repo.WithTransaction(ctx, func(tx Repository) error {
+		cart, items, err := lockCart(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		// A purchase is paid in a single currency, so a cart is too
+		for _, other := range items {
+			if other.ProductID != req.ProductID && other.Currency != currency {
+				return fmt.Errorf("%w: the cart holds products priced in %s, not %s", ErrInvalidInput, other.Currency, currency)
+			}
+		}
+
+		item := findCartItem(items, req.ProductID)
+		if item == nil {
+			if len(items) >= maxCartItems {
+				return fmt.Errorf("%w: a cart holds at most %d products", ErrInvalidInput, maxCartItems)
+			}
+			item = &entities.CartItem{CartID: cart.ID, ProductID: req.ProductID}
+		}
+		item.Qty += req.Qty
+		if item.Qty > product.Qty {
+			return fmt.Errorf("%w: only %d of %s left in stock", ErrConflict, product.Qty, product.Name)
+		}
+		item.Price = product.Price
+		item.Currency = currency
+
+		return saveCartItems(ctx, tx, cart, item)
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.cartResponse(ctx, userID)
+}
+
+// UpdateCartItem sets the qty of a product already in the cart
+func (s *service) UpdateCartItem(ctx context.Context, productID string, req dtos.UpdateCartItemRequest) (*presenter.CartResponse, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	product, err := s.productClient.GetProductDetail(ctx, productID, userID)
+	if err != nil {
+		return nil, upstreamError(err, "failed to fetch product")
+	}
+	if req.Qty > product.Qty {
+		return nil, fmt.Errorf("%w: only %d of %s left in stock", ErrConflict, product.Qty, product.Name)
+	}
+
+	if err := s.repo.WithTransaction(ctx, func(tx Repository) error {
+		cart, items, err := lockCart(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+
+		item := findCartItem(items, productID)
+		if item == nil {
+			return fmt.Errorf("product %s %w in cart", productID, ErrNotFound)
+		}
+		item.Qty = req.Qty
+		item.Price = product.Price
+
+		return saveCartItems(ctx, tx, cart, item)
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.cartResponse(ctx, userID)
+}
+
+// RemoveCartItem takes a product out of the cart
+func (s *service) RemoveCartItem(ctx context.Context, productID string) (*presenter.CartResponse, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	if err := s.repo.WithTransaction(ctx, func(tx Repository) error {
+		cart, items, err := lockCart(ctx, tx, userID)
+		if err != nil {
+			return err
+		}
+		if findCartItem(items, productID) == nil {
+			return fmt.Errorf("product %s %w in cart", productID, ErrNotFound)
+		}
+
+		if err := tx.DeleteCartItem(ctx, cart.ID.String(), productID); err != nil {
+			return fmt.Errorf("failed to remove cart item: %w", err)
+		}
+		if err := tx.BumpCartVersion(ctx, cart.ID.String()); err != nil {
+			return fmt.Errorf("failed to update cart: %w", err)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return s.cartResponse(ctx, userID)
+}
+
+// ClearCart removes every product from the cart
+func (s *service) ClearCart(ctx context.Context) error {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return ErrUnauthenticated
+	}
+
+	return s.repo.WithTransaction(ctx, func(tx Repository) error {
+		cart, err := tx.LockCartByUserID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to lock cart: %w", err)
+		}
+		return clearCart(ctx, tx, cart)
+	})
+}
+
+// Checkout turns the buyer's cart into a purchase. Every item is checked
+// against the current product details first; when prices changed, the cart
+// takes the new prices and checkout fails so the buyer can review them. The
+// cart is emptied in the same transaction that creates the purchase.
+func (s *service) Checkout(ctx context.Context, req dtos.CheckoutRequest) (*presenter.PurchaseResponse, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	cart, err := s.repo.GetCartByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("%w: the cart is empty", ErrInvalidInput)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+	items, err := s.repo.GetCartItems(ctx, cart.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart items: %w", err)
+	}
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: the cart is empty", ErrInvalidInput)
+	}
+
+	products, err := s.findCartProducts(ctx, items, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	var repriced []*entities.CartItem
+	for _, item := range items {
+		product, ok := products[item.ProductID]
+		if !ok {
+			return nil, fmt.Errorf("%w: product %s is no longer available, remove it from the cart", ErrConflict, item.ProductID)
+		}
+		if product.Qty < item.Qty {
+			return nil, fmt.Errorf("%w: only %d of %s left in stock", ErrConflict, product.Qty, product.Name)
+		}
+		if product.Price != item.Price {
+			item.Price = product.Price
+			repriced = append(repriced, item)
+		}
+	}
+
+	if len(repriced) > 0 {
+		if err := s.repo.WithTransaction(ctx, func(tx Repository) error {
+			locked, err := tx.LockCartByUserID(ctx, userID)
+			if err != nil {
+				return fmt.Errorf("failed to lock cart: %w", err)
+			}
+			if locked.Version != cart.Version {
+				return errCartChanged
+			}
+			return saveCartItems(ctx, tx, locked, repriced...)
+		}); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("%w: the price of %d products in the cart changed, review the cart and check out again", ErrConflict, len(repriced))
+	}
+
+	purchaseReq := dtos.CreatePurchaseRequest{
+		PurchasedItems:      make([]dtos.PurchaseItemRequest, 0, len(items)),
+		SenderName:          req.SenderName,
+		SenderContactType:   req.SenderContactType,
+		SenderContactDetail: req.SenderContactDetail,
+		VoucherCode:         req.VoucherCode,
+		ShippingAddressID:   req.ShippingAddressID,
+		Shipping:            req.Shipping,
+	}
+	for _, item := range items {
+		purchaseReq.PurchasedItems = append(purchaseReq.PurchasedItems, dtos.PurchaseItemRequest{
+			ProductID: item.ProductID,
+			Qty:       item.Qty,
+		})
+	}
+
+	// Another checkout of the same cart waits for the lock, then sees the
+	// version bumped and rolls back its purchase
+	return s.placeOrder(ctx, userID, purchaseReq, products, func(tx Repository) error {
+		locked, err := tx.LockCartByUserID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to lock cart: %w", err)
+		}
+		if locked.Version != cart.Version {
+			return errCartChanged
+		}
+		return clearCart(ctx, tx, locked)
+	})
+}
+
+// cartResponse prices the user's cart with the current product details.
+// Users without a cart get an empty one.
+func (s *service) cartResponse(ctx context.Context, userID string) (*presenter.CartResponse, error) {
+	response := &presenter.CartResponse{Items: []presenter.CartItemResponse{}}
+
+	cart, err := s.repo.GetCartByUserID(ctx, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return response, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+	items, err := s.repo.GetCartItems(ctx, cart.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cart items: %w", err)
+	}
+
+	products, err := s.findCartProducts(ctx, items, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	response.UpdatedAt = cart.UpdatedAt.Format(time.RFC3339)
+	for _, item := range items {
+		itemResponse := presenter.CartItemResponse{
+			ProductID:  item.ProductID,
+			Qty:        item.Qty,
+			Price:      item.Price,
+			SavedPrice: item.Price,
+		}
+		// Deleted products stay listed so the buyer sees what to remove
+		if product, ok := products[item.ProductID]; ok {
+			itemResponse.SellerID = product.SellerID
+			itemResponse.Name = product.Name
+			itemResponse.Category = product.Category
+			itemResponse.SKU = product.SKU
+			itemResponse.FileThumbnailURI = product.FileThumbnailURI
+			itemResponse.Price = product.Price
+			itemResponse.PriceChanged = product.Price != item.Price
+			itemResponse.LineTotal = product.Price.Mul(item.Qty)
+			itemResponse.Stock = product.Qty
+			itemResponse.Available = product.Qty >= item.Qty
+			response.TotalPrice = response.TotalPrice.Add(itemResponse.LineTotal)
+		}
+		response.Currency = item.Currency
+		response.Items = append(response.Items, itemResponse)
+	}
+
+	return response, nil
+}
+
+// findCartProducts fetches the current details of the products in a cart,
+// leaving out products that were deleted since they were added
+func (s *service) findCartProducts(ctx context.Context, items []*entities.CartItem, userID string) (map[string]*presenter.ProductResponse, error) {
+	if len(items) == 0 {
+		return nil, nil
+	}
+
+	productIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		productIDs = append(productIDs, item.ProductID)
+	}
+	products, err := s.productClient.FindProductDetails(ctx, productIDs, userID)
+	if err != nil {
+		return nil, upstreamError(err, "failed to fetch products")
+	}
+	return products, nil
+}
+
+// lockCart locks the user's cart, creating it if needed, and reads its items
+func lockCart(ctx context.Context, repo Repository, userID string) (*entities.Cart, []*entities.CartItem, error) {
+	cart, err := repo.LockCartByUserID(ctx, userID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to lock cart: %w", err)
+	}
+	items, err := repo.GetCartItems(ctx, cart.ID.String())
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cart items: %w", err)
+	}
+	return cart, items, nil
+}
+
+// saveCartItems stores changed items of a locked cart and bumps its version
+func saveCartItems(ctx context.Context, repo Repository, cart *entities.Cart, items ...*entities.CartItem) error {
+	for _, item := range items {
+		if err := repo.SaveCartItem(ctx, item); err != nil {
+			return fmt.Errorf("failed to save cart item: %w", err)
+		}
+	}
+	if err := repo.BumpCartVersion(ctx, cart.ID.String()); err != nil {
+		return fmt.Errorf("failed to update cart: %w", err)
+	}
+	return nil
+}
+
+// clearCart empties a locked cart
+func clearCart(ctx context.Context, repo Repository, cart *entities.Cart) error {
+	if err := repo.DeleteCartItems(ctx, cart.ID.String()); err != nil {
+		return fmt.Errorf("failed to clear cart: %w", err)
+	}
+	if err := repo.BumpCartVersion(ctx, cart.ID.String()); err != nil {
+		return fmt.Errorf("failed to update cart: %w", err)
+	}
+	return nil
+}
+
+func findCartItem(items []*entities.CartItem, productID string) *entities.CartItem {
+	for _, item := range items {
+		if item.ProductID == productID {
+			return item
+		}
+	}
+	return nil
+}
@@ -0,0 +1,82 @@
+package purchase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"purchase-service/api/presenter"
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/http"
+	"purchase-service/pkg/money"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+// newProductCatalog serves GET /product/:productId for the given products
+func newProductCatalog(t *testing.T, products ...presenter.ProductResponse) *httptest.Server {
+	t.Helper()
+	byID := make(map[string]presenter.ProductResponse, len(products))
+	for _, product := range products {
+		byID[product.ID] = product
+	}
+	return httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		product, ok := byID[strings.TrimPrefix(r.URL.Path, "/product/")]
+		if r.Method != nethttp.MethodGet || !ok {
+			nethttp.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(product); err != nil {
+			t.Errorf("failed to encode product: %v", err)
+		}
+	}))
+}
+
+func TestAddCartItem(t *testing.T) {
+	db := openTestDB(t)
+
+	seller := uuid.NewString()
+	keyboard := presenter.ProductResponse{ID: uuid.NewString(), Name: "Keyboard", Qty: 3, Price: money.Amount(150000), Currency: "IDR", SellerID: seller}
+	mouse := presenter.ProductResponse{ID: uuid.NewString(), Name: "Mouse", Qty: 5, Price: money.Amount(7500), Currency: "USD", SellerID: seller}
+	catalog := newProductCatalog(t, keyboard, mouse)
+	defer catalog.Close()
+
+	s := &service{repo: NewGormRepository(db), productClient: http.NewClient(catalog.URL, "secret")}
+	ctx := context.WithValue(context.Background(), "user_id", uuid.NewString())
+
+	if _, err := s.AddCartItem(ctx, dtos.AddCartItemRequest{ProductID: keyboard.ID, Qty: 1}); err != nil {
+		t.Fatalf("AddCartItem() error = %v", err)
+	}
+	// Adding again goes on top of the qty already in the cart
+	cart, err := s.AddCartItem(ctx, dtos.AddCartItemRequest{ProductID: keyboard.ID, Qty: 2})
+	if err != nil {
+		t.Fatalf("AddCartItem() error = %v", err)
+	}
+	if len(cart.Items) != 1 || cart.Items[0].Qty != 3 {
+		t.Fatalf("cart items = %+v, want one keyboard with qty 3", cart.Items)
+	}
+	if cart.TotalPrice != money.Amount(450000) || cart.Currency != "IDR" {
+		t.Errorf("cart total = %v %s, want 450000 IDR", cart.TotalPrice, cart.Currency)
+	}
+
+	// More than the stock left
+	if _, err := s.AddCartItem(ctx, dtos.AddCartItemRequest{ProductID: keyboard.ID, Qty: 1}); !errors.Is(err, ErrConflict) {
+		t.Errorf("AddCartItem() past the stock error = %v, want ErrConflict", err)
+	}
+	// A cart is paid in a single currency
+	if _, err := s.AddCartItem(ctx, dtos.AddCartItemRequest{ProductID: mouse.ID, Qty: 1}); !errors.Is(err, ErrInvalidInput) {
+		t.Errorf("AddCartItem() in another currency error = %v, want ErrInvalidInput", err)
+	}
+
+	cart, err = s.GetCart(ctx)
+	if err != nil {
+		t.Fatalf("GetCart() error = %v", err)
+	}
+	if len(cart.Items) != 1 || cart.Items[0].Qty != 3 {
+		t.Errorf("cart items after refused additions = %+v, want one keyboard with qty 3", cart.Items)
+	}
+}
@@ -0,0 +1,637 @@
+package purchase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"purchase-service/api/presenter"
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/entities"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// OpenDispute starts a dispute on a purchase that is still in progress. Either
+// the buyer or one of the sellers can open it, and the other side is notified.
+func (s *service) OpenDispute(ctx context.Context, purchaseID string, req dtos.DisputeMessageRequest) (*presenter.DisputeResponse, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	fileIDs := uniqueStrings(req.FileIds)
+	if len(fileIDs) > 0 {
+		if err := s.verifyUserFiles(ctx, fileIDs, userID, "evidence"); err != nil {
+			return nil, err
+		}
+	}
+
+	var dispute *entities.PurchaseDispute
+	var message *entities.PurchaseDisputeMessage
+	var evidence []*entities.PurchaseDisputeEvidence
+	err := s.repo.WithTransaction(ctx, func(tx Repository) error {
+		_, items, role, err := getDisputeParticipant(ctx, tx, purchaseID, userID)
+		if err != nil {
+			return err
+		}
+
+		purchase, err := tx.LockPurchaseByID(ctx, purchaseID)
+		if err != nil {
+			return fmt.Errorf("failed to lock purchase: %w", err)
+		}
+		if IsFinalStatus(purchase.Status) {
+			return fmt.Errorf("%w: purchase is %s, only purchases in progress can be disputed", ErrConflict, purchase.Status)
+		}
+		latest, err := tx.GetLatestDisputeByPurchaseID(ctx, purchaseID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("failed to get dispute: %w", err)
+		}
+		if err == nil && latest.Status != entities.DisputeStatusResolved {
+			return fmt.Errorf("%w: purchase already has an active dispute", ErrConflict)
+		}
+
+		dispute = &entities.PurchaseDispute{
+			PurchaseID:   purchase.ID,
+			OpenedBy:     uuid.MustParse(userID),
+			OpenedByRole: role,
+			Status:       entities.DisputeStatusOpen,
+		}
+		if err := tx.CreateDispute(ctx, dispute); err != nil {
+			return fmt.Errorf("failed to create dispute: %w", err)
+		}
+
+		message, evidence, err = createDisputeMessage(ctx, tx, dispute, userID, role, req.Message, fileIDs)
+		if err != nil {
+			return err
+		}
+
+		return notifyOtherSide(ctx, tx, purchase, items, role, entities.NotificationDisputeOpened,
+			fmt.Sprintf("The %s opened a dispute: %s", role, req.Message))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.disputeMessageResponses(ctx, []*entities.PurchaseDisputeMessage{message}, evidence)
+	if err != nil {
+		return nil, err
+	}
+	response := disputeResponse(dispute)
+	response.Messages = messages
+	return &response, nil
+}
+
+// GetDispute returns the latest dispute of a purchase with its thread, for the
+// buyer and the sellers of the purchase
+func (s *service) GetDispute(ctx context.Context, purchaseID string) (*presenter.DisputeResponse, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	if _, _, _, err := getDisputeParticipant(ctx, s.repo, purchaseID, userID); err != nil {
+		return nil, err
+	}
+
+	dispute, err := s.repo.GetLatestDisputeByPurchaseID(ctx, purchaseID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDisputeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dispute: %w", err)
+	}
+
+	return s.disputeWithMessages(ctx, dispute)
+}
+
+// AddDisputeMessage adds to the thread of the active dispute of a purchase. A
+// message from one side leaves the dispute awaiting the other side, unless it
+// was escalated to an admin.
+func (s *service) AddDisputeMessage(ctx context.Context, purchaseID string, req dtos.DisputeMessageRequest) (*presenter.DisputeMessageResponse, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	fileIDs := uniqueStrings(req.FileIds)
+	if len(fileIDs) > 0 {
+		if err := s.verifyUserFiles(ctx, fileIDs, userID, "evidence"); err != nil {
+			return nil, err
+		}
+	}
+
+	var message *entities.PurchaseDisputeMessage
+	var evidence []*entities.PurchaseDisputeEvidence
+	err := s.repo.WithTransaction(ctx, func(tx Repository) error {
+		purchase, items, role, err := getDisputeParticipant(ctx, tx, purchaseID, userID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.LockPurchaseByID(ctx, purchaseID); err != nil {
+			return fmt.Errorf("failed to lock purchase: %w", err)
+		}
+		dispute, err := getActiveDispute(ctx, tx, purchaseID)
+		if err != nil {
+			return err
+		}
+
+		status := entities.DisputeStatusAwaitingSeller
+		if role == entities.DisputeRoleSeller {
+			status = entities.DisputeStatusAwaitingBuyer
+		}
+		if dispute.Status != entities.DisputeStatusEscalated && dispute.Status != status {
+			if err := tx.UpdateDisputeStatus(ctx, dispute.ID.String(), dispute.Status, status, nil); err != nil {
+				return fmt.Errorf("failed to update dispute status: %w", err)
+			}
+		}
+
+		message, evidence, err = createDisputeMessage(ctx, tx, dispute, userID, role, req.Message, fileIDs)
+		if err != nil {
+			return err
+		}
+
+		return notifyOtherSide(ctx, tx, purchase, items, role, entities.NotificationDisputeMessage,
+			fmt.Sprintf("The %s replied to the dispute: %s", role, req.Message))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	messages, err := s.disputeMessageResponses(ctx, []*entities.PurchaseDisputeMessage{message}, evidence)
+	if err != nil {
+		return nil, err
+	}
+	return &messages[0], nil
+}
+
+// EscalateDispute hands the active dispute of a purchase over to an admin when
+// the buyer and sellers cannot settle it themselves
+func (s *service) EscalateDispute(ctx context.Context, purchaseID string) error {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return ErrUnauthenticated
+	}
+
+	return s.repo.WithTransaction(ctx, func(tx Repository) error {
+		purchase, items, role, err := getDisputeParticipant(ctx, tx, purchaseID, userID)
+		if err != nil {
+			return err
+		}
+
+		if _, err := tx.LockPurchaseByID(ctx, purchaseID); err != nil {
+			return fmt.Errorf("failed to lock purchase: %w", err)
+		}
+		dispute, err := getActiveDispute(ctx, tx, purchaseID)
+		if err != nil {
+			return err
+		}
+		if dispute.Status == entities.DisputeStatusEscalated {
+			return fmt.Errorf("%w: dispute is already escalated", ErrConflict)
+		}
+
+		if err := tx.UpdateDisputeStatus(ctx, dispute.ID.String(), dispute.Status, entities.DisputeStatusEscalated, map[string]interface{}{
+			"escalated_at": time.Now(),
+		}); err != nil {
+			return fmt.Errorf("failed to escalate dispute: %w", err)
+		}
+
+		return notifyOtherSide(ctx, tx, purchase, items, role, entities.NotificationDisputeEscalated,
+			fmt.Sprintf("The %s escalated the dispute to an admin", role))
+	})
+}
+
+// ListDisputes lists all disputes for admins, newest first, optionally only
+// those in the given status
+func (s *service) ListDisputes(ctx context.Context, status string, page, limit int) (*presenter.ListDisputesResponse, error) {
+	switch entities.DisputeStatus(status) {
+	case "", entities.DisputeStatusOpen, entities.DisputeStatusAwaitingSeller, entities.DisputeStatusAwaitingBuyer,
+		entities.DisputeStatusEscalated, entities.DisputeStatusResolved:
+	default:
+		return nil, fmt.Errorf("%w: unknown dispute status %s", ErrInvalidInput, status)
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	disputes, total, err := s.repo.GetDisputes(ctx, entities.DisputeStatus(status), page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get disputes: %w", err)
+	}
+
+	responses := make([]presenter.DisputeResponse, 0, len(disputes))
+	for _, dispute := range disputes {
+		responses = append(responses, disputeResponse(dispute))
+	}
+
+	return &presenter.ListDisputesResponse{
+		Disputes: responses,
+		Total:    int(total),
+		Page:     page,
+		Limit:    limit,
+	}, nil
+}
+
+// GetDisputeByID returns any dispute with its thread, for admins
+func (s *service) GetDisputeByID(ctx context.Context, disputeID string) (*presenter.DisputeResponse, error) {
+	dispute, err := getDispute(ctx, s.repo, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	return s.disputeWithMessages(ctx, dispute)
+}
+
+// ResolveDispute closes a dispute on an admin's decision. The outcome can
+// force the purchase to cancelled or completed from any status it is not
+// final in, bypassing the usual transitions; the buyer and sellers are
+// notified either way.
+func (s *service) ResolveDispute(ctx context.Context, disputeID string, req dtos.ResolveDisputeRequest) (*presenter.DisputeResponse, error) {
+	adminID, ok := ctx.Value("user_id").(string)
+	if !ok || adminID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	dispute, err := getDispute(ctx, s.repo, disputeID)
+	if err != nil {
+		return nil, err
+	}
+	purchaseID := dispute.PurchaseID.String()
+	outcome := entities.DisputeOutcome(req.Outcome)
+
+	var buyerID string
+	var committed []string
+	err = s.repo.WithTransaction(ctx, func(tx Repository) error {
+		purchase, err := tx.LockPurchaseByID(ctx, purchaseID)
+		if err != nil {
+			return fmt.Errorf("failed to lock purchase: %w", err)
+		}
+		// Re-read under the lock, the thread may have moved on meanwhile
+		if dispute, err = getDispute(ctx, tx, disputeID); err != nil {
+			return err
+		}
+		if dispute.Status == entities.DisputeStatusResolved {
+			return fmt.Errorf("%w: dispute is already resolved", ErrConflict)
+		}
+
+		items, err := tx.GetPurchaseItemsByPurchaseID(ctx, purchaseID)
+		if err != nil {
+			return fmt.Errorf("failed to get purchase items: %w", err)
+		}
+
+		message := "An admin resolved the dispute"
+		switch outcome {
+		case entities.DisputeOutcomeCancel:
+			if err := s.forceCancel(ctx, tx, purchase, items, adminID, req.Note); err != nil {
+				return err
+			}
+			message += " and cancelled the purchase"
+		case entities.DisputeOutcomeComplete:
+			if committed, err = s.forceComplete(ctx, tx, purchase, items, adminID, req.Note); err != nil {
+				return err
+			}
+			message += " and completed the purchase"
+		}
+		message += ": " + req.Note
+
+		now := time.Now()
+		resolvedBy := uuid.MustParse(adminID)
+		if err := tx.UpdateDisputeStatus(ctx, disputeID, dispute.Status, entities.DisputeStatusResolved, map[string]interface{}{
+			"outcome":         outcome,
+			"resolution_note": req.Note,
+			"resolved_by":     resolvedBy,
+			"resolved_at":     now,
+		}); err != nil {
+			return fmt.Errorf("failed to resolve dispute: %w", err)
+		}
+		dispute.Status = entities.DisputeStatusResolved
+		dispute.Outcome = outcome
+		dispute.ResolutionNote = req.Note
+		dispute.ResolvedBy = &resolvedBy
+		dispute.ResolvedAt = &now
+		dispute.UpdatedAt = now
+
+		if err := tx.CreateNotification(ctx, &entities.PurchaseNotification{
+			UserID:     purchase.UserID,
+			PurchaseID: purchase.ID,
+			Type:       entities.NotificationDisputeResolved,
+			Message:    message,
+		}); err != nil {
+			return fmt.Errorf("failed to notify buyer: %w", err)
+		}
+		buyerID = purchase.UserID.String()
+		return notifySellers(ctx, tx, purchase, items, entities.NotificationDisputeResolved, message)
+	})
+	if err != nil {
+		return nil, err
+	}
+	s.commitSavedStock(ctx, purchaseID, committed, buyerID)
+
+	return s.disputeWithMessages(ctx, dispute)
+}
+
+// forceCancel cancels a purchase on an admin's decision. Stock of the sellers
+// that had not confirmed their payment goes back on sale; stock already sold
+// is not returned, as with refunds.
+func (s *service) forceCancel(ctx context.Context, tx Repository, purchase *entities.Purchase, items []*entities.PurchaseItem, adminID, note string) error {
+	if IsFinalStatus(purchase.Status) {
+		return fmt.Errorf("%w: purchase is already %s", ErrConflict, purchase.Status)
+	}
+	purchaseID := purchase.ID.String()
+
+	paymentDetails, err := tx.GetPurchasePaymentDetailsByPurchaseID(ctx, purchaseID)
+	if err != nil {
+		return fmt.Errorf("failed to get purchase payment details: %w", err)
+	}
+	confirmed := make(map[uuid.UUID]bool, len(paymentDetails))
+	for _, detail := range paymentDetails {
+		if detail.Status == entities.PurchaseStatusConfirmed {
+			confirmed[detail.SellerID] = true
+		}
+	}
+
+	// Cancelling gives the buyer their money back, which settles a pending refund request
+	if purchase.Status == entities.PurchaseStatusRefundRequested {
+		if err := decidePendingRefunds(ctx, tx, purchaseID, paymentDetails, entities.RefundStatusApproved, note); err != nil {
+			return err
+		}
+		if err := resolveRefund(ctx, tx, purchaseID, adminID, entities.RefundStatusApproved, note); err != nil {
+			return err
+		}
+	}
+
+	if err := s.setStatus(ctx, tx, purchase, entities.PurchaseStatusCancelled, map[string]interface{}{
+		"status_reason": note,
+	}); err != nil {
+		return fmt.Errorf("failed to cancel purchase: %w", err)
+	}
+	if err := tx.ReleaseVoucherRedemption(ctx, purchaseID); err != nil {
+		return fmt.Errorf("failed to release voucher: %w", err)
+	}
+
+	productIDs := make([]string, 0, len(items))
+	for _, item := range items {
+		if !confirmed[item.SellerID] {
+			productIDs = append(productIDs, item.ProductID)
+		}
+	}
+	if err := s.tryReleaseStock(ctx, purchaseID, productIDs, purchase.UserID.String()); err != nil {
+		return upstreamError(err, "failed to release reserved stock")
+	}
+	return nil
+}
+
+// forceComplete completes a purchase on an admin's decision. The payments to
+// sellers that had not confirmed them yet are confirmed, so the payment
+// details agree with the purchase status. It returns the products whose
+// reserved stock is to be committed once the transaction has.
+func (s *service) forceComplete(ctx context.Context, tx Repository, purchase *entities.Purchase, items []*entities.PurchaseItem, adminID, note string) ([]string, error) {
+	if IsFinalStatus(purchase.Status) {
+		return nil, fmt.Errorf("%w: purchase is already %s", ErrConflict, purchase.Status)
+	}
+	purchaseID := purchase.ID.String()
+
+	paymentDetails, err := tx.GetPurchasePaymentDetailsByPurchaseID(ctx, purchaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase payment details: %w", err)
+	}
+
+	// Completing keeps the money with the sellers, which turns down a pending refund request
+	if purchase.Status == entities.PurchaseStatusRefundRequested {
+		if err := decidePendingRefunds(ctx, tx, purchaseID, paymentDetails, entities.RefundStatusRejected, note); err != nil {
+			return nil, err
+		}
+		if err := resolveRefund(ctx, tx, purchaseID, adminID, entities.RefundStatusRejected, note); err != nil {
+			return nil, err
+		}
+	}
+	now := time.Now()
+	var productIDs []string
+	for _, detail := range paymentDetails {
+		// Payments called off stay that way
+		if detail.Status == entities.PurchaseStatusConfirmed || detail.Status == entities.PurchaseStatusCancelled {
+			continue
+		}
+		sellerID := detail.SellerID.String()
+		if err := tx.UpdatePaymentDetailStatus(ctx, purchaseID, sellerID, detail.Status, entities.PurchaseStatusConfirmed, map[string]interface{}{
+			"confirmed_at":  now,
+			"status_reason": "",
+		}); err != nil {
+			return nil, fmt.Errorf("failed to confirm payment to seller %s: %w", sellerID, err)
+		}
+		if err := tx.UpdatePaymentProofStatus(ctx, purchaseID, sellerID, entities.PaymentProofStatusPending, entities.PaymentProofStatusAccepted, &now); err != nil {
+			return nil, fmt.Errorf("failed to accept payment proofs: %w", err)
+		}
+		for _, item := range items {
+			if item.SellerID == detail.SellerID {
+				productIDs = append(productIDs, item.ProductID)
+			}
+		}
+	}
+
+	fields := map[string]interface{}{"status_reason": note}
+	if purchase.ConfirmedAt == nil {
+		fields["confirmed_at"] = now
+	}
+	if err := s.setStatus(ctx, tx, purchase, entities.PurchaseStatusCompleted, fields); err != nil {
+		return nil, fmt.Errorf("failed to complete purchase: %w", err)
+	}
+
+	return productIDs, nil
+}
+
+// getDisputeParticipant loads a purchase and its items, and tells whether the
+// user takes part in its disputes as the buyer or as one of the sellers
+func getDisputeParticipant(ctx context.Context, repo Repository, purchaseID, userID string) (*entities.Purchase, []*entities.PurchaseItem, entities.DisputeRole, error) {
+	purchase, err := getPurchase(ctx, repo, purchaseID)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	items, err := repo.GetPurchaseItemsByPurchaseID(ctx, purchaseID)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to get purchase items: %w", err)
+	}
+
+	if purchase.UserID.String() == userID {
+		return purchase, items, entities.DisputeRoleBuyer, nil
+	}
+	for _, item := range items {
+		if item.SellerID.String() == userID {
+			return purchase, items, entities.DisputeRoleSeller, nil
+		}
+	}
+	return nil, nil, "", fmt.Errorf("%w: purchase does not belong to user", ErrForbidden)
+}
+
+// getDispute loads a dispute, reporting malformed and unknown IDs as not found
+func getDispute(ctx context.Context, repo Repository, disputeID string) (*entities.PurchaseDispute, error) {
+	if _, err := uuid.Parse(disputeID); err != nil {
+		return nil, ErrDisputeNotFound
+	}
+
+	dispute, err := repo.GetDisputeByID(ctx, disputeID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDisputeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dispute: %w", err)
+	}
+	return dispute, nil
+}
+
+// getActiveDispute loads the dispute of a purchase that is not resolved yet
+func getActiveDispute(ctx context.Context, repo Repository, purchaseID string) (*entities.PurchaseDispute, error) {
+	dispute, err := repo.GetLatestDisputeByPurchaseID(ctx, purchaseID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrDisputeNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dispute: %w", err)
+	}
+	if dispute.Status == entities.DisputeStatusResolved {
+		return nil, fmt.Errorf("%w: dispute is already resolved", ErrConflict)
+	}
+	return dispute, nil
+}
+
+// createDisputeMessage adds a message and its evidence files to a dispute
+func createDisputeMessage(ctx context.Context, tx Repository, dispute *entities.PurchaseDispute, senderID string, role entities.DisputeRole, text string, fileIDs []string) (*entities.PurchaseDisputeMessage, []*entities.PurchaseDisputeEvidence, error) {
+	message := &entities.PurchaseDisputeMessage{
+		DisputeID:  dispute.ID,
+		SenderID:   uuid.MustParse(senderID),
+		SenderRole: role,
+		Message:    text,
+	}
+	if err := tx.CreateDisputeMessage(ctx, message); err != nil {
+		return nil, nil, fmt.Errorf("failed to create dispute message: %w", err)
+	}
+
+	evidence := make([]*entities.PurchaseDisputeEvidence, 0, len(fileIDs))
+	for i, fileID := range fileIDs {
+		evidence = append(evidence, &entities.PurchaseDisputeEvidence{
+			DisputeID: dispute.ID,
+			MessageID: message.ID,
+			FileID:    uuid.MustParse(fileID),
+			Position:  i,
+		})
+	}
+	if err := tx.CreateDisputeEvidence(ctx, evidence); err != nil {
+		return nil, nil, fmt.Errorf("failed to create dispute evidence: %w", err)
+	}
+	return message, evidence, nil
+}
+
+// notifyOtherSide tells the sellers about what the buyer did in a dispute, or
+// the buyer about what a seller did
+func notifyOtherSide(ctx context.Context, tx Repository, purchase *entities.Purchase, items []*entities.PurchaseItem, role entities.DisputeRole, notificationType entities.NotificationType, message string) error {
+	if role == entities.DisputeRoleBuyer {
+		return notifySellers(ctx, tx, purchase, items, notificationType, message)
+	}
+
+	if err := tx.CreateNotification(ctx, &entities.PurchaseNotification{
+		UserID:     purchase.UserID,
+		PurchaseID: purchase.ID,
+		Type:       notificationType,
+		Message:    message,
+	}); err != nil {
+		return fmt.Errorf("failed to notify buyer: %w", err)
+	}
+	return nil
+}
+
+// disputeWithMessages converts a stored dispute to its API representation,
+// including the whole thread
+func (s *service) disputeWithMessages(ctx context.Context, dispute *entities.PurchaseDispute) (*presenter.DisputeResponse, error) {
+	disputeID := dispute.ID.String()
+	messages, err := s.repo.GetDisputeMessagesByDisputeID(ctx, disputeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dispute messages: %w", err)
+	}
+	evidence, err := s.repo.GetDisputeEvidenceByDisputeID(ctx, disputeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get dispute evidence: %w", err)
+	}
+
+	messageResponses, err := s.disputeMessageResponses(ctx, messages, evidence)
+	if err != nil {
+		return nil, err
+	}
+	response := disputeResponse(dispute)
+	response.Messages = messageResponses
+	return &response, nil
+}
+
+// disputeMessageResponses converts stored dispute messages to their API
+// representation, attaching the evidence files of each
+func (s *service) disputeMessageResponses(ctx context.Context, messages []*entities.PurchaseDisputeMessage, evidence []*entities.PurchaseDisputeEvidence) ([]presenter.DisputeMessageResponse, error) {
+	fileIDs := make([]string, 0, len(evidence))
+	for _, file := range evidence {
+		fileIDs = append(fileIDs, file.FileID.String())
+	}
+
+	filesByID := s.getFiles(ctx, fileIDs)
+
+	evidenceByMessage := make(map[string][]presenter.DisputeEvidence)
+	for _, file := range evidence {
+		messageID := file.MessageID.String()
+		response := presenter.DisputeEvidence{FileID: file.FileID.String()}
+		if stored, ok := filesByID[response.FileID]; ok {
+			response.FileURI = stored.FileURI
+			response.FileThumbnailURI = stored.FileThumbnailURI
+		}
+		evidenceByMessage[messageID] = append(evidenceByMessage[messageID], response)
+	}
+
+	responses := make([]presenter.DisputeMessageResponse, 0, len(messages))
+	for _, message := range messages {
+		messageEvidence := evidenceByMessage[message.ID.String()]
+		if messageEvidence == nil {
+			messageEvidence = []presenter.DisputeEvidence{}
+		}
+		responses = append(responses, presenter.DisputeMessageResponse{
+			MessageID:  message.ID.String(),
+			SenderID:   message.SenderID.String(),
+			SenderRole: string(message.SenderRole),
+			Message:    message.Message,
+			Evidence:   messageEvidence,
+			CreatedAt:  message.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return responses, nil
+}
+
+// disputeResponse converts a stored dispute to its API representation, without the thread
+func disputeResponse(dispute *entities.PurchaseDispute) presenter.DisputeResponse {
+	format := func(t *time.Time) *string {
+		if t == nil {
+			return nil
+		}
+		formatted := t.Format(time.RFC3339)
+		return &formatted
+	}
+
+	response := presenter.DisputeResponse{
+		DisputeID:      dispute.ID.String(),
+		PurchaseID:     dispute.PurchaseID.String(),
+		OpenedBy:       dispute.OpenedBy.String(),
+		OpenedByRole:   string(dispute.OpenedByRole),
+		Status:         string(dispute.Status),
+		Outcome:        string(dispute.Outcome),
+		ResolutionNote: dispute.ResolutionNote,
+		EscalatedAt:    format(dispute.EscalatedAt),
+		ResolvedAt:     format(dispute.ResolvedAt),
+		CreatedAt:      dispute.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      dispute.UpdatedAt.Format(time.RFC3339),
+	}
+	if dispute.ResolvedBy != nil {
+		resolvedBy := dispute.ResolvedBy.String()
+		response.ResolvedBy = &resolvedBy
+	}
+	return response
+}
@@ -0,0 +1,105 @@
+package purchase
+
+import (
+	"context"
+	"net/http/httptest"
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/entities"
+	"purchase-service/pkg/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestResolveDispute(t *testing.T) {
+	db := openTestDB(t)
+
+	tests := []struct {
+		outcome       entities.DisputeOutcome
+		wantStatus    entities.PurchaseStatus
+		wantDetail    entities.PurchaseStatus // Of the seller that had not confirmed
+		wantCommitted []string
+		wantReleased  []string
+	}{
+		{
+			outcome:      entities.DisputeOutcomeCancel,
+			wantStatus:   entities.PurchaseStatusCancelled,
+			wantDetail:   entities.PurchaseStatusProofUploaded,
+			wantReleased: []string{"mouse"},
+		},
+		{
+			outcome:       entities.DisputeOutcomeComplete,
+			wantStatus:    entities.PurchaseStatusCompleted,
+			wantDetail:    entities.PurchaseStatusConfirmed,
+			wantCommitted: []string{"mouse"},
+		},
+		{
+			outcome:    entities.DisputeOutcomeNone,
+			wantStatus: entities.PurchaseStatusProofUploaded,
+			wantDetail: entities.PurchaseStatusProofUploaded,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.outcome), func(t *testing.T) {
+			productHandler := &fakeProductService{}
+			productService := httptest.NewServer(productHandler)
+			defer productService.Close()
+			s := &service{
+				repo:          NewGormRepository(db),
+				productClient: http.NewClient(productService.URL, "secret"),
+			}
+
+			// The first seller confirmed; the second has a proof to check
+			paid := uuid.New()
+			unpaid := uuid.New()
+			purchase := seedPurchase(t, db, time.Now(), "IDR", entities.PurchaseStatusProofUploaded,
+				map[uuid.UUID]entities.PurchaseStatus{paid: entities.PurchaseStatusConfirmed, unpaid: entities.PurchaseStatusProofUploaded}, nil, nil,
+				seedLine{paid, "keyboard", "Keyboard", "electronics", 1, "100.00"},
+				seedLine{unpaid, "mouse", "Mouse", "electronics", 1, "50.00"})
+			dispute := &entities.PurchaseDispute{
+				PurchaseID:   purchase.ID,
+				OpenedBy:     purchase.UserID,
+				OpenedByRole: entities.DisputeRoleBuyer,
+				Status:       entities.DisputeStatusEscalated,
+			}
+			if err := db.Create(dispute).Error; err != nil {
+				t.Fatalf("failed to seed dispute: %v", err)
+			}
+
+			ctx := context.WithValue(context.Background(), "user_id", uuid.NewString())
+			response, err := s.ResolveDispute(ctx, dispute.ID.String(), dtos.ResolveDisputeRequest{Outcome: string(tt.outcome), Note: "checked the transfer"})
+			if err != nil {
+				t.Fatalf("ResolveDispute() error = %v", err)
+			}
+			if response.Status != string(entities.DisputeStatusResolved) || response.Outcome != string(tt.outcome) {
+				t.Errorf("dispute is %s with outcome %s, want resolved with %s", response.Status, response.Outcome, tt.outcome)
+			}
+
+			var got entities.Purchase
+			if err := db.First(&got, "id = ?", purchase.ID).Error; err != nil {
+				t.Fatalf("failed to read purchase: %v", err)
+			}
+			if got.Status != tt.wantStatus {
+				t.Errorf("purchase is %s, want %s", got.Status, tt.wantStatus)
+			}
+			var detail entities.PurchasePaymentDetail
+			if err := db.First(&detail, "purchase_id = ? AND seller_id = ?", purchase.ID, unpaid).Error; err != nil {
+				t.Fatalf("failed to read payment detail: %v", err)
+			}
+			if detail.Status != tt.wantDetail {
+				t.Errorf("payment to the unpaid seller is %s, want %s", detail.Status, tt.wantDetail)
+			}
+
+			// Stock the paid seller already sold is never touched
+			if !reflect.DeepEqual(productHandler.committed, tt.wantCommitted) {
+				t.Errorf("committed %v, want %v", productHandler.committed, tt.wantCommitted)
+			}
+			if !reflect.DeepEqual(productHandler.released, tt.wantReleased) {
+				t.Errorf("released %v, want %v", productHandler.released, tt.wantReleased)
+			}
+		})
+	}
+}
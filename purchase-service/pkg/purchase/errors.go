@@ -0,0 +1,78 @@
+package purchase
+
+import (
+	"errors"
+	"fmt"
+	nethttp "net/http"
+	"purchase-service/pkg/http"
+)
+
+// Error kinds returned by the service. Handlers match them with errors.Is to
+// pick the response status; the wrapping error says what went wrong.
+var (
+	ErrUnauthenticated     = errors.New("user context not found")
+	ErrNotFound            = errors.New("not found")
+	ErrForbidden           = errors.New("access denied")
+	ErrInvalidInput        = errors.New("invalid input")
+	ErrConflict            = errors.New("conflict")
+	ErrUpstreamUnavailable = errors.New("upstream service unavailable")
+)
+
+// ErrPurchaseNotFound is returned for unknown and malformed purchase IDs alike
+var ErrPurchaseNotFound = fmt.Errorf("purchase %w", ErrNotFound)
+
+// ErrDisputeNotFound is returned for unknown and malformed dispute IDs alike,
+// and for purchases that were never disputed
+var ErrDisputeNotFound = fmt.Errorf("dispute %w", ErrNotFound)
+
+func (e *InvalidTransitionError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+func (e *StatusConflictError) Is(target error) bool {
+	return target == ErrConflict
+}
+
+// upstreamFailure is a failed call to another service. Its message is safe to
+// show to clients while the original error stays reachable through errors.As.
+type upstreamFailure struct {
+	kind    error
+	message string
+	cause   error
+}
+
+func (e *upstreamFailure) Error() string {
+	return e.message
+}
+
+func (e *upstreamFailure) Unwrap() []error {
+	return []error{e.kind, e.cause}
+}
+
+// upstreamError classifies a failed call to another service. A 404 means the
+// referenced resource does not exist and a 409 that the other service refused
+// the change; anything else, including network failures, is treated as the
+// other service being unavailable.
+func upstreamError(err error, action string) error {
+	failure := &upstreamFailure{kind: ErrUpstreamUnavailable, cause: err}
+
+	reason := ErrUpstreamUnavailable.Error()
+	var statusErr *http.StatusError
+	if errors.As(err, &statusErr) {
+		switch statusErr.StatusCode {
+		case nethttp.StatusNotFound:
+			failure.kind = ErrNotFound
+		case nethttp.StatusConflict:
+			failure.kind = ErrConflict
+		}
+		if failure.kind != ErrUpstreamUnavailable {
+			reason = failure.kind.Error()
+			if message := statusErr.Message(); message != "" {
+				reason = message
+			}
+		}
+	}
+
+	failure.message = action + ": " + reason
+	return failure
+}
@@ -0,0 +1,176 @@
+package purchase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"purchase-service/pkg/entities"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// ExpiryReasonPaymentDeadline is recorded on purchases expired by the worker
+const ExpiryReasonPaymentDeadline = "payment deadline exceeded"
+
+// ExpireOverduePurchases expires the purchases that are still waiting for
+// payment after the deadline and releases their stock. When some sellers of a
+// purchase already confirmed their payment, only the payments to the other
+// sellers are called off and the purchase goes on as confirmed. Candidates
+// are read batchSize at a time and each one is expired in a transaction of
+// its own, so a purchase that fails is logged and passed over until the next
+// run without holding back the others. It returns the number of purchases
+// expired or partly cancelled.
+func (s *service) ExpireOverduePurchases(ctx context.Context, deadline time.Duration, batchSize int) (int, error) {
+	expired := 0
+	cutoff := time.Now().Add(-deadline)
+
+	afterID := uuid.Nil
+	for {
+		purchases, err := s.repo.GetPurchasesCreatedBefore(ctx, entities.PurchaseStatusPendingPayment, cutoff, afterID, batchSize)
+		if err != nil {
+			return expired, fmt.Errorf("failed to get overdue purchases: %w", err)
+		}
+		if len(purchases) == 0 {
+			return expired, nil
+		}
+		afterID = purchases[len(purchases)-1].ID
+
+		for _, purchase := range purchases {
+			ok, err := s.expirePurchase(ctx, purchase.ID.String(), cutoff)
+			if err != nil {
+				log.Printf("failed to expire purchase %s: %v", purchase.ID, err)
+				continue
+			}
+			if ok {
+				expired++
+			}
+		}
+
+		if len(purchases) < batchSize {
+			return expired, nil
+		}
+	}
+}
+
+// expirePurchase expires one overdue purchase, or the unpaid part of it, and
+// reports whether it did. The row is claimed with SELECT ... FOR UPDATE SKIP
+// LOCKED, so several replicas can run the worker without expiring the same
+// purchase twice.
+func (s *service) expirePurchase(ctx context.Context, purchaseID string, cutoff time.Time) (bool, error) {
+	var purchase *entities.Purchase
+	var productIDs []string
+	err := s.repo.WithTransaction(ctx, func(tx Repository) error {
+		var err error
+		purchase, err = tx.LockPurchaseCreatedBefore(ctx, purchaseID, entities.PurchaseStatusPendingPayment, cutoff)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// Paid, disputed or taken by another replica since it was listed
+			purchase = nil
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to lock purchase: %w", err)
+		}
+
+		items, err := tx.GetPurchaseItemsByPurchaseID(ctx, purchaseID)
+		if err != nil {
+			return fmt.Errorf("failed to get purchase items: %w", err)
+		}
+		paymentDetails, err := tx.GetPurchasePaymentDetailsByPurchaseID(ctx, purchaseID)
+		if err != nil {
+			return fmt.Errorf("failed to get payment details: %w", err)
+		}
+
+		if hasConfirmedPayment(paymentDetails) {
+			productIDs, err = s.expireUnconfirmedPayments(ctx, tx, purchase, paymentDetails, items)
+			return err
+		}
+
+		if err := s.transitionStatus(ctx, tx, purchase, entities.PurchaseStatusExpired, map[string]interface{}{
+			"status_reason": ExpiryReasonPaymentDeadline,
+		}); err != nil {
+			return fmt.Errorf("failed to expire purchase: %w", err)
+		}
+		if err := tx.ReleaseVoucherRedemption(ctx, purchaseID); err != nil {
+			return fmt.Errorf("failed to release voucher: %w", err)
+		}
+		productIDs = itemProductIDs(items)
+		return nil
+	})
+	if err != nil || purchase == nil {
+		return false, err
+	}
+
+	// Stock goes back only once the purchase can no longer be paid, so a
+	// payment confirmed meanwhile never finds its stock released. Release
+	// is idempotent, so retrying is safe.
+	if err := retryStock(func() error {
+		return s.tryReleaseStock(ctx, purchaseID, productIDs, purchase.UserID.String())
+	}); err != nil {
+		log.Printf("failed to release stock of expired purchase %s: %v", purchaseID, err)
+	}
+	return true, nil
+}
+
+// expireUnconfirmedPayments calls off the overdue payments of a purchase that
+// other sellers already confirmed, lets the sellers that were not paid know
+// and returns their products, whose stock is to be released.
+func (s *service) expireUnconfirmedPayments(ctx context.Context, tx Repository, purchase *entities.Purchase, details []*entities.PurchasePaymentDetail, items []*entities.PurchaseItem) ([]string, error) {
+	cancelledItems, err := s.cancelUnconfirmedPayments(ctx, tx, purchase, details, items, ExpiryReasonPaymentDeadline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expire unpaid sellers: %w", err)
+	}
+	if err := notifySellers(ctx, tx, purchase, cancelledItems, entities.NotificationPurchaseCancelled,
+		"A purchase of your products was not paid before the deadline and was cancelled"); err != nil {
+		return nil, err
+	}
+	return itemProductIDs(cancelledItems), nil
+}
+
+// ExpiryWorker periodically expires purchases whose payment deadline passed
+type ExpiryWorker struct {
+	service   Service
+	interval  time.Duration
+	deadline  time.Duration
+	batchSize int
+}
+
+func NewExpiryWorker(service Service, interval, deadline time.Duration, batchSize int) *ExpiryWorker {
+	return &ExpiryWorker{
+		service:   service,
+		interval:  interval,
+		deadline:  deadline,
+		batchSize: batchSize,
+	}
+}
+
+// Run expires overdue purchases every interval until ctx is cancelled
+func (w *ExpiryWorker) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	log.Printf("purchase expiry worker started (deadline %s, interval %s)", w.deadline, w.interval)
+	for {
+		w.expireAll(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// expireAll expires everything overdue; purchases that failed are tried
+// again on the next run
+func (w *ExpiryWorker) expireAll(ctx context.Context) {
+	expired, err := w.service.ExpireOverduePurchases(ctx, w.deadline, w.batchSize)
+	if err != nil {
+		log.Printf("failed to expire overdue purchases: %v", err)
+	}
+	if expired > 0 {
+		log.Printf("expired %d overdue purchases", expired)
+	}
+}
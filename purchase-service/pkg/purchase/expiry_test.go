@@ -0,0 +1,101 @@
+package purchase
+
+import (
+	"context"
+	"net/http/httptest"
+	"purchase-service/pkg/entities"
+	"purchase-service/pkg/http"
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestExpireOverduePurchases(t *testing.T) {
+	db := openTestDB(t)
+	productHandler := &fakeProductService{}
+	productService := httptest.NewServer(productHandler)
+	defer productService.Close()
+
+	s := &service{
+		repo:          NewGormRepository(db),
+		productClient: http.NewClient(productService.URL, "secret"),
+	}
+
+	seller := uuid.New()
+	otherSeller := uuid.New()
+	overdue := time.Now().Add(-48 * time.Hour)
+	unpaid := map[uuid.UUID]entities.PurchaseStatus{seller: entities.PurchaseStatusPendingPayment}
+
+	// Not paid in time, so expired with all of its stock
+	expired := seedPurchase(t, db, overdue, "IDR", entities.PurchaseStatusPendingPayment, unpaid, nil, nil,
+		seedLine{seller, "keyboard", "Keyboard", "electronics", 1, "100.00"})
+	// One seller confirmed, so only the other seller's part is called off
+	partlyPaid := seedPurchase(t, db, overdue, "IDR", entities.PurchaseStatusPendingPayment,
+		map[uuid.UUID]entities.PurchaseStatus{seller: entities.PurchaseStatusConfirmed, otherSeller: entities.PurchaseStatusPendingPayment}, nil, nil,
+		seedLine{seller, "monitor", "Monitor", "electronics", 1, "300.00"},
+		seedLine{otherSeller, "mouse", "Mouse", "electronics", 2, "50.00"})
+	// Read on a later page, as the batch size is one
+	alsoExpired := seedPurchase(t, db, overdue.Add(time.Minute), "IDR", entities.PurchaseStatusPendingPayment, unpaid, nil, nil,
+		seedLine{seller, "mouse-pad", "Mouse pad", "accessories", 1, "20.00"})
+	// Still within the deadline
+	recent := seedPurchase(t, db, time.Now().Add(-time.Hour), "IDR", entities.PurchaseStatusPendingPayment, unpaid, nil, nil,
+		seedLine{seller, "webcam", "Webcam", "electronics", 1, "80.00"})
+	// Waiting for the seller to check the proof
+	uploaded := seedPurchase(t, db, overdue, "IDR", entities.PurchaseStatusProofUploaded,
+		map[uuid.UUID]entities.PurchaseStatus{seller: entities.PurchaseStatusProofUploaded}, nil, nil,
+		seedLine{seller, "headset", "Headset", "electronics", 1, "60.00"})
+
+	count, err := s.ExpireOverduePurchases(context.Background(), 24*time.Hour, 1)
+	if err != nil {
+		t.Fatalf("ExpireOverduePurchases() error = %v", err)
+	}
+	if count != 3 {
+		t.Errorf("ExpireOverduePurchases() = %d, want 3", count)
+	}
+
+	wantStatus := map[*entities.Purchase]entities.PurchaseStatus{
+		expired:     entities.PurchaseStatusExpired,
+		partlyPaid:  entities.PurchaseStatusConfirmed,
+		alsoExpired: entities.PurchaseStatusExpired,
+		recent:      entities.PurchaseStatusPendingPayment,
+		uploaded:    entities.PurchaseStatusProofUploaded,
+	}
+	for seeded, want := range wantStatus {
+		var purchase entities.Purchase
+		if err := db.First(&purchase, "id = ?", seeded.ID).Error; err != nil {
+			t.Fatalf("failed to read purchase %s: %v", seeded.ID, err)
+		}
+		if purchase.Status != want {
+			t.Errorf("purchase %s is %s, want %s", seeded.ID, purchase.Status, want)
+		}
+	}
+
+	var details []*entities.PurchasePaymentDetail
+	if err := db.Where("purchase_id = ?", partlyPaid.ID).Find(&details).Error; err != nil {
+		t.Fatalf("failed to read payment details: %v", err)
+	}
+	for _, detail := range details {
+		want := entities.PurchaseStatusConfirmed
+		if detail.SellerID == otherSeller {
+			want = entities.PurchaseStatusCancelled
+		}
+		if detail.Status != want {
+			t.Errorf("payment to seller %s is %s, want %s", detail.SellerID, detail.Status, want)
+		}
+	}
+
+	// Only stock of the purchases, or sellers, called off goes back
+	released := append([]string(nil), productHandler.released...)
+	sort.Strings(released)
+	if want := []string{"keyboard", "mouse", "mouse-pad"}; !reflect.DeepEqual(released, want) {
+		t.Errorf("released %v, want %v", released, want)
+	}
+
+	// Nothing is left for the next run
+	if count, err := s.ExpireOverduePurchases(context.Background(), 24*time.Hour, 1); err != nil || count != 0 {
+		t.Errorf("second ExpireOverduePurchases() = %d, %v, want 0 and no error", count, err)
+	}
+}
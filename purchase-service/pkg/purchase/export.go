@@ -0,0 +1,124 @@
+package purchase
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/entities"
+	"purchase-service/pkg/export"
+	"purchase-service/pkg/money"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ExportFunc writes a prepared export to w. It is called once the response
+// headers are sent, after the request context is gone, so it takes its own.
+type ExportFunc func(ctx context.Context, w io.Writer) error
+
+// exportRow is one purchase item together with its purchase and the payment
+// to the item's seller
+type exportRow struct {
+	PurchaseID         uuid.UUID
+	CreatedAt          time.Time
+	Status             entities.PurchaseStatus
+	BuyerID            uuid.UUID
+	Currency           string
+	SellerID           uuid.UUID
+	ProductID          string
+	Name               string
+	SKU                string
+	Qty                int
+	Price              money.Amount
+	LineTotal          money.Amount
+	PaymentStatus      string
+	PaymentConfirmedAt *time.Time
+}
+
+var exportHeader = []string{
+	"Purchase ID", "Purchase date", "Status", "Buyer ID", "Seller ID", "Product ID", "Item", "SKU",
+	"Qty", "Unit price", "Line total", "Currency", "Payment status", "Payment confirmed at",
+}
+
+// ExportPurchases prepares an export of the buyer's purchases with one row
+// per purchased item
+func (s *service) ExportPurchases(ctx context.Context, filter dtos.ExportPurchasesFilter) (ExportFunc, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return nil, ErrUnauthenticated
+	}
+	return s.prepareExport(userID, "", filter)
+}
+
+// ExportSellerOrders prepares an export of the seller's own items across all
+// purchases, with the state of the buyer's payment to the seller
+func (s *service) ExportSellerOrders(ctx context.Context, filter dtos.ExportPurchasesFilter) (ExportFunc, error) {
+	sellerID, ok := ctx.Value("user_id").(string)
+	if !ok || sellerID == "" {
+		return nil, ErrUnauthenticated
+	}
+	return s.prepareExport("", sellerID, filter)
+}
+
+// prepareExport validates the filter up front, since errors found while
+// streaming can no longer change the response status
+func (s *service) prepareExport(buyerID, sellerID string, filter dtos.ExportPurchasesFilter) (ExportFunc, error) {
+	if !export.IsValidFormat(filter.Format) {
+		return nil, fmt.Errorf("%w: unknown export format %s", ErrInvalidInput, filter.Format)
+	}
+	if filter.Status != "" && !IsValidStatus(filter.Status) {
+		return nil, fmt.Errorf("%w: unknown status %s", ErrInvalidInput, filter.Status)
+	}
+
+	return func(ctx context.Context, w io.Writer) error {
+		out, err := export.NewWriter(filter.Format, w)
+		if err != nil {
+			return err
+		}
+
+		header := make([]export.Cell, len(exportHeader))
+		for i, title := range exportHeader {
+			header[i] = export.Text(title)
+		}
+		if err := out.WriteRow(header); err != nil {
+			return err
+		}
+
+		if err := s.repo.StreamExportRows(ctx, buyerID, sellerID, filter, func(row *exportRow) error {
+			return out.WriteRow(exportCells(row))
+		}); err != nil {
+			return fmt.Errorf("failed to export purchases: %w", err)
+		}
+		return out.Close()
+	}, nil
+}
+
+func exportCells(row *exportRow) []export.Cell {
+	confirmedAt := ""
+	if row.PaymentConfirmedAt != nil {
+		confirmedAt = row.PaymentConfirmedAt.Format(time.RFC3339)
+	}
+	sellerID := ""
+	if row.SellerID != uuid.Nil {
+		sellerID = row.SellerID.String()
+	}
+
+	return []export.Cell{
+		export.Text(row.PurchaseID.String()),
+		export.Text(row.CreatedAt.Format(time.RFC3339)),
+		export.Text(string(row.Status)),
+		export.Text(row.BuyerID.String()),
+		export.Text(sellerID),
+		export.Text(row.ProductID),
+		export.Text(row.Name),
+		export.Text(row.SKU),
+		export.Number(strconv.Itoa(row.Qty)),
+		export.Number(row.Price.String()),
+		export.Number(row.LineTotal.String()),
+		export.Text(row.Currency),
+		export.Text(row.PaymentStatus),
+		export.Text(confirmedAt),
+	}
+}
@@ -0,0 +1,252 @@
+package purchase
+
+import (
+	"context"
+	"fmt"
+	"purchase-service/pkg/entities"
+	"purchase-service/pkg/pdf"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// GetInvoice renders the invoices of a purchase as a PDF, one per seller.
+// The buyer gets every seller's invoice and a seller only their own. Invoice
+// numbers are allocated the first time an invoice is requested.
+func (s *service) GetInvoice(ctx context.Context, purchaseID string) ([]byte, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	purchase, err := getPurchase(ctx, s.repo, purchaseID)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := s.loadPurchaseDetails(ctx, []*entities.Purchase{purchase})
+	if err != nil {
+		return nil, err
+	}
+
+	paymentDetails := details.paymentDetails[purchaseID]
+	if purchase.UserID.String() != userID {
+		detail := findPaymentDetail(paymentDetails, userID)
+		if detail == nil {
+			return nil, fmt.Errorf("%w: purchase does not belong to user", ErrForbidden)
+		}
+		paymentDetails = []*entities.PurchasePaymentDetail{detail}
+	}
+	if len(paymentDetails) == 0 {
+		return nil, fmt.Errorf("invoice %w: purchase has no payment details", ErrNotFound)
+	}
+
+	invoices, err := s.issueInvoices(ctx, purchaseID, paymentDetails)
+	if err != nil {
+		return nil, err
+	}
+
+	return renderInvoices(purchase, details, paymentDetails, invoices), nil
+}
+
+// issueInvoices returns the invoice of every given seller, allocating the next
+// number of the sellers that have none for this purchase yet. The purchase is
+// locked meanwhile, so concurrent requests cannot issue the same invoice twice.
+func (s *service) issueInvoices(ctx context.Context, purchaseID string, paymentDetails []*entities.PurchasePaymentDetail) (map[uuid.UUID]*entities.PurchaseInvoice, error) {
+	invoices := make(map[uuid.UUID]*entities.PurchaseInvoice, len(paymentDetails))
+	missing := func() bool {
+		for _, detail := range paymentDetails {
+			if _, ok := invoices[detail.SellerID]; !ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	existing, err := s.repo.GetInvoicesByPurchaseID(ctx, purchaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoices: %w", err)
+	}
+	for _, invoice := range existing {
+		invoices[invoice.SellerID] = invoice
+	}
+	if !missing() {
+		return invoices, nil
+	}
+
+	if err := s.repo.WithTransaction(ctx, func(tx Repository) error {
+		if _, err := tx.LockPurchaseByID(ctx, purchaseID); err != nil {
+			return fmt.Errorf("failed to lock purchase: %w", err)
+		}
+
+		// Another request may have issued them while we waited for the lock
+		existing, err := tx.GetInvoicesByPurchaseID(ctx, purchaseID)
+		if err != nil {
+			return fmt.Errorf("failed to get invoices: %w", err)
+		}
+		for _, invoice := range existing {
+			invoices[invoice.SellerID] = invoice
+		}
+
+		for _, detail := range paymentDetails {
+			if _, ok := invoices[detail.SellerID]; ok {
+				continue
+			}
+
+			sequence, err := tx.NextInvoiceSequence(ctx, detail.SellerID.String())
+			if err != nil {
+				return fmt.Errorf("failed to allocate invoice number: %w", err)
+			}
+			invoice := &entities.PurchaseInvoice{
+				PurchaseID: detail.PurchaseID,
+				SellerID:   detail.SellerID,
+				Sequence:   sequence,
+				Number:     invoiceNumber(detail.SellerID, sequence),
+			}
+			if err := tx.CreateInvoice(ctx, invoice); err != nil {
+				return fmt.Errorf("failed to create invoice: %w", err)
+			}
+			invoices[detail.SellerID] = invoice
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+// invoiceNumber formats a seller's invoice sequence, e.g. INV/0199A3F2/000042.
+// The seller part keeps numbers of different sellers apart.
+func invoiceNumber(sellerID uuid.UUID, sequence int) string {
+	return fmt.Sprintf("INV/%s/%06d", strings.ToUpper(sellerID.String()[:8]), sequence)
+}
+
+// Invoice table columns: item and SKU are left aligned at their x, the
+// numbers right aligned at theirs
+const (
+	invoiceItemX      = pdf.Margin
+	invoiceSKUX       = pdf.Margin + 215
+	invoiceQtyX       = pdf.Margin + 350
+	invoicePriceX     = pdf.Margin + 425
+	invoiceTotalX     = pdf.PageWidth - pdf.Margin
+	invoiceItemWidth  = 205
+	invoiceSKUWidth   = 95
+	invoiceLineHeight = 16
+)
+
+// renderInvoices draws one invoice per seller, each starting on a new page
+func renderInvoices(purchase *entities.Purchase, details *purchaseDetails, paymentDetails []*entities.PurchasePaymentDetail, invoices map[uuid.UUID]*entities.PurchaseInvoice) []byte {
+	purchaseID := purchase.ID.String()
+	sender := details.senders[purchaseID]
+	doc := pdf.New()
+
+	for _, detail := range paymentDetails {
+		invoice := invoices[detail.SellerID]
+		page := doc.AddPage()
+
+		y := float64(pdf.Margin + 20)
+		page.Text(pdf.Margin, y, pdf.Bold, 22, "INVOICE")
+		page.TextRight(invoiceTotalX, y, pdf.Bold, 12, invoice.Number)
+		y += 30
+
+		status := string(purchase.Status)
+		if detail.Status != purchase.Status {
+			status += " (payment to this seller: " + string(detail.Status) + ")"
+		}
+		for _, row := range [][2]string{
+			{"Invoice date", invoice.CreatedAt.Format("02 Jan 2006")},
+			{"Purchase ID", purchaseID},
+			{"Purchase date", purchase.CreatedAt.Format("02 Jan 2006 15:04 MST")},
+			{"Status", status},
+		} {
+			page.Text(pdf.Margin, y, pdf.Bold, 10, row[0])
+			page.Text(pdf.Margin+90, y, pdf.Regular, 10, row[1])
+			y += 14
+		}
+		if purchase.StatusReason != "" {
+			page.Text(pdf.Margin, y, pdf.Bold, 10, "Note")
+			page.Text(pdf.Margin+90, y, pdf.Regular, 10, pdf.Truncate(pdf.Regular, 10, purchase.StatusReason, invoiceTotalX-pdf.Margin-90))
+			y += 14
+		}
+		y += 16
+
+		// Who pays whom
+		column := pdf.PageWidth / 2
+		page.Text(pdf.Margin, y, pdf.Bold, 11, "Pay to")
+		page.Text(column, y, pdf.Bold, 11, "Billed to")
+		y += 15
+		payTo := []string{detail.BankAccountHolder, detail.BankAccountName, "Account " + detail.BankAccountNumber}
+		billedTo := []string{sender.SenderName, sender.SenderContactType + ": " + sender.SenderContactDetail}
+		if address := details.addresses[purchaseID]; address != nil {
+			street := address.AddressLine1
+			if address.AddressLine2 != "" {
+				street += ", " + address.AddressLine2
+			}
+			billedTo = append(billedTo,
+				"Ship to "+address.RecipientName+" ("+address.Phone+")",
+				street,
+				address.City+", "+address.Province+" "+address.PostalCode+", "+address.Country,
+			)
+		}
+		shipment := findShipment(details.shipments[purchaseID], detail.SellerID.String())
+		for i := 0; i < len(payTo) || i < len(billedTo); i++ {
+			if i < len(payTo) {
+				page.Text(pdf.Margin, y, pdf.Regular, 10, pdf.Truncate(pdf.Regular, 10, payTo[i], column-pdf.Margin-10))
+			}
+			if i < len(billedTo) {
+				page.Text(column, y, pdf.Regular, 10, pdf.Truncate(pdf.Regular, 10, billedTo[i], invoiceTotalX-column))
+			}
+			y += 14
+		}
+		y += 20
+
+		y = invoiceTableHeader(page, y)
+		for _, item := range details.items[purchaseID] {
+			if item.SellerID != detail.SellerID {
+				continue
+			}
+			// Leave room for the discount, shipping and total below the last line
+			if y > pdf.PageHeight-pdf.Margin-40-2*invoiceLineHeight {
+				page = doc.AddPage()
+				page.Text(pdf.Margin, pdf.Margin+12, pdf.Bold, 10, invoice.Number+" (continued)")
+				y = invoiceTableHeader(page, pdf.Margin+40)
+			}
+
+			page.Text(invoiceItemX, y, pdf.Regular, 10, pdf.Truncate(pdf.Regular, 10, item.Name, invoiceItemWidth))
+			page.Text(invoiceSKUX, y, pdf.Regular, 10, pdf.Truncate(pdf.Regular, 10, item.SKU, invoiceSKUWidth))
+			page.TextRight(invoiceQtyX, y, pdf.Regular, 10, strconv.Itoa(item.Qty))
+			page.TextRight(invoicePriceX, y, pdf.Regular, 10, item.Price.String())
+			page.TextRight(invoiceTotalX, y, pdf.Regular, 10, item.LineTotal.String())
+			y += invoiceLineHeight
+		}
+
+		page.Line(pdf.Margin, y-10, invoiceTotalX, y-10)
+		y += 6
+		if detail.Discount > 0 {
+			page.TextRight(invoicePriceX, y, pdf.Regular, 10, "Voucher "+purchase.VoucherCode)
+			page.TextRight(invoiceTotalX, y, pdf.Regular, 10, "-"+detail.Discount.String())
+			y += invoiceLineHeight
+		}
+		if shipment != nil {
+			page.TextRight(invoicePriceX, y, pdf.Regular, 10, "Shipping ("+shipment.Method+")")
+			page.TextRight(invoiceTotalX, y, pdf.Regular, 10, shipment.Cost.String())
+			y += invoiceLineHeight
+		}
+		page.TextRight(invoicePriceX, y, pdf.Bold, 11, "Total")
+		page.TextRight(invoiceTotalX, y, pdf.Bold, 11, purchase.Currency+" "+detail.TotalPrice.String())
+	}
+
+	return doc.Bytes()
+}
+
+// invoiceTableHeader draws the column titles and returns where the first row goes
+func invoiceTableHeader(page *pdf.Page, y float64) float64 {
+	page.Text(invoiceItemX, y, pdf.Bold, 10, "Item")
+	page.Text(invoiceSKUX, y, pdf.Bold, 10, "SKU")
+	page.TextRight(invoiceQtyX, y, pdf.Bold, 10, "Qty")
+	page.TextRight(invoicePriceX, y, pdf.Bold, 10, "Unit price")
+	page.TextRight(invoiceTotalX, y, pdf.Bold, 10, "Amount")
+	page.Line(pdf.Margin, y+6, invoiceTotalX, y+6)
+	return y + invoiceLineHeight + 4
+}
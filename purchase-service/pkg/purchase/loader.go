@@ -0,0 +1,212 @@
+package purchase
+
+import (
+	"context"
+	"fmt"
+	"purchase-service/api/presenter"
+	"purchase-service/pkg/entities"
+	"time"
+)
+
+// purchaseDetails holds the rows related to a set of purchases, keyed by purchase ID
+type purchaseDetails struct {
+	items          map[string][]*entities.PurchaseItem
+	senders        map[string]*entities.PurchaseSender
+	paymentDetails map[string][]*entities.PurchasePaymentDetail
+	refunds        map[string]*entities.PurchaseRefund
+	proofs         map[string][]*entities.PurchasePaymentProof
+	files          map[string]*presenter.ExternalFileResponse // Payment proof files, keyed by file ID
+	addresses      map[string]*entities.PurchaseShippingAddress
+	shipments      map[string][]*entities.PurchaseShipment
+}
+
+// loadPurchaseDetails fetches items, senders, payment details, payment proofs,
+// the latest refund request, shipping addresses and shipments for all the
+// given purchases in a constant number of queries, and the proof files from
+// the user service in batches.
+// A purchase without a sender is reported as an error rather than silently
+// dropped from the result.
+func (s *service) loadPurchaseDetails(ctx context.Context, purchases []*entities.Purchase) (*purchaseDetails, error) {
+	details := &purchaseDetails{
+		items:          make(map[string][]*entities.PurchaseItem, len(purchases)),
+		senders:        make(map[string]*entities.PurchaseSender, len(purchases)),
+		paymentDetails: make(map[string][]*entities.PurchasePaymentDetail, len(purchases)),
+		refunds:        make(map[string]*entities.PurchaseRefund),
+		proofs:         make(map[string][]*entities.PurchasePaymentProof, len(purchases)),
+		addresses:      make(map[string]*entities.PurchaseShippingAddress),
+		shipments:      make(map[string][]*entities.PurchaseShipment),
+	}
+	if len(purchases) == 0 {
+		return details, nil
+	}
+
+	purchaseIDs := make([]string, len(purchases))
+	for i, purchase := range purchases {
+		purchaseIDs[i] = purchase.ID.String()
+	}
+
+	items, err := s.repo.GetPurchaseItemsByPurchaseIDs(ctx, purchaseIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase items: %w", err)
+	}
+	for _, item := range items {
+		purchaseID := item.PurchaseID.String()
+		details.items[purchaseID] = append(details.items[purchaseID], item)
+	}
+
+	senders, err := s.repo.GetPurchaseSendersByPurchaseIDs(ctx, purchaseIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase senders: %w", err)
+	}
+	for _, sender := range senders {
+		details.senders[sender.PurchaseID.String()] = sender
+	}
+
+	paymentDetails, err := s.repo.GetPurchasePaymentDetailsByPurchaseIDs(ctx, purchaseIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase payment details: %w", err)
+	}
+	for _, detail := range paymentDetails {
+		purchaseID := detail.PurchaseID.String()
+		details.paymentDetails[purchaseID] = append(details.paymentDetails[purchaseID], detail)
+	}
+
+	refunds, err := s.repo.GetLatestRefundsByPurchaseIDs(ctx, purchaseIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase refunds: %w", err)
+	}
+	for _, refund := range refunds {
+		details.refunds[refund.PurchaseID.String()] = refund
+	}
+
+	proofs, err := s.repo.GetPaymentProofsByPurchaseIDs(ctx, purchaseIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment proofs: %w", err)
+	}
+	fileIDs := make([]string, 0, len(proofs))
+	for _, proof := range proofs {
+		purchaseID := proof.PurchaseID.String()
+		details.proofs[purchaseID] = append(details.proofs[purchaseID], proof)
+		fileIDs = append(fileIDs, proof.FileID.String())
+	}
+
+	details.files = s.getFiles(ctx, fileIDs)
+
+	addresses, err := s.repo.GetShippingAddressesByPurchaseIDs(ctx, purchaseIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipping addresses: %w", err)
+	}
+	for _, address := range addresses {
+		details.addresses[address.PurchaseID.String()] = address
+	}
+
+	shipments, err := s.repo.GetShipmentsByPurchaseIDs(ctx, purchaseIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shipments: %w", err)
+	}
+	for _, shipment := range shipments {
+		purchaseID := shipment.PurchaseID.String()
+		details.shipments[purchaseID] = append(details.shipments[purchaseID], shipment)
+	}
+
+	for _, purchaseID := range purchaseIDs {
+		if _, ok := details.senders[purchaseID]; !ok {
+			return nil, fmt.Errorf("failed to get purchase sender: purchase %s has no sender", purchaseID)
+		}
+	}
+
+	return details, nil
+}
+
+// getPurchaseResponse builds the buyer's view of a purchase from its loaded details
+func getPurchaseResponse(purchase *entities.Purchase, details *purchaseDetails) presenter.GetPurchaseResponse {
+	purchaseID := purchase.ID.String()
+	sender := details.senders[purchaseID]
+
+	return presenter.GetPurchaseResponse{
+		PurchaseID:      purchaseID,
+		UserID:          purchase.UserID.String(),
+		Status:          string(purchase.Status),
+		StatusHistory:   statusTimestamps(purchase),
+		StatusReason:    purchase.StatusReason,
+		PaymentProofIds: paymentProofIDs(details.proofs[purchaseID]),
+		PaymentProofs:   paymentProofFiles(details.proofs[purchaseID], details.files),
+		PurchasedItems:  purchaseItemResponses(details.items[purchaseID]),
+		TotalPrice:      purchase.TotalPrice,
+		Discount:        purchase.Discount,
+		VoucherCode:     purchase.VoucherCode,
+		ShippingCost:    shippingCost(details.shipments[purchaseID]),
+		Currency:        purchase.Currency,
+		PaymentDetails:  paymentDetailResponses(details.paymentDetails[purchaseID]),
+		ShippingAddress: shippingAddressResponse(details.addresses[purchaseID]),
+		Shipments:       shipmentResponses(details.shipments[purchaseID]),
+		Refund:          refundResponse(details.refunds[purchaseID]),
+		SenderInfo:      senderInfo(sender),
+		CreatedAt:       purchase.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       purchase.UpdatedAt.Format(time.RFC3339),
+	}
+}
+
+// paymentProofIDs lists the file IDs of the proofs that were not rejected
+func paymentProofIDs(proofs []*entities.PurchasePaymentProof) []string {
+	paymentProofIds := []string{}
+	for _, proof := range proofs {
+		if proof.Status != entities.PaymentProofStatusRejected {
+			paymentProofIds = append(paymentProofIds, proof.FileID.String())
+		}
+	}
+	return paymentProofIds
+}
+
+// paymentProofFiles converts stored payment proofs to their API representation
+func paymentProofFiles(proofs []*entities.PurchasePaymentProof, files map[string]*presenter.ExternalFileResponse) []presenter.PaymentProofFile {
+	responses := make([]presenter.PaymentProofFile, 0, len(proofs))
+	for _, proof := range proofs {
+		response := presenter.PaymentProofFile{
+			FileID:     proof.FileID.String(),
+			Status:     string(proof.Status),
+			UploadedAt: proof.CreatedAt.Format(time.RFC3339),
+		}
+		if file, ok := files[response.FileID]; ok {
+			response.FileURI = file.FileURI
+			response.FileThumbnailURI = file.FileThumbnailURI
+		}
+		if proof.SellerID != nil {
+			response.SellerID = proof.SellerID.String()
+		}
+		if proof.ReviewedAt != nil {
+			reviewedAt := proof.ReviewedAt.Format(time.RFC3339)
+			response.ReviewedAt = &reviewedAt
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+// senderInfo converts a stored sender to its API representation
+func senderInfo(sender *entities.PurchaseSender) presenter.SenderInfo {
+	return presenter.SenderInfo{
+		SenderName:          sender.SenderName,
+		SenderContactType:   sender.SenderContactType,
+		SenderContactDetail: sender.SenderContactDetail,
+	}
+}
+
+// refundResponse converts a stored refund request to its API representation
+func refundResponse(refund *entities.PurchaseRefund) *presenter.RefundResponse {
+	if refund == nil {
+		return nil
+	}
+
+	response := &presenter.RefundResponse{
+		Status:           string(refund.Status),
+		Reason:           refund.Reason,
+		ResolutionReason: refund.ResolutionReason,
+		RequestedAt:      refund.CreatedAt.Format(time.RFC3339),
+	}
+	if refund.ResolvedAt != nil {
+		resolvedAt := refund.ResolvedAt.Format(time.RFC3339)
+		response.ResolvedAt = &resolvedAt
+	}
+	return response
+}
@@ -0,0 +1,46 @@
+package purchase
+
+import (
+	"context"
+	"fmt"
+	"purchase-service/api/presenter"
+	"time"
+)
+
+func (s *service) ListNotifications(ctx context.Context, page, limit int) (*presenter.ListNotificationsResponse, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	// Set default pagination values
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	notifications, total, err := s.repo.GetNotificationsByUserID(ctx, userID, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+
+	responses := make([]presenter.NotificationResponse, 0, len(notifications))
+	for _, notification := range notifications {
+		responses = append(responses, presenter.NotificationResponse{
+			NotificationID: notification.ID.String(),
+			PurchaseID:     notification.PurchaseID.String(),
+			Type:           string(notification.Type),
+			Message:        notification.Message,
+			CreatedAt:      notification.CreatedAt.Format(time.RFC3339),
+		})
+	}
+
+	return &presenter.ListNotificationsResponse{
+		Notifications: responses,
+		Total:         int(total),
+		Page:          page,
+		Limit:         limit,
+	}, nil
+}
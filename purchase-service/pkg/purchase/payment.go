@@ -0,0 +1,84 @@
+package purchase
+
+import (
+	"context"
+	"fmt"
+	"purchase-service/pkg/entities"
+)
+
+// A purchase spanning several sellers is paid with one transfer per seller.
+// Each payment detail carries its own status, and the purchase status follows
+// from all of them: it is confirmed once every seller confirmed, waits for
+// review once every seller has a proof, and waits for payment otherwise.
+// Payments to sellers that were cancelled while others were already
+// confirmed no longer count.
+
+// lockPayablePurchase locks a purchase whose payments may still change, that
+// is one waiting for payment or for proof review
+func lockPayablePurchase(ctx context.Context, repo Repository, purchaseID string, to entities.PurchaseStatus) (*entities.Purchase, error) {
+	purchase, err := repo.LockPurchaseByID(ctx, purchaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to lock purchase: %w", err)
+	}
+
+	if purchase.Status != entities.PurchaseStatusPendingPayment && purchase.Status != entities.PurchaseStatusProofUploaded {
+		return nil, &InvalidTransitionError{From: purchase.Status, To: to}
+	}
+	return purchase, nil
+}
+
+// getSellerPaymentDetail loads the payment detail of one seller of a purchase
+func getSellerPaymentDetail(ctx context.Context, repo Repository, purchaseID, sellerID string) (*entities.PurchasePaymentDetail, error) {
+	details, err := repo.GetPurchasePaymentDetailsByPurchaseID(ctx, purchaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase payment details: %w", err)
+	}
+
+	if detail := findPaymentDetail(details, sellerID); detail != nil {
+		return detail, nil
+	}
+	return nil, fmt.Errorf("payment detail of seller %s %w", sellerID, ErrNotFound)
+}
+
+func findPaymentDetail(details []*entities.PurchasePaymentDetail, sellerID string) *entities.PurchasePaymentDetail {
+	for _, detail := range details {
+		if detail.SellerID.String() == sellerID {
+			return detail
+		}
+	}
+	return nil
+}
+
+// combinedPaymentStatus is the purchase status the payments to its sellers
+// add up to; cancelled payments leave it alone
+func combinedPaymentStatus(details []*entities.PurchasePaymentDetail) entities.PurchaseStatus {
+	status := entities.PurchaseStatusConfirmed
+	for _, detail := range details {
+		switch detail.Status {
+		case entities.PurchaseStatusPendingPayment:
+			return entities.PurchaseStatusPendingPayment
+		case entities.PurchaseStatusProofUploaded:
+			status = entities.PurchaseStatusProofUploaded
+		}
+	}
+	return status
+}
+
+// syncPurchaseStatus moves the purchase to the status its sellers' payments
+// add up to, if that changed. It must run under the purchase lock after the
+// payment details were updated; fields are applied only on a status change.
+func (s *service) syncPurchaseStatus(ctx context.Context, repo Repository, purchase *entities.Purchase, fields map[string]interface{}) error {
+	details, err := repo.GetPurchasePaymentDetailsByPurchaseID(ctx, purchase.ID.String())
+	if err != nil {
+		return fmt.Errorf("failed to get purchase payment details: %w", err)
+	}
+
+	status := combinedPaymentStatus(details)
+	if status == purchase.Status {
+		return nil
+	}
+	if err := s.transitionStatus(ctx, repo, purchase, status, fields); err != nil {
+		return fmt.Errorf("failed to update purchase status: %w", err)
+	}
+	return nil
+}
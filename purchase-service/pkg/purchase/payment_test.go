@@ -0,0 +1,57 @@
+package purchase
+
+import (
+	"purchase-service/pkg/entities"
+	"testing"
+)
+
+func TestCombinedPaymentStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		statuses []entities.PurchaseStatus
+		want     entities.PurchaseStatus
+	}{
+		{
+			name:     "single seller waiting for payment",
+			statuses: []entities.PurchaseStatus{entities.PurchaseStatusPendingPayment},
+			want:     entities.PurchaseStatusPendingPayment,
+		},
+		{
+			name:     "one seller still unpaid holds the purchase back",
+			statuses: []entities.PurchaseStatus{entities.PurchaseStatusConfirmed, entities.PurchaseStatusProofUploaded, entities.PurchaseStatusPendingPayment},
+			want:     entities.PurchaseStatusPendingPayment,
+		},
+		{
+			name:     "proofs uploaded to every unconfirmed seller",
+			statuses: []entities.PurchaseStatus{entities.PurchaseStatusConfirmed, entities.PurchaseStatusProofUploaded},
+			want:     entities.PurchaseStatusProofUploaded,
+		},
+		{
+			name:     "every seller confirmed",
+			statuses: []entities.PurchaseStatus{entities.PurchaseStatusConfirmed, entities.PurchaseStatusConfirmed},
+			want:     entities.PurchaseStatusConfirmed,
+		},
+		{
+			name:     "cancelled payments are left out",
+			statuses: []entities.PurchaseStatus{entities.PurchaseStatusCancelled, entities.PurchaseStatusConfirmed},
+			want:     entities.PurchaseStatusConfirmed,
+		},
+		{
+			name:     "cancelled payments do not confirm an unpaid purchase",
+			statuses: []entities.PurchaseStatus{entities.PurchaseStatusCancelled, entities.PurchaseStatusPendingPayment},
+			want:     entities.PurchaseStatusPendingPayment,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			details := make([]*entities.PurchasePaymentDetail, 0, len(tt.statuses))
+			for _, status := range tt.statuses {
+				details = append(details, &entities.PurchasePaymentDetail{Status: status})
+			}
+			if got := combinedPaymentStatus(details); got != tt.want {
+				t.Errorf("combinedPaymentStatus(%v) = %s, want %s", tt.statuses, got, tt.want)
+			}
+		})
+	}
+}
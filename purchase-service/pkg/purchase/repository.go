@@ -2,20 +2,158 @@ package purchase
 
 import (
 	"context"
+	"fmt"
+	"purchase-service/pkg/dtos"
 	"purchase-service/pkg/entities"
 
+	"time"
+
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
+	// WithTransaction runs fn against a repository bound to a single database
+	// transaction; any error returned by fn rolls back every write made through it
+	WithTransaction(ctx context.Context, fn func(tx Repository) error) error
 	CreatePurchase(ctx context.Context, purchase *entities.Purchase) error
 	CreatePurchaseItems(ctx context.Context, items []*entities.PurchaseItem) error
 	CreatePurchaseSender(ctx context.Context, sender *entities.PurchaseSender) error
+	CreatePurchasePaymentDetails(ctx context.Context, details []*entities.PurchasePaymentDetail) error
 	GetPurchaseByID(ctx context.Context, id string) (*entities.Purchase, error)
+	// GetExistingPurchaseIDs returns those of the given IDs that belong to a purchase
+	GetExistingPurchaseIDs(ctx context.Context, ids []string) ([]string, error)
+	// LockPurchaseByID reads a purchase with SELECT ... FOR UPDATE so changes
+	// that depend on its current state are serialized. It must be called
+	// inside WithTransaction.
+	LockPurchaseByID(ctx context.Context, id string) (*entities.Purchase, error)
 	GetPurchaseItemsByPurchaseID(ctx context.Context, purchaseID string) ([]*entities.PurchaseItem, error)
 	GetPurchaseSenderByPurchaseID(ctx context.Context, purchaseID string) (*entities.PurchaseSender, error)
-	UpdatePurchasePaymentProof(ctx context.Context, purchaseID string, paymentProofIds string) error
-	GetPurchasesByUserID(ctx context.Context, userID string, page, limit int) ([]*entities.Purchase, int64, error)
+	GetPurchasePaymentDetailsByPurchaseID(ctx context.Context, purchaseID string) ([]*entities.PurchasePaymentDetail, error)
+	// Batch variants load the related rows of a whole page of purchases in one query each
+	GetPurchaseItemsByPurchaseIDs(ctx context.Context, purchaseIDs []string) ([]*entities.PurchaseItem, error)
+	GetPurchaseSendersByPurchaseIDs(ctx context.Context, purchaseIDs []string) ([]*entities.PurchaseSender, error)
+	GetPurchasePaymentDetailsByPurchaseIDs(ctx context.Context, purchaseIDs []string) ([]*entities.PurchasePaymentDetail, error)
+	UpdatePurchaseStatus(ctx context.Context, purchaseID string, from, to entities.PurchaseStatus, fields map[string]interface{}) error
+	// UpdatePaymentDetailStatus moves the payment to one seller of a purchase to
+	// a new status, only if it is still in the expected status
+	UpdatePaymentDetailStatus(ctx context.Context, purchaseID, sellerID string, from, to entities.PurchaseStatus, fields map[string]interface{}) error
+	// UpdatePaymentDetailRefundStatus records one seller's decision on the
+	// refund request of a purchase, only if the decision is still the expected one
+	UpdatePaymentDetailRefundStatus(ctx context.Context, purchaseID, sellerID string, from, to entities.RefundStatus, fields map[string]interface{}) error
+	// GetPurchasesByUserID returns up to limit purchases of the buyer matching the
+	// filter, newest first, starting after the cursor when one is given
+	GetPurchasesByUserID(ctx context.Context, userID string, filter dtos.ListPurchasesFilter, after *cursor, limit int) ([]*entities.Purchase, error)
+	GetPurchasesBySellerID(ctx context.Context, sellerID string, status entities.PurchaseStatus, page, limit int) ([]*entities.Purchase, int64, error)
+	CreateNotification(ctx context.Context, notification *entities.PurchaseNotification) error
+	GetNotificationsByUserID(ctx context.Context, userID string, page, limit int) ([]*entities.PurchaseNotification, int64, error)
+	CreatePaymentProofs(ctx context.Context, proofs []*entities.PurchasePaymentProof) error
+	// UpdatePaymentProofStatus moves the proofs made out to a seller, including
+	// those covering the whole purchase, from one review status to another
+	UpdatePaymentProofStatus(ctx context.Context, purchaseID, sellerID string, from, to entities.PaymentProofStatus, reviewedAt *time.Time) error
+	// GetPaymentProofsByPurchaseIDs returns the proofs of the given purchases
+	// that were not replaced by a later upload, oldest first
+	GetPaymentProofsByPurchaseIDs(ctx context.Context, purchaseIDs []string) ([]*entities.PurchasePaymentProof, error)
+	CreateRefund(ctx context.Context, refund *entities.PurchaseRefund) error
+	UpdateRefund(ctx context.Context, refund *entities.PurchaseRefund) error
+	GetLatestRefundByPurchaseID(ctx context.Context, purchaseID string) (*entities.PurchaseRefund, error)
+	// GetLatestRefundsByPurchaseIDs returns the most recent refund request of
+	// each purchase that has one
+	GetLatestRefundsByPurchaseIDs(ctx context.Context, purchaseIDs []string) ([]*entities.PurchaseRefund, error)
+	// StreamExportRows calls fn for every purchase item matching the filter,
+	// oldest purchase first, reading rows from the database one at a time.
+	// Exactly one of buyerID and sellerID selects whose purchases are read.
+	StreamExportRows(ctx context.Context, buyerID, sellerID string, filter dtos.ExportPurchasesFilter, fn func(row *exportRow) error) error
+	// Seller analytics aggregate the seller's sales: items in purchases created
+	// in the query's range and currency whose payment the seller confirmed,
+	// leaving out refunded purchases
+	GetSellerRevenueByPeriod(ctx context.Context, query salesQuery) ([]*revenuePeriod, error)
+	GetSellerTopProducts(ctx context.Context, query salesQuery, limit int) ([]*productSales, error)
+	GetSellerTopCategories(ctx context.Context, query salesQuery, limit int) ([]*categorySales, error)
+	// GetSellerConversion counts the purchases with the seller's items created in
+	// the query's range and currency, and how many of them the seller confirmed
+	GetSellerConversion(ctx context.Context, query salesQuery) (*conversionCounts, error)
+	GetInvoicesByPurchaseID(ctx context.Context, purchaseID string) ([]*entities.PurchaseInvoice, error)
+	CreateInvoice(ctx context.Context, invoice *entities.PurchaseInvoice) error
+	// NextInvoiceSequence atomically allocates the seller's next invoice
+	// sequence, starting at 1. The allocation is rolled back with the
+	// surrounding transaction, so issued invoices have no gaps.
+	NextInvoiceSequence(ctx context.Context, sellerID string) (int, error)
+	// GetPurchasesCreatedBefore returns up to limit purchases in the given
+	// status that were created before the cutoff and that have no active
+	// dispute, ordered by ID and starting after afterID.
+	GetPurchasesCreatedBefore(ctx context.Context, status entities.PurchaseStatus, before time.Time, afterID uuid.UUID, limit int) ([]*entities.Purchase, error)
+	// LockPurchaseCreatedBefore locks the purchase if it still matches
+	// GetPurchasesCreatedBefore. It returns gorm.ErrRecordNotFound when it no
+	// longer does or another transaction holds the row, and must be called
+	// inside WithTransaction.
+	LockPurchaseCreatedBefore(ctx context.Context, id string, status entities.PurchaseStatus, before time.Time) (*entities.Purchase, error)
+	GetCartByUserID(ctx context.Context, userID string) (*entities.Cart, error)
+	// LockCartByUserID reads the user's cart with SELECT ... FOR UPDATE,
+	// creating an empty one first if there is none. It must be called inside
+	// WithTransaction.
+	LockCartByUserID(ctx context.Context, userID string) (*entities.Cart, error)
+	GetCartItems(ctx context.Context, cartID string) ([]*entities.CartItem, error)
+	// SaveCartItem inserts a new cart item or updates an existing one
+	SaveCartItem(ctx context.Context, item *entities.CartItem) error
+	DeleteCartItem(ctx context.Context, cartID, productID string) error
+	DeleteCartItems(ctx context.Context, cartID string) error
+	// BumpCartVersion records that the items of a cart changed
+	BumpCartVersion(ctx context.Context, cartID string) error
+	// CreateVoucher inserts the voucher unless its code is taken; it reports
+	// whether the row was inserted
+	CreateVoucher(ctx context.Context, voucher *entities.Voucher) (bool, error)
+	GetVoucherByCode(ctx context.Context, code string) (*entities.Voucher, error)
+	GetVoucherByID(ctx context.Context, id string) (*entities.Voucher, error)
+	// LockVoucherByID reads a voucher with SELECT ... FOR UPDATE so redemptions
+	// are checked against its limits one at a time. It must be called inside
+	// WithTransaction.
+	LockVoucherByID(ctx context.Context, id string) (*entities.Voucher, error)
+	GetVouchersBySellerID(ctx context.Context, sellerID string, page, limit int) ([]*entities.Voucher, int64, error)
+	DisableVoucher(ctx context.Context, id string, disabledAt time.Time) error
+	// CountVoucherRedemptions counts the user's redemptions of a voucher that
+	// were not released
+	CountVoucherRedemptions(ctx context.Context, voucherID, userID string) (int64, error)
+	CreateVoucherRedemption(ctx context.Context, redemption *entities.VoucherRedemption) error
+	IncrementVoucherUsage(ctx context.Context, voucherID string) error
+	// ReleaseVoucherRedemption gives back the voucher use of a purchase that
+	// was cancelled or expired. Purchases without a voucher are left alone.
+	ReleaseVoucherRedemption(ctx context.Context, purchaseID string) error
+	CreatePurchaseShippingAddress(ctx context.Context, address *entities.PurchaseShippingAddress) error
+	CreatePurchaseShipments(ctx context.Context, shipments []*entities.PurchaseShipment) error
+	GetShippingAddressesByPurchaseIDs(ctx context.Context, purchaseIDs []string) ([]*entities.PurchaseShippingAddress, error)
+	GetShipmentsByPurchaseIDs(ctx context.Context, purchaseIDs []string) ([]*entities.PurchaseShipment, error)
+	// LockShipmentsByPurchaseID reads the shipments of a purchase with
+	// SELECT ... FOR UPDATE. It must be called inside WithTransaction.
+	LockShipmentsByPurchaseID(ctx context.Context, purchaseID string) ([]*entities.PurchaseShipment, error)
+	// UpdateShipmentStatus moves the shipment of one seller of a purchase to a
+	// new status, only if it is still in the expected status
+	UpdateShipmentStatus(ctx context.Context, purchaseID, sellerID string, from, to entities.ShipmentStatus, fields map[string]interface{}) error
+	// CreateReview inserts the review unless the product was already reviewed
+	// for the purchase; it reports whether the row was inserted
+	CreateReview(ctx context.Context, review *entities.ProductReview) (bool, error)
+	CreateReviewImages(ctx context.Context, images []*entities.ProductReviewImage) error
+	GetReviewsByProductID(ctx context.Context, productID string, page, limit int) ([]*entities.ProductReview, int64, error)
+	GetReviewsBySellerID(ctx context.Context, sellerID string, page, limit int) ([]*entities.ProductReview, int64, error)
+	GetReviewImagesByReviewIDs(ctx context.Context, reviewIDs []string) ([]*entities.ProductReviewImage, error)
+	// AddSellerRating adds one review's stars to the seller's running total,
+	// creating the seller's row on their first review
+	AddSellerRating(ctx context.Context, sellerID string, rating int) error
+	GetSellerRating(ctx context.Context, sellerID string) (*entities.SellerRating, error)
+	CreateDispute(ctx context.Context, dispute *entities.PurchaseDispute) error
+	GetDisputeByID(ctx context.Context, id string) (*entities.PurchaseDispute, error)
+	GetLatestDisputeByPurchaseID(ctx context.Context, purchaseID string) (*entities.PurchaseDispute, error)
+	// GetDisputes returns a page of all disputes, newest first, optionally only
+	// those in the given status, and how many there are in total
+	GetDisputes(ctx context.Context, status entities.DisputeStatus, page, limit int) ([]*entities.PurchaseDispute, int64, error)
+	// UpdateDisputeStatus moves a dispute to a new status, only if it is still
+	// in the expected status
+	UpdateDisputeStatus(ctx context.Context, id string, from, to entities.DisputeStatus, fields map[string]interface{}) error
+	CreateDisputeMessage(ctx context.Context, message *entities.PurchaseDisputeMessage) error
+	CreateDisputeEvidence(ctx context.Context, evidence []*entities.PurchaseDisputeEvidence) error
+	GetDisputeMessagesByDisputeID(ctx context.Context, disputeID string) ([]*entities.PurchaseDisputeMessage, error)
+	GetDisputeEvidenceByDisputeID(ctx context.Context, disputeID string) ([]*entities.PurchaseDisputeEvidence, error)
 }
 
 type GormRepository struct {
@@ -26,6 +164,12 @@ func NewGormRepository(db *gorm.DB) *GormRepository {
 	return &GormRepository{db: db}
 }
 
+func (r *GormRepository) WithTransaction(ctx context.Context, fn func(tx Repository) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&GormRepository{db: tx})
+	})
+}
+
 func (r *GormRepository) CreatePurchase(ctx context.Context, purchase *entities.Purchase) error {
 	return r.db.WithContext(ctx).Create(purchase).Error
 }
@@ -38,6 +182,10 @@ func (r *GormRepository) CreatePurchaseSender(ctx context.Context, sender *entit
 	return r.db.WithContext(ctx).Create(sender).Error
 }
 
+func (r *GormRepository) CreatePurchasePaymentDetails(ctx context.Context, details []*entities.PurchasePaymentDetail) error {
+	return r.db.WithContext(ctx).Create(&details).Error
+}
+
 func (r *GormRepository) GetPurchaseByID(ctx context.Context, id string) (*entities.Purchase, error) {
 	var purchase entities.Purchase
 	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&purchase).Error; err != nil {
@@ -46,6 +194,30 @@ func (r *GormRepository) GetPurchaseByID(ctx context.Context, id string) (*entit
 	return &purchase, nil
 }
 
+func (r *GormRepository) GetExistingPurchaseIDs(ctx context.Context, ids []string) ([]string, error) {
+	existing := []string{}
+	if len(ids) == 0 {
+		return existing, nil
+	}
+	if err := r.db.WithContext(ctx).Model(&entities.Purchase{}).
+		Where("id IN ?", ids).
+		Pluck("id", &existing).Error; err != nil {
+		return nil, err
+	}
+	return existing, nil
+}
+
+func (r *GormRepository) LockPurchaseByID(ctx context.Context, id string) (*entities.Purchase, error) {
+	var purchase entities.Purchase
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&purchase).Error; err != nil {
+		return nil, err
+	}
+	return &purchase, nil
+}
+
 func (r *GormRepository) GetPurchaseItemsByPurchaseID(ctx context.Context, purchaseID string) ([]*entities.PurchaseItem, error) {
 	var items []*entities.PurchaseItem
 	if err := r.db.WithContext(ctx).Where("purchase_id = ?", purchaseID).Find(&items).Error; err != nil {
@@ -62,25 +234,202 @@ func (r *GormRepository) GetPurchaseSenderByPurchaseID(ctx context.Context, purc
 	return &sender, nil
 }
 
-func (r *GormRepository) UpdatePurchasePaymentProof(ctx context.Context, purchaseID string, paymentProofIds string) error {
-	return r.db.WithContext(ctx).Model(&entities.Purchase{}).
-		Where("id = ?", purchaseID).
-		Update("payment_proof_ids", paymentProofIds).Error
+func (r *GormRepository) GetPurchasePaymentDetailsByPurchaseID(ctx context.Context, purchaseID string) ([]*entities.PurchasePaymentDetail, error) {
+	var details []*entities.PurchasePaymentDetail
+	if err := r.db.WithContext(ctx).
+		Where("purchase_id = ?", purchaseID).
+		Order("bank_account_name ASC").
+		Find(&details).Error; err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+func (r *GormRepository) GetPurchaseItemsByPurchaseIDs(ctx context.Context, purchaseIDs []string) ([]*entities.PurchaseItem, error) {
+	var items []*entities.PurchaseItem
+	if len(purchaseIDs) == 0 {
+		return items, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("purchase_id IN ?", purchaseIDs).
+		Order("created_at ASC, id ASC").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *GormRepository) GetPurchaseSendersByPurchaseIDs(ctx context.Context, purchaseIDs []string) ([]*entities.PurchaseSender, error) {
+	var senders []*entities.PurchaseSender
+	if len(purchaseIDs) == 0 {
+		return senders, nil
+	}
+	if err := r.db.WithContext(ctx).Where("purchase_id IN ?", purchaseIDs).Find(&senders).Error; err != nil {
+		return nil, err
+	}
+	return senders, nil
+}
+
+func (r *GormRepository) GetPurchasePaymentDetailsByPurchaseIDs(ctx context.Context, purchaseIDs []string) ([]*entities.PurchasePaymentDetail, error) {
+	var details []*entities.PurchasePaymentDetail
+	if len(purchaseIDs) == 0 {
+		return details, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("purchase_id IN ?", purchaseIDs).
+		Order("bank_account_name ASC").
+		Find(&details).Error; err != nil {
+		return nil, err
+	}
+	return details, nil
+}
+
+// UpdatePurchaseStatus moves a purchase to a new status together with any extra
+// column updates, only if the purchase is still in the expected status
+func (r *GormRepository) UpdatePurchaseStatus(ctx context.Context, purchaseID string, from, to entities.PurchaseStatus, fields map[string]interface{}) error {
+	updates := map[string]interface{}{"status": to}
+	for column, value := range fields {
+		updates[column] = value
+	}
+
+	result := r.db.WithContext(ctx).Model(&entities.Purchase{}).
+		Where("id = ? AND status = ?", purchaseID, from).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return &StatusConflictError{PurchaseID: purchaseID, Expected: from}
+	}
+	return nil
+}
+
+func (r *GormRepository) UpdatePaymentDetailStatus(ctx context.Context, purchaseID, sellerID string, from, to entities.PurchaseStatus, fields map[string]interface{}) error {
+	updates := map[string]interface{}{"status": to}
+	for column, value := range fields {
+		updates[column] = value
+	}
+
+	result := r.db.WithContext(ctx).Model(&entities.PurchasePaymentDetail{}).
+		Where("purchase_id = ? AND seller_id = ? AND status = ?", purchaseID, sellerID, from).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return &StatusConflictError{PurchaseID: purchaseID, Expected: from}
+	}
+	return nil
+}
+
+func (r *GormRepository) UpdatePaymentDetailRefundStatus(ctx context.Context, purchaseID, sellerID string, from, to entities.RefundStatus, fields map[string]interface{}) error {
+	updates := map[string]interface{}{"refund_status": to}
+	for column, value := range fields {
+		updates[column] = value
+	}
+
+	result := r.db.WithContext(ctx).Model(&entities.PurchasePaymentDetail{}).
+		Where("purchase_id = ? AND seller_id = ? AND refund_status = ?", purchaseID, sellerID, from).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: refund decision of seller %s changed concurrently", ErrConflict, sellerID)
+	}
+	return nil
+}
+
+func (r *GormRepository) GetPurchasesByUserID(ctx context.Context, userID string, filter dtos.ListPurchasesFilter, after *cursor, limit int) ([]*entities.Purchase, error) {
+	var purchases []*entities.Purchase
+
+	query := r.db.WithContext(ctx).Model(&entities.Purchase{}).Where("user_id = ?", userID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if filter.MinTotal != nil {
+		query = query.Where("total_price >= ?", *filter.MinTotal)
+	}
+	if filter.MaxTotal != nil {
+		query = query.Where("total_price <= ?", *filter.MaxTotal)
+	}
+	if filter.SellerID != "" {
+		query = query.Where("id IN (?)", r.db.Model(&entities.PurchaseItem{}).Select("purchase_id").Where("seller_id = ?", filter.SellerID))
+	}
+	if filter.ProductID != "" {
+		query = query.Where("id IN (?)", r.db.Model(&entities.PurchaseItem{}).Select("purchase_id").Where("product_id = ?", filter.ProductID))
+	}
+
+	// Keyset pagination: continue strictly after the last row of the previous page
+	if after != nil {
+		query = query.Where("(created_at, id) < (?, ?)", after.CreatedAt, after.ID)
+	}
+
+	if err := query.
+		Order("created_at DESC, id DESC").
+		Limit(limit).
+		Find(&purchases).Error; err != nil {
+		return nil, err
+	}
+
+	return purchases, nil
+}
+
+// purchasesCreatedBefore scopes a query to the purchases in the given status
+// created before the cutoff
+func purchasesCreatedBefore(db *gorm.DB, status entities.PurchaseStatus, before time.Time) *gorm.DB {
+	return db.Where("status = ? AND created_at < ?", status, before).
+		// A disputed payment waits for the dispute to be resolved
+		Where("NOT EXISTS (SELECT 1 FROM purchase_disputes pd WHERE pd.purchase_id = purchases.id AND pd.status <> ?)",
+			entities.DisputeStatusResolved)
+}
+
+func (r *GormRepository) GetPurchasesCreatedBefore(ctx context.Context, status entities.PurchaseStatus, before time.Time, afterID uuid.UUID, limit int) ([]*entities.Purchase, error) {
+	var purchases []*entities.Purchase
+	if err := purchasesCreatedBefore(r.db.WithContext(ctx), status, before).
+		Where("id > ?", afterID).
+		Order("id ASC").
+		Limit(limit).
+		Find(&purchases).Error; err != nil {
+		return nil, err
+	}
+	return purchases, nil
+}
+
+func (r *GormRepository) LockPurchaseCreatedBefore(ctx context.Context, id string, status entities.PurchaseStatus, before time.Time) (*entities.Purchase, error) {
+	var purchase entities.Purchase
+	if err := purchasesCreatedBefore(r.db.WithContext(ctx), status, before).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("id = ?", id).
+		First(&purchase).Error; err != nil {
+		return nil, err
+	}
+	return &purchase, nil
 }
 
-func (r *GormRepository) GetPurchasesByUserID(ctx context.Context, userID string, page, limit int) ([]*entities.Purchase, int64, error) {
+// GetPurchasesBySellerID returns purchases containing at least one item sold by the seller
+func (r *GormRepository) GetPurchasesBySellerID(ctx context.Context, sellerID string, status entities.PurchaseStatus, page, limit int) ([]*entities.Purchase, int64, error) {
 	var purchases []*entities.Purchase
 	var total int64
 
-	// Get total count
-	if err := r.db.WithContext(ctx).Model(&entities.Purchase{}).Where("user_id = ?", userID).Count(&total).Error; err != nil {
+	sellerItems := r.db.Model(&entities.PurchaseItem{}).Select("purchase_id").Where("seller_id = ?", sellerID)
+	query := r.db.WithContext(ctx).Model(&entities.Purchase{}).Where("id IN (?)", sellerItems)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	// Get paginated results
 	offset := (page - 1) * limit
-	if err := r.db.WithContext(ctx).
-		Where("user_id = ?", userID).
+	if err := query.
 		Order("created_at DESC").
 		Offset(offset).
 		Limit(limit).
@@ -90,3 +439,630 @@ func (r *GormRepository) GetPurchasesByUserID(ctx context.Context, userID string
 
 	return purchases, total, nil
 }
+
+func (r *GormRepository) CreateNotification(ctx context.Context, notification *entities.PurchaseNotification) error {
+	return r.db.WithContext(ctx).Create(notification).Error
+}
+
+func (r *GormRepository) GetNotificationsByUserID(ctx context.Context, userID string, page, limit int) ([]*entities.PurchaseNotification, int64, error) {
+	var notifications []*entities.PurchaseNotification
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.PurchaseNotification{}).Where("user_id = ?", userID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&notifications).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return notifications, total, nil
+}
+
+func (r *GormRepository) CreatePaymentProofs(ctx context.Context, proofs []*entities.PurchasePaymentProof) error {
+	if len(proofs) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(proofs).Error
+}
+
+func (r *GormRepository) UpdatePaymentProofStatus(ctx context.Context, purchaseID, sellerID string, from, to entities.PaymentProofStatus, reviewedAt *time.Time) error {
+	return r.db.WithContext(ctx).Model(&entities.PurchasePaymentProof{}).
+		Where("purchase_id = ? AND (seller_id = ? OR seller_id IS NULL) AND status = ?", purchaseID, sellerID, from).
+		Updates(map[string]interface{}{"status": to, "reviewed_at": reviewedAt}).Error
+}
+
+func (r *GormRepository) GetPaymentProofsByPurchaseIDs(ctx context.Context, purchaseIDs []string) ([]*entities.PurchasePaymentProof, error) {
+	var proofs []*entities.PurchasePaymentProof
+	if len(purchaseIDs) == 0 {
+		return proofs, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("purchase_id IN ? AND status <> ?", purchaseIDs, entities.PaymentProofStatusReplaced).
+		Order("created_at ASC, id ASC").
+		Find(&proofs).Error; err != nil {
+		return nil, err
+	}
+	return proofs, nil
+}
+
+func (r *GormRepository) CreateRefund(ctx context.Context, refund *entities.PurchaseRefund) error {
+	return r.db.WithContext(ctx).Create(refund).Error
+}
+
+func (r *GormRepository) UpdateRefund(ctx context.Context, refund *entities.PurchaseRefund) error {
+	return r.db.WithContext(ctx).Save(refund).Error
+}
+
+func (r *GormRepository) GetLatestRefundByPurchaseID(ctx context.Context, purchaseID string) (*entities.PurchaseRefund, error) {
+	var refund entities.PurchaseRefund
+	if err := r.db.WithContext(ctx).
+		Where("purchase_id = ?", purchaseID).
+		Order("created_at DESC, id DESC").
+		First(&refund).Error; err != nil {
+		return nil, err
+	}
+	return &refund, nil
+}
+
+func (r *GormRepository) GetLatestRefundsByPurchaseIDs(ctx context.Context, purchaseIDs []string) ([]*entities.PurchaseRefund, error) {
+	var refunds []*entities.PurchaseRefund
+	if len(purchaseIDs) == 0 {
+		return refunds, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Raw(`SELECT DISTINCT ON (purchase_id) * FROM purchase_refunds
+			WHERE purchase_id IN ?
+			ORDER BY purchase_id, created_at DESC, id DESC`, purchaseIDs).
+		Scan(&refunds).Error; err != nil {
+		return nil, err
+	}
+	return refunds, nil
+}
+
+func (r *GormRepository) StreamExportRows(ctx context.Context, buyerID, sellerID string, filter dtos.ExportPurchasesFilter, fn func(row *exportRow) error) error {
+	query := r.db.WithContext(ctx).Table("purchase_items i").
+		Select(`p.id AS purchase_id, p.created_at, p.status, p.user_id AS buyer_id, p.currency,
+			i.seller_id, i.product_id, i.name, i.sku, i.qty, i.price, i.line_total,
+			d.status AS payment_status, d.confirmed_at AS payment_confirmed_at`).
+		Joins("JOIN purchases p ON p.id = i.purchase_id").
+		Joins("LEFT JOIN purchase_payment_details d ON d.purchase_id = i.purchase_id AND d.seller_id = i.seller_id")
+	if buyerID != "" {
+		query = query.Where("p.user_id = ?", buyerID)
+	}
+	if sellerID != "" {
+		query = query.Where("i.seller_id = ?", sellerID)
+	}
+	if filter.Status != "" {
+		query = query.Where("p.status = ?", filter.Status)
+	}
+	if filter.From != nil {
+		query = query.Where("p.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("p.created_at < ?", *filter.To)
+	}
+
+	rows, err := query.Order("p.created_at ASC, p.id ASC, i.created_at ASC, i.id ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var row exportRow
+		if err := r.db.ScanRows(rows, &row); err != nil {
+			return err
+		}
+		if err := fn(&row); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// sellerSales selects the seller's sold items, joined with their purchase as p
+// and the payment to the seller as d
+func (r *GormRepository) sellerSales(ctx context.Context, query salesQuery) *gorm.DB {
+	return r.db.WithContext(ctx).Table("purchase_items i").
+		Joins("JOIN purchases p ON p.id = i.purchase_id").
+		Joins("JOIN purchase_payment_details d ON d.purchase_id = i.purchase_id AND d.seller_id = i.seller_id").
+		Where("i.seller_id = ? AND p.currency = ?", query.SellerID, query.Currency).
+		Where("p.created_at >= ? AND p.created_at < ?", query.From, query.To).
+		Where("d.status = ? AND p.status <> ?", entities.PurchaseStatusConfirmed, entities.PurchaseStatusRefunded)
+}
+
+// netLineTotal is what the seller was paid for an item: its line total less
+// its share of the seller's voucher discount, split in proportion to the line
+// totals so the items of a payment add up to the revenue of that payment
+const netLineTotal = `i.line_total - COALESCE(d.discount * i.line_total / NULLIF((SELECT SUM(o.line_total)
+	FROM purchase_items o WHERE o.purchase_id = i.purchase_id AND o.seller_id = i.seller_id), 0), 0)`
+
+func (r *GormRepository) GetSellerRevenueByPeriod(ctx context.Context, query salesQuery) ([]*revenuePeriod, error) {
+	// Revenue is what the seller was paid for their items, so it is summed per
+	// payment rather than per item to take voucher discounts off, leaving out
+	// what the buyer paid for shipping
+	var periods []*revenuePeriod
+	if err := r.db.WithContext(ctx).Table("purchase_payment_details d").
+		Joins("JOIN purchases p ON p.id = d.purchase_id").
+		Joins("LEFT JOIN purchase_shipments s ON s.purchase_id = d.purchase_id AND s.seller_id = d.seller_id").
+		Select(`date_trunc(?, p.created_at AT TIME ZONE 'UTC') AS period,
+			SUM(d.total_price - COALESCE(s.cost, 0)) AS revenue, COUNT(*) AS orders,
+			SUM((SELECT SUM(i.qty) FROM purchase_items i WHERE i.purchase_id = d.purchase_id AND i.seller_id = d.seller_id)) AS items_sold`, query.Interval).
+		Where("d.seller_id = ? AND p.currency = ?", query.SellerID, query.Currency).
+		Where("p.created_at >= ? AND p.created_at < ?", query.From, query.To).
+		Where("d.status = ? AND p.status <> ?", entities.PurchaseStatusConfirmed, entities.PurchaseStatusRefunded).
+		Group("period").
+		Order("period ASC").
+		Scan(&periods).Error; err != nil {
+		return nil, err
+	}
+	return periods, nil
+}
+
+func (r *GormRepository) GetSellerTopProducts(ctx context.Context, query salesQuery, limit int) ([]*productSales, error) {
+	var products []*productSales
+	if err := r.sellerSales(ctx, query).
+		Select("i.product_id, MAX(i.name) AS name, SUM(i.qty) AS qty_sold, ROUND(SUM(" + netLineTotal + "), 2) AS revenue").
+		Group("i.product_id").
+		Order("revenue DESC, qty_sold DESC, i.product_id ASC").
+		Limit(limit).
+		Scan(&products).Error; err != nil {
+		return nil, err
+	}
+	return products, nil
+}
+
+func (r *GormRepository) GetSellerTopCategories(ctx context.Context, query salesQuery, limit int) ([]*categorySales, error) {
+	var categories []*categorySales
+	if err := r.sellerSales(ctx, query).
+		Select("i.category, SUM(i.qty) AS qty_sold, ROUND(SUM(" + netLineTotal + "), 2) AS revenue").
+		Group("i.category").
+		Order("revenue DESC, qty_sold DESC, i.category ASC").
+		Limit(limit).
+		Scan(&categories).Error; err != nil {
+		return nil, err
+	}
+	return categories, nil
+}
+
+func (r *GormRepository) GetSellerConversion(ctx context.Context, query salesQuery) (*conversionCounts, error) {
+	var counts conversionCounts
+	if err := r.db.WithContext(ctx).Table("purchase_payment_details d").
+		Joins("JOIN purchases p ON p.id = d.purchase_id").
+		Select("COUNT(*) AS created, COUNT(*) FILTER (WHERE d.status = ?) AS confirmed", entities.PurchaseStatusConfirmed).
+		Where("d.seller_id = ? AND p.currency = ?", query.SellerID, query.Currency).
+		Where("p.created_at >= ? AND p.created_at < ?", query.From, query.To).
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+	return &counts, nil
+}
+
+func (r *GormRepository) GetInvoicesByPurchaseID(ctx context.Context, purchaseID string) ([]*entities.PurchaseInvoice, error) {
+	var invoices []*entities.PurchaseInvoice
+	if err := r.db.WithContext(ctx).Where("purchase_id = ?", purchaseID).Find(&invoices).Error; err != nil {
+		return nil, err
+	}
+	return invoices, nil
+}
+
+func (r *GormRepository) CreateInvoice(ctx context.Context, invoice *entities.PurchaseInvoice) error {
+	return r.db.WithContext(ctx).Create(invoice).Error
+}
+
+func (r *GormRepository) NextInvoiceSequence(ctx context.Context, sellerID string) (int, error) {
+	var sequence int
+	if err := r.db.WithContext(ctx).Raw(`
+		INSERT INTO seller_invoice_counters (seller_id, last_sequence, updated_at)
+		VALUES (?, 1, NOW())
+		ON CONFLICT (seller_id) DO UPDATE
+		SET last_sequence = seller_invoice_counters.last_sequence + 1, updated_at = NOW()
+		RETURNING last_sequence`, sellerID).Scan(&sequence).Error; err != nil {
+		return 0, err
+	}
+	return sequence, nil
+}
+
+func (r *GormRepository) GetCartByUserID(ctx context.Context, userID string) (*entities.Cart, error) {
+	var cart entities.Cart
+	if err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&cart).Error; err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func (r *GormRepository) LockCartByUserID(ctx context.Context, userID string) (*entities.Cart, error) {
+	parsedUserID, err := uuid.Parse(userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}}, DoNothing: true}).
+		Create(&entities.Cart{UserID: parsedUserID}).Error; err != nil {
+		return nil, err
+	}
+
+	var cart entities.Cart
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		First(&cart).Error; err != nil {
+		return nil, err
+	}
+	return &cart, nil
+}
+
+func (r *GormRepository) GetCartItems(ctx context.Context, cartID string) ([]*entities.CartItem, error) {
+	var items []*entities.CartItem
+	if err := r.db.WithContext(ctx).
+		Where("cart_id = ?", cartID).
+		Order("created_at ASC, id ASC").
+		Find(&items).Error; err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+func (r *GormRepository) SaveCartItem(ctx context.Context, item *entities.CartItem) error {
+	return r.db.WithContext(ctx).Save(item).Error
+}
+
+func (r *GormRepository) DeleteCartItem(ctx context.Context, cartID, productID string) error {
+	return r.db.WithContext(ctx).
+		Where("cart_id = ? AND product_id = ?", cartID, productID).
+		Delete(&entities.CartItem{}).Error
+}
+
+func (r *GormRepository) DeleteCartItems(ctx context.Context, cartID string) error {
+	return r.db.WithContext(ctx).Where("cart_id = ?", cartID).Delete(&entities.CartItem{}).Error
+}
+
+func (r *GormRepository) BumpCartVersion(ctx context.Context, cartID string) error {
+	return r.db.WithContext(ctx).Model(&entities.Cart{}).
+		Where("id = ?", cartID).
+		Update("version", gorm.Expr("version + 1")).Error
+}
+
+func (r *GormRepository) CreateVoucher(ctx context.Context, voucher *entities.Voucher) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "code"}}, DoNothing: true}).
+		Create(voucher)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *GormRepository) GetVoucherByCode(ctx context.Context, code string) (*entities.Voucher, error) {
+	var voucher entities.Voucher
+	if err := r.db.WithContext(ctx).Where("code = ?", code).First(&voucher).Error; err != nil {
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+func (r *GormRepository) GetVoucherByID(ctx context.Context, id string) (*entities.Voucher, error) {
+	var voucher entities.Voucher
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&voucher).Error; err != nil {
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+func (r *GormRepository) LockVoucherByID(ctx context.Context, id string) (*entities.Voucher, error) {
+	var voucher entities.Voucher
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&voucher).Error; err != nil {
+		return nil, err
+	}
+	return &voucher, nil
+}
+
+func (r *GormRepository) GetVouchersBySellerID(ctx context.Context, sellerID string, page, limit int) ([]*entities.Voucher, int64, error) {
+	var vouchers []*entities.Voucher
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.Voucher{}).Where("seller_id = ?", sellerID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&vouchers).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return vouchers, total, nil
+}
+
+func (r *GormRepository) DisableVoucher(ctx context.Context, id string, disabledAt time.Time) error {
+	return r.db.WithContext(ctx).Model(&entities.Voucher{}).
+		Where("id = ? AND disabled_at IS NULL", id).
+		Update("disabled_at", disabledAt).Error
+}
+
+func (r *GormRepository) CountVoucherRedemptions(ctx context.Context, voucherID, userID string) (int64, error) {
+	var count int64
+	if err := r.db.WithContext(ctx).Model(&entities.VoucherRedemption{}).
+		Where("voucher_id = ? AND user_id = ? AND released_at IS NULL", voucherID, userID).
+		Count(&count).Error; err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *GormRepository) CreateVoucherRedemption(ctx context.Context, redemption *entities.VoucherRedemption) error {
+	return r.db.WithContext(ctx).Create(redemption).Error
+}
+
+func (r *GormRepository) IncrementVoucherUsage(ctx context.Context, voucherID string) error {
+	return r.db.WithContext(ctx).Model(&entities.Voucher{}).
+		Where("id = ?", voucherID).
+		Update("used_count", gorm.Expr("used_count + 1")).Error
+}
+
+func (r *GormRepository) ReleaseVoucherRedemption(ctx context.Context, purchaseID string) error {
+	return r.db.WithContext(ctx).Exec(`
+		WITH released AS (
+			UPDATE voucher_redemptions
+			SET released_at = NOW(), updated_at = NOW()
+			WHERE purchase_id = ? AND released_at IS NULL
+			RETURNING voucher_id
+		)
+		UPDATE vouchers
+		SET used_count = used_count - 1, updated_at = NOW()
+		WHERE id IN (SELECT voucher_id FROM released)`, purchaseID).Error
+}
+
+func (r *GormRepository) CreatePurchaseShippingAddress(ctx context.Context, address *entities.PurchaseShippingAddress) error {
+	return r.db.WithContext(ctx).Create(address).Error
+}
+
+func (r *GormRepository) CreatePurchaseShipments(ctx context.Context, shipments []*entities.PurchaseShipment) error {
+	return r.db.WithContext(ctx).Create(&shipments).Error
+}
+
+func (r *GormRepository) GetShippingAddressesByPurchaseIDs(ctx context.Context, purchaseIDs []string) ([]*entities.PurchaseShippingAddress, error) {
+	var addresses []*entities.PurchaseShippingAddress
+	if len(purchaseIDs) == 0 {
+		return addresses, nil
+	}
+	if err := r.db.WithContext(ctx).Where("purchase_id IN ?", purchaseIDs).Find(&addresses).Error; err != nil {
+		return nil, err
+	}
+	return addresses, nil
+}
+
+func (r *GormRepository) GetShipmentsByPurchaseIDs(ctx context.Context, purchaseIDs []string) ([]*entities.PurchaseShipment, error) {
+	var shipments []*entities.PurchaseShipment
+	if len(purchaseIDs) == 0 {
+		return shipments, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("purchase_id IN ?", purchaseIDs).
+		Order("created_at ASC, id ASC").
+		Find(&shipments).Error; err != nil {
+		return nil, err
+	}
+	return shipments, nil
+}
+
+func (r *GormRepository) LockShipmentsByPurchaseID(ctx context.Context, purchaseID string) ([]*entities.PurchaseShipment, error) {
+	var shipments []*entities.PurchaseShipment
+	if err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("purchase_id = ?", purchaseID).
+		Order("created_at ASC, id ASC").
+		Find(&shipments).Error; err != nil {
+		return nil, err
+	}
+	return shipments, nil
+}
+
+func (r *GormRepository) UpdateShipmentStatus(ctx context.Context, purchaseID, sellerID string, from, to entities.ShipmentStatus, fields map[string]interface{}) error {
+	updates := map[string]interface{}{"status": to}
+	for column, value := range fields {
+		updates[column] = value
+	}
+
+	result := r.db.WithContext(ctx).Model(&entities.PurchaseShipment{}).
+		Where("purchase_id = ? AND seller_id = ? AND status = ?", purchaseID, sellerID, from).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: shipment of purchase %s is no longer %s", ErrConflict, purchaseID, from)
+	}
+	return nil
+}
+
+func (r *GormRepository) CreateReview(ctx context.Context, review *entities.ProductReview) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "purchase_id"}, {Name: "product_id"}}, DoNothing: true}).
+		Create(review)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (r *GormRepository) CreateReviewImages(ctx context.Context, images []*entities.ProductReviewImage) error {
+	if len(images) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&images).Error
+}
+
+func (r *GormRepository) GetReviewsByProductID(ctx context.Context, productID string, page, limit int) ([]*entities.ProductReview, int64, error) {
+	return getReviews(r.db.WithContext(ctx).Model(&entities.ProductReview{}).Where("product_id = ?", productID), page, limit)
+}
+
+func (r *GormRepository) GetReviewsBySellerID(ctx context.Context, sellerID string, page, limit int) ([]*entities.ProductReview, int64, error) {
+	return getReviews(r.db.WithContext(ctx).Model(&entities.ProductReview{}).Where("seller_id = ?", sellerID), page, limit)
+}
+
+// getReviews returns a page of the reviews selected by query, newest first,
+// and how many there are in total
+func getReviews(query *gorm.DB, page, limit int) ([]*entities.ProductReview, int64, error) {
+	var reviews []*entities.ProductReview
+	var total int64
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&reviews).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return reviews, total, nil
+}
+
+func (r *GormRepository) GetReviewImagesByReviewIDs(ctx context.Context, reviewIDs []string) ([]*entities.ProductReviewImage, error) {
+	var images []*entities.ProductReviewImage
+	if len(reviewIDs) == 0 {
+		return images, nil
+	}
+	if err := r.db.WithContext(ctx).
+		Where("review_id IN ?", reviewIDs).
+		Order("position ASC").
+		Find(&images).Error; err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+func (r *GormRepository) AddSellerRating(ctx context.Context, sellerID string, rating int) error {
+	return r.db.WithContext(ctx).Exec(`
+		INSERT INTO seller_ratings (seller_id, review_count, rating_total, created_at, updated_at)
+		VALUES (?, 1, ?, NOW(), NOW())
+		ON CONFLICT (seller_id) DO UPDATE
+		SET review_count = seller_ratings.review_count + 1,
+			rating_total = seller_ratings.rating_total + EXCLUDED.rating_total,
+			updated_at = NOW()`, sellerID, rating).Error
+}
+
+func (r *GormRepository) GetSellerRating(ctx context.Context, sellerID string) (*entities.SellerRating, error) {
+	var rating entities.SellerRating
+	if err := r.db.WithContext(ctx).Where("seller_id = ?", sellerID).First(&rating).Error; err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+func (r *GormRepository) CreateDispute(ctx context.Context, dispute *entities.PurchaseDispute) error {
+	return r.db.WithContext(ctx).Create(dispute).Error
+}
+
+func (r *GormRepository) GetDisputeByID(ctx context.Context, id string) (*entities.PurchaseDispute, error) {
+	var dispute entities.PurchaseDispute
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&dispute).Error; err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+func (r *GormRepository) GetLatestDisputeByPurchaseID(ctx context.Context, purchaseID string) (*entities.PurchaseDispute, error) {
+	var dispute entities.PurchaseDispute
+	if err := r.db.WithContext(ctx).
+		Where("purchase_id = ?", purchaseID).
+		Order("created_at DESC, id DESC").
+		First(&dispute).Error; err != nil {
+		return nil, err
+	}
+	return &dispute, nil
+}
+
+func (r *GormRepository) GetDisputes(ctx context.Context, status entities.DisputeStatus, page, limit int) ([]*entities.PurchaseDispute, int64, error) {
+	var disputes []*entities.PurchaseDispute
+	var total int64
+
+	query := r.db.WithContext(ctx).Model(&entities.PurchaseDispute{})
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	if err := query.
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&disputes).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return disputes, total, nil
+}
+
+func (r *GormRepository) UpdateDisputeStatus(ctx context.Context, id string, from, to entities.DisputeStatus, fields map[string]interface{}) error {
+	updates := map[string]interface{}{"status": to}
+	for column, value := range fields {
+		updates[column] = value
+	}
+
+	result := r.db.WithContext(ctx).Model(&entities.PurchaseDispute{}).
+		Where("id = ? AND status = ?", id, from).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("%w: dispute %s is no longer %s", ErrConflict, id, from)
+	}
+	return nil
+}
+
+func (r *GormRepository) CreateDisputeMessage(ctx context.Context, message *entities.PurchaseDisputeMessage) error {
+	return r.db.WithContext(ctx).Create(message).Error
+}
+
+func (r *GormRepository) CreateDisputeEvidence(ctx context.Context, evidence []*entities.PurchaseDisputeEvidence) error {
+	if len(evidence) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&evidence).Error
+}
+
+func (r *GormRepository) GetDisputeMessagesByDisputeID(ctx context.Context, disputeID string) ([]*entities.PurchaseDisputeMessage, error) {
+	var messages []*entities.PurchaseDisputeMessage
+	if err := r.db.WithContext(ctx).
+		Where("dispute_id = ?", disputeID).
+		Order("created_at ASC, id ASC").
+		Find(&messages).Error; err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+func (r *GormRepository) GetDisputeEvidenceByDisputeID(ctx context.Context, disputeID string) ([]*entities.PurchaseDisputeEvidence, error) {
+	var evidence []*entities.PurchaseDisputeEvidence
+	if err := r.db.WithContext(ctx).
+		Where("dispute_id = ?", disputeID).
+		Order("position ASC").
+		Find(&evidence).Error; err != nil {
+		return nil, err
+	}
+	return evidence, nil
+}
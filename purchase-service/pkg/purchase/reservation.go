@@ -0,0 +1,122 @@
+package purchase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	nethttp "net/http"
+	"purchase-service/pkg/http"
+	"sort"
+	"time"
+)
+
+// Stock calls made after a purchase was saved cannot be rolled back with it,
+// so they are retried a few times before giving up
+const (
+	stockRetryAttempts = 3
+	stockRetryDelay    = time.Second
+)
+
+// reserveStock holds stock for every product of a purchase. If any product
+// cannot be reserved, the reservations already made are released again so a
+// multi-seller cart never ends up half-reserved.
+func (s *service) reserveStock(ctx context.Context, purchaseID string, quantities map[string]int, userID string) error {
+	// Reserve in a stable order so concurrent carts contend predictably
+	productIDs := make([]string, 0, len(quantities))
+	for productID := range quantities {
+		productIDs = append(productIDs, productID)
+	}
+	sort.Strings(productIDs)
+
+	reserved := make([]string, 0, len(productIDs))
+	for _, productID := range productIDs {
+		if err := s.productClient.ReserveProductQuantity(ctx, productID, purchaseID, quantities[productID], userID); err != nil {
+			s.releaseStock(ctx, purchaseID, reserved, userID)
+			return upstreamError(err, "failed to reserve product "+productID)
+		}
+		reserved = append(reserved, productID)
+	}
+
+	return nil
+}
+
+// commitStock turns the reservations of a purchase into sold stock
+func (s *service) commitStock(ctx context.Context, purchaseID string, productIDs []string, userID string) error {
+	for _, productID := range uniqueStrings(productIDs) {
+		if err := s.productClient.CommitProductReservation(ctx, productID, purchaseID, userID); err != nil {
+			return upstreamError(err, "failed to commit reservation for product "+productID)
+		}
+	}
+	return nil
+}
+
+// commitSavedStock commits the reservations of a purchase whose payment was
+// confirmed in a transaction that has already committed. The product service
+// cannot take a commit back, so it is never done before the confirmation is
+// saved. Failures are retried and then logged; until a commit succeeds the
+// stock stays reserved for the purchase, which keeps it off sale all the same.
+func (s *service) commitSavedStock(ctx context.Context, purchaseID string, productIDs []string, userID string) {
+	ctx = context.WithoutCancel(ctx)
+	if err := retryStock(func() error {
+		return s.commitStock(ctx, purchaseID, productIDs, userID)
+	}); err != nil {
+		log.Printf("failed to commit stock for purchase %s: %v", purchaseID, err)
+	}
+}
+
+// releaseStock returns held stock to the product service. It is best effort:
+// failures are logged, and release is idempotent so it can safely be retried.
+func (s *service) releaseStock(ctx context.Context, purchaseID string, productIDs []string, userID string) {
+	if err := s.tryReleaseStock(ctx, purchaseID, productIDs, userID); err != nil {
+		log.Printf("failed to release stock for purchase %s: %v", purchaseID, err)
+	}
+}
+
+// tryReleaseStock releases every reservation of a purchase and reports the
+// first failure, for callers that must not move on with stock still held
+func (s *service) tryReleaseStock(ctx context.Context, purchaseID string, productIDs []string, userID string) error {
+	// Compensation must still run when the originating request was cancelled
+	ctx = context.WithoutCancel(ctx)
+
+	var firstErr error
+	for _, productID := range uniqueStrings(productIDs) {
+		if err := s.productClient.ReleaseProductReservation(ctx, productID, purchaseID, userID); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to release reservation for product %s: %w", productID, err)
+		}
+	}
+	return firstErr
+}
+
+// retryStock calls fn until it succeeds, the product service refuses it or
+// stockRetryAttempts is reached, waiting longer after every failure
+func retryStock(fn func() error) error {
+	var err error
+	for attempt := 0; attempt < stockRetryAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(stockRetryDelay << (attempt - 1))
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+		// A 4xx answer will not change by asking again
+		var statusErr *http.StatusError
+		if errors.As(err, &statusErr) && statusErr.StatusCode < nethttp.StatusInternalServerError {
+			return err
+		}
+	}
+	return err
+}
+
+// uniqueStrings removes duplicates while keeping the original order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	result := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			result = append(result, v)
+		}
+	}
+	return result
+}
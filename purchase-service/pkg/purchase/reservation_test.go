@@ -0,0 +1,44 @@
+package purchase
+
+import (
+	"errors"
+	nethttp "net/http"
+	"purchase-service/pkg/http"
+	"testing"
+)
+
+func TestRetryStock(t *testing.T) {
+	unavailable := &http.StatusError{Service: "product service", StatusCode: nethttp.StatusServiceUnavailable}
+	conflict := &http.StatusError{Service: "product service", StatusCode: nethttp.StatusConflict}
+
+	tests := []struct {
+		name      string
+		results   []error // Result of each call; calls past the end succeed
+		wantCalls int
+		wantErr   error
+	}{
+		{name: "succeeds at once", wantCalls: 1},
+		{name: "retries while the product service is down", results: []error{unavailable}, wantCalls: 2},
+		{name: "retries connection errors", results: []error{errInjected}, wantCalls: 2},
+		{name: "refused calls are not retried", results: []error{conflict}, wantCalls: 1, wantErr: conflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			err := retryStock(func() error {
+				calls++
+				if calls <= len(tt.results) {
+					return tt.results[calls-1]
+				}
+				return nil
+			})
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("retryStock() error = %v, want %v", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("retryStock() made %d calls, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
@@ -0,0 +1,248 @@
+package purchase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"purchase-service/api/presenter"
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/entities"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateReview lets the buyer of a completed purchase rate one of its products
+// and adds the rating to the seller's running total
+func (s *service) CreateReview(ctx context.Context, purchaseID string, req dtos.CreateReviewRequest) (*presenter.ReviewResponse, error) {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	purchase, err := s.getBuyerPurchase(ctx, s.repo, purchaseID, userID)
+	if err != nil {
+		return nil, err
+	}
+	// Buyers only review what they received
+	if purchase.Status != entities.PurchaseStatusCompleted {
+		return nil, fmt.Errorf("%w: purchase is %s, only completed purchases can be reviewed", ErrConflict, purchase.Status)
+	}
+
+	items, err := s.repo.GetPurchaseItemsByPurchaseID(ctx, purchaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchase items: %w", err)
+	}
+	var item *entities.PurchaseItem
+	for _, candidate := range items {
+		if candidate.ProductID == req.ProductID {
+			item = candidate
+			break
+		}
+	}
+	if item == nil {
+		return nil, fmt.Errorf("%w: product %s is not part of this purchase", ErrInvalidInput, req.ProductID)
+	}
+	// Items that could not be attributed to a seller have nobody to rate
+	if item.SellerID == uuid.Nil {
+		return nil, fmt.Errorf("%w: product %s has no seller to review", ErrInvalidInput, req.ProductID)
+	}
+
+	fileIDs := uniqueStrings(req.FileIds)
+	if len(fileIDs) > 0 {
+		if err := s.verifyUserFiles(ctx, fileIDs, userID, "review image"); err != nil {
+			return nil, err
+		}
+	}
+
+	review := &entities.ProductReview{
+		PurchaseID:  purchase.ID,
+		ProductID:   item.ProductID,
+		SellerID:    item.SellerID,
+		UserID:      purchase.UserID,
+		ProductName: item.Name,
+		Rating:      req.Rating,
+		Comment:     req.Comment,
+	}
+	images := make([]*entities.ProductReviewImage, 0, len(fileIDs))
+
+	err = s.repo.WithTransaction(ctx, func(tx Repository) error {
+		created, err := tx.CreateReview(ctx, review)
+		if err != nil {
+			return fmt.Errorf("failed to create review: %w", err)
+		}
+		if !created {
+			return fmt.Errorf("%w: product %s was already reviewed for this purchase", ErrConflict, req.ProductID)
+		}
+
+		for i, fileID := range fileIDs {
+			images = append(images, &entities.ProductReviewImage{
+				ReviewID: review.ID,
+				FileID:   uuid.MustParse(fileID),
+				Position: i,
+			})
+		}
+		if err := tx.CreateReviewImages(ctx, images); err != nil {
+			return fmt.Errorf("failed to create review images: %w", err)
+		}
+
+		if err := tx.AddSellerRating(ctx, review.SellerID.String(), review.Rating); err != nil {
+			return fmt.Errorf("failed to update seller rating: %w", err)
+		}
+
+		return tx.CreateNotification(ctx, &entities.PurchaseNotification{
+			UserID:     review.SellerID,
+			PurchaseID: purchase.ID,
+			Type:       entities.NotificationReviewReceived,
+			Message:    fmt.Sprintf("%s received a %d-star review", item.Name, review.Rating),
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.reviewResponses(ctx, []*entities.ProductReview{review})
+	if err != nil {
+		return nil, err
+	}
+	return &responses[0], nil
+}
+
+// ListProductReviews lists the reviews of a product, newest first
+func (s *service) ListProductReviews(ctx context.Context, productID string, page, limit int) (*presenter.ListReviewsResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	reviews, total, err := s.repo.GetReviewsByProductID(ctx, productID, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reviews: %w", err)
+	}
+
+	responses, err := s.reviewResponses(ctx, reviews)
+	if err != nil {
+		return nil, err
+	}
+
+	return &presenter.ListReviewsResponse{
+		Reviews: responses,
+		Total:   int(total),
+		Page:    page,
+		Limit:   limit,
+	}, nil
+}
+
+// ListSellerReviews lists the reviews of all of a seller's products, newest
+// first, together with the seller's overall rating
+func (s *service) ListSellerReviews(ctx context.Context, sellerID string, page, limit int) (*presenter.ListSellerReviewsResponse, error) {
+	rating, err := s.GetSellerRating(ctx, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	reviews, total, err := s.repo.GetReviewsBySellerID(ctx, sellerID, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reviews: %w", err)
+	}
+
+	responses, err := s.reviewResponses(ctx, reviews)
+	if err != nil {
+		return nil, err
+	}
+
+	return &presenter.ListSellerReviewsResponse{
+		Rating:  *rating,
+		Reviews: responses,
+		Total:   int(total),
+		Page:    page,
+		Limit:   limit,
+	}, nil
+}
+
+// GetSellerRating returns the average rating of a seller. Sellers without
+// reviews have an average of 0.
+func (s *service) GetSellerRating(ctx context.Context, sellerID string) (*presenter.SellerRatingResponse, error) {
+	if _, err := uuid.Parse(sellerID); err != nil {
+		return nil, fmt.Errorf("%w: sellerId is not valid", ErrInvalidInput)
+	}
+
+	response := &presenter.SellerRatingResponse{SellerID: sellerID}
+	rating, err := s.repo.GetSellerRating(ctx, sellerID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return response, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seller rating: %w", err)
+	}
+
+	response.ReviewCount = rating.ReviewCount
+	if rating.ReviewCount > 0 {
+		response.AverageRating = math.Round(float64(rating.RatingTotal)*100/float64(rating.ReviewCount)) / 100
+	}
+	return response, nil
+}
+
+// reviewResponses converts stored reviews to their API representation, loading
+// the images of all of them at once
+func (s *service) reviewResponses(ctx context.Context, reviews []*entities.ProductReview) ([]presenter.ReviewResponse, error) {
+	reviewIDs := make([]string, 0, len(reviews))
+	for _, review := range reviews {
+		reviewIDs = append(reviewIDs, review.ID.String())
+	}
+
+	images, err := s.repo.GetReviewImagesByReviewIDs(ctx, reviewIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review images: %w", err)
+	}
+	fileIDs := make([]string, 0, len(images))
+	for _, image := range images {
+		fileIDs = append(fileIDs, image.FileID.String())
+	}
+
+	filesByID := s.getFiles(ctx, fileIDs)
+
+	imagesByReview := make(map[string][]presenter.ReviewImage)
+	for _, image := range images {
+		reviewID := image.ReviewID.String()
+		response := presenter.ReviewImage{FileID: image.FileID.String()}
+		// Files deleted from the file store keep their ID but lose their URIs
+		if file, ok := filesByID[response.FileID]; ok {
+			response.FileURI = file.FileURI
+			response.FileThumbnailURI = file.FileThumbnailURI
+		}
+		imagesByReview[reviewID] = append(imagesByReview[reviewID], response)
+	}
+
+	responses := make([]presenter.ReviewResponse, 0, len(reviews))
+	for _, review := range reviews {
+		reviewImages := imagesByReview[review.ID.String()]
+		if reviewImages == nil {
+			reviewImages = []presenter.ReviewImage{}
+		}
+		responses = append(responses, presenter.ReviewResponse{
+			ReviewID:    review.ID.String(),
+			PurchaseID:  review.PurchaseID.String(),
+			ProductID:   review.ProductID,
+			ProductName: review.ProductName,
+			SellerID:    review.SellerID.String(),
+			UserID:      review.UserID.String(),
+			Rating:      review.Rating,
+			Comment:     review.Comment,
+			Images:      reviewImages,
+			CreatedAt:   review.CreatedAt.Format(time.RFC3339),
+		})
+	}
+	return responses, nil
+}
@@ -0,0 +1,275 @@
+package purchase
+
+import (
+	"context"
+	"fmt"
+	"purchase-service/api/presenter"
+	"purchase-service/pkg/entities"
+	"purchase-service/pkg/money"
+	"time"
+)
+
+func (s *service) ListSellerOrders(ctx context.Context, page, limit int, status string) (*presenter.ListSellerOrdersResponse, error) {
+	sellerID, ok := ctx.Value("user_id").(string)
+	if !ok || sellerID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	// Set default pagination values
+	if page <= 0 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	if status != "" && !IsValidStatus(status) {
+		return nil, fmt.Errorf("%w: unknown status %s", ErrInvalidInput, status)
+	}
+
+	purchases, total, err := s.repo.GetPurchasesBySellerID(ctx, sellerID, entities.PurchaseStatus(status), page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get seller orders: %w", err)
+	}
+
+	// Load related rows and proof files for the whole page at once
+	details, err := s.loadPurchaseDetails(ctx, purchases)
+	if err != nil {
+		return nil, err
+	}
+
+	orders := make([]presenter.SellerOrderResponse, 0, len(purchases))
+	for _, purchase := range purchases {
+		orders = append(orders, sellerOrderResponse(purchase, details, sellerID))
+	}
+
+	return &presenter.ListSellerOrdersResponse{
+		Orders: orders,
+		Total:  int(total),
+		Page:   page,
+		Limit:  limit,
+	}, nil
+}
+
+func (s *service) GetSellerOrder(ctx context.Context, purchaseID string) (*presenter.SellerOrderResponse, error) {
+	sellerID, ok := ctx.Value("user_id").(string)
+	if !ok || sellerID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	purchase, _, err := s.getSellerPurchase(ctx, s.repo, purchaseID, sellerID)
+	if err != nil {
+		return nil, err
+	}
+
+	details, err := s.loadPurchaseDetails(ctx, []*entities.Purchase{purchase})
+	if err != nil {
+		return nil, err
+	}
+
+	order := sellerOrderResponse(purchase, details, sellerID)
+	return &order, nil
+}
+
+// ConfirmPayment accepts the buyer's payment proof for the calling seller,
+// lets the buyer know and then turns the seller's reserved stock into sold
+// stock. The purchase is confirmed once every seller has confirmed their
+// payment.
+func (s *service) ConfirmPayment(ctx context.Context, purchaseID string) error {
+	sellerID, ok := ctx.Value("user_id").(string)
+	if !ok || sellerID == "" {
+		return ErrUnauthenticated
+	}
+
+	var buyerID string
+	var productIDs []string
+	err := s.repo.WithTransaction(ctx, func(tx Repository) error {
+		_, items, err := s.getSellerPurchase(ctx, tx, purchaseID, sellerID)
+		if err != nil {
+			return err
+		}
+
+		purchase, err := lockPayablePurchase(ctx, tx, purchaseID, entities.PurchaseStatusConfirmed)
+		if err != nil {
+			return err
+		}
+		detail, err := getSellerPaymentDetail(ctx, tx, purchaseID, sellerID)
+		if err != nil {
+			return err
+		}
+		if detail.Status != entities.PurchaseStatusProofUploaded {
+			return &InvalidTransitionError{From: detail.Status, To: entities.PurchaseStatusConfirmed}
+		}
+
+		now := time.Now()
+		if err := tx.UpdatePaymentDetailStatus(ctx, purchaseID, sellerID, detail.Status, entities.PurchaseStatusConfirmed, map[string]interface{}{
+			"confirmed_at":  now,
+			"status_reason": "",
+		}); err != nil {
+			return fmt.Errorf("failed to confirm payment: %w", err)
+		}
+		if err := tx.UpdatePaymentProofStatus(ctx, purchaseID, sellerID, entities.PaymentProofStatusPending, entities.PaymentProofStatusAccepted, &now); err != nil {
+			return fmt.Errorf("failed to accept payment proofs: %w", err)
+		}
+		if err := s.syncPurchaseStatus(ctx, tx, purchase, nil); err != nil {
+			return err
+		}
+
+		buyerID = purchase.UserID.String()
+		productIDs = make([]string, 0, len(items))
+		for _, item := range items {
+			if item.SellerID.String() == sellerID {
+				productIDs = append(productIDs, item.ProductID)
+			}
+		}
+
+		return tx.CreateNotification(ctx, &entities.PurchaseNotification{
+			UserID:     purchase.UserID,
+			PurchaseID: purchase.ID,
+			Type:       entities.NotificationPaymentConfirmed,
+			Message:    "Your payment has been confirmed by the seller",
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	s.commitSavedStock(ctx, purchaseID, productIDs, buyerID)
+	return nil
+}
+
+// RejectPayment sends the payment to the calling seller back to awaiting
+// payment so the buyer can upload a new proof, and tells the buyer why
+func (s *service) RejectPayment(ctx context.Context, purchaseID, reason string) error {
+	sellerID, ok := ctx.Value("user_id").(string)
+	if !ok || sellerID == "" {
+		return ErrUnauthenticated
+	}
+
+	return s.repo.WithTransaction(ctx, func(tx Repository) error {
+		if _, _, err := s.getSellerPurchase(ctx, tx, purchaseID, sellerID); err != nil {
+			return err
+		}
+
+		purchase, err := lockPayablePurchase(ctx, tx, purchaseID, entities.PurchaseStatusPendingPayment)
+		if err != nil {
+			return err
+		}
+		detail, err := getSellerPaymentDetail(ctx, tx, purchaseID, sellerID)
+		if err != nil {
+			return err
+		}
+		// Only an uploaded proof can be rejected
+		if detail.Status != entities.PurchaseStatusProofUploaded {
+			return &InvalidTransitionError{From: detail.Status, To: entities.PurchaseStatusPendingPayment}
+		}
+
+		if err := tx.UpdatePaymentDetailStatus(ctx, purchaseID, sellerID, detail.Status, entities.PurchaseStatusPendingPayment, map[string]interface{}{
+			"status_reason": reason,
+		}); err != nil {
+			return fmt.Errorf("failed to reject payment: %w", err)
+		}
+
+		now := time.Now()
+		if err := tx.UpdatePaymentProofStatus(ctx, purchaseID, sellerID, entities.PaymentProofStatusPending, entities.PaymentProofStatusRejected, &now); err != nil {
+			return fmt.Errorf("failed to reject payment proofs: %w", err)
+		}
+		if err := s.syncPurchaseStatus(ctx, tx, purchase, map[string]interface{}{
+			"status_reason": reason,
+		}); err != nil {
+			return err
+		}
+
+		return tx.CreateNotification(ctx, &entities.PurchaseNotification{
+			UserID:     purchase.UserID,
+			PurchaseID: purchase.ID,
+			Type:       entities.NotificationPaymentRejected,
+			Message:    "Your payment proof was rejected: " + reason,
+		})
+	})
+}
+
+// getSellerPurchase loads a purchase and its items, verifying the seller sold
+// at least one of them
+func (s *service) getSellerPurchase(ctx context.Context, repo Repository, purchaseID, sellerID string) (*entities.Purchase, []*entities.PurchaseItem, error) {
+	purchase, err := getPurchase(ctx, repo, purchaseID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	items, err := repo.GetPurchaseItemsByPurchaseID(ctx, purchaseID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get purchase items: %w", err)
+	}
+
+	for _, item := range items {
+		if item.SellerID.String() == sellerID {
+			return purchase, items, nil
+		}
+	}
+	return nil, nil, fmt.Errorf("%w: purchase does not belong to seller", ErrForbidden)
+}
+
+// sellerOrderResponse builds the seller's view of a purchase: only their own
+// items and payment details, plus the buyer's payment proof files
+func sellerOrderResponse(purchase *entities.Purchase, details *purchaseDetails, sellerID string) presenter.SellerOrderResponse {
+	purchaseID := purchase.ID.String()
+
+	var sellerItems []*entities.PurchaseItem
+	var totalPrice money.Amount
+	for _, item := range details.items[purchaseID] {
+		if item.SellerID.String() == sellerID {
+			sellerItems = append(sellerItems, item)
+			totalPrice = totalPrice.Add(item.LineTotal)
+		}
+	}
+
+	// The seller's share of a voucher discount comes off their total
+	var discount money.Amount
+	var paymentDetail *presenter.PaymentDetail
+	for _, detail := range paymentDetailResponses(details.paymentDetails[purchaseID]) {
+		if detail.SellerID == sellerID {
+			paymentDetail = &detail
+			discount = detail.Discount
+			break
+		}
+	}
+
+	// The seller is paid for shipping their items on top of them
+	var shipment *presenter.ShipmentResponse
+	for _, response := range shipmentResponses(details.shipments[purchaseID]) {
+		if response.SellerID == sellerID {
+			shipment = &response
+			totalPrice = totalPrice.Add(response.Cost)
+			break
+		}
+	}
+
+	// Proofs made out to another seller are none of this seller's business
+	var proofs []*entities.PurchasePaymentProof
+	for _, proof := range details.proofs[purchaseID] {
+		if proof.SellerID == nil || proof.SellerID.String() == sellerID {
+			proofs = append(proofs, proof)
+		}
+	}
+
+	return presenter.SellerOrderResponse{
+		PurchaseID:      purchaseID,
+		BuyerID:         purchase.UserID.String(),
+		Status:          string(purchase.Status),
+		StatusHistory:   statusTimestamps(purchase),
+		StatusReason:    purchase.StatusReason,
+		PurchasedItems:  purchaseItemResponses(sellerItems),
+		TotalPrice:      totalPrice.Sub(discount),
+		Discount:        discount,
+		Currency:        purchase.Currency,
+		PaymentDetail:   paymentDetail,
+		PaymentProofs:   paymentProofFiles(proofs, details.files),
+		Refund:          refundResponse(details.refunds[purchaseID]),
+		SenderInfo:      senderInfo(details.senders[purchaseID]),
+		ShippingAddress: shippingAddressResponse(details.addresses[purchaseID]),
+		Shipment:        shipment,
+		CreatedAt:       purchase.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:       purchase.UpdatedAt.Format(time.RFC3339),
+	}
+}
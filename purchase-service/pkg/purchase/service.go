@@ -2,36 +2,96 @@ package purchase
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"maps"
 	"purchase-service/api/presenter"
 	"purchase-service/pkg/dtos"
 	"purchase-service/pkg/entities"
 	"purchase-service/pkg/http"
+	"purchase-service/pkg/money"
 	"sort"
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Service interface {
 	CreatePurchase(ctx context.Context, req dtos.CreatePurchaseRequest) (*presenter.PurchaseResponse, error)
 	UploadPaymentProof(ctx context.Context, purchaseID string, req dtos.PaymentProofRequest) error
 	GetPurchaseByID(ctx context.Context, purchaseID string) (*presenter.GetPurchaseResponse, error)
-	ListPurchases(ctx context.Context, page, limit int) (*presenter.ListPurchasesResponse, error)
+	GetInvoice(ctx context.Context, purchaseID string) ([]byte, error)
+	ListPurchases(ctx context.Context, filter dtos.ListPurchasesFilter) (*presenter.ListPurchasesResponse, error)
+	// ExportPurchases and ExportSellerOrders validate the filter and return a
+	// function streaming the buyer's or seller's rows in the requested format
+	ExportPurchases(ctx context.Context, filter dtos.ExportPurchasesFilter) (ExportFunc, error)
+	ListNotifications(ctx context.Context, page, limit int) (*presenter.ListNotificationsResponse, error)
+	ListSellerOrders(ctx context.Context, page, limit int, status string) (*presenter.ListSellerOrdersResponse, error)
+	GetSellerOrder(ctx context.Context, purchaseID string) (*presenter.SellerOrderResponse, error)
+	ExportSellerOrders(ctx context.Context, filter dtos.ExportPurchasesFilter) (ExportFunc, error)
+	GetSellerAnalytics(ctx context.Context, filter dtos.SellerAnalyticsFilter) (*presenter.SellerAnalyticsResponse, error)
+	ConfirmPayment(ctx context.Context, purchaseID string) error
+	RejectPayment(ctx context.Context, purchaseID, reason string) error
+	CancelPurchase(ctx context.Context, purchaseID, reason string) error
+	RequestRefund(ctx context.Context, purchaseID, reason string) error
+	ApproveRefund(ctx context.Context, purchaseID string) error
+	RejectRefund(ctx context.Context, purchaseID, reason string) error
+	ExpireOverduePurchases(ctx context.Context, deadline time.Duration, batchSize int) (int, error)
+	// GetExistingPurchaseIDs is for other services: the product service asks
+	// it whether the purchases stock is still reserved for were ever written
+	GetExistingPurchaseIDs(ctx context.Context, req dtos.PurchaseIDsRequest) (*presenter.PurchaseIDsResponse, error)
+	GetCart(ctx context.Context) (*presenter.CartResponse, error)
+	AddCartItem(ctx context.Context, req dtos.AddCartItemRequest) (*presenter.CartResponse, error)
+	UpdateCartItem(ctx context.Context, productID string, req dtos.UpdateCartItemRequest) (*presenter.CartResponse, error)
+	RemoveCartItem(ctx context.Context, productID string) (*presenter.CartResponse, error)
+	ClearCart(ctx context.Context) error
+	// Checkout revalidates the cart against the product service and turns it
+	// into a purchase, emptying the cart
+	Checkout(ctx context.Context, req dtos.CheckoutRequest) (*presenter.PurchaseResponse, error)
+	CreateVoucher(ctx context.Context, req dtos.CreateVoucherRequest) (*presenter.VoucherResponse, error)
+	ListSellerVouchers(ctx context.Context, page, limit int) (*presenter.ListVouchersResponse, error)
+	DisableVoucher(ctx context.Context, voucherID string) error
+	// CreatePlatformVoucher and DisablePlatformVoucher manage the vouchers that
+	// discount whole purchases. They are for admins; the routes serving them
+	// check the caller is one
+	CreatePlatformVoucher(ctx context.Context, req dtos.CreateVoucherRequest) (*presenter.VoucherResponse, error)
+	DisablePlatformVoucher(ctx context.Context, voucherID string) error
+	ShipOrder(ctx context.Context, purchaseID string, req dtos.ShipOrderRequest) error
+	// ConfirmReceipt completes the purchase once the buyer received the
+	// shipments of all its sellers, or right away when it has none
+	ConfirmReceipt(ctx context.Context, purchaseID string, req dtos.ConfirmReceiptRequest) error
+	CreateReview(ctx context.Context, purchaseID string, req dtos.CreateReviewRequest) (*presenter.ReviewResponse, error)
+	ListProductReviews(ctx context.Context, productID string, page, limit int) (*presenter.ListReviewsResponse, error)
+	// ListSellerReviews returns a page of the reviews of the seller's products
+	// together with the seller's overall rating
+	ListSellerReviews(ctx context.Context, sellerID string, page, limit int) (*presenter.ListSellerReviewsResponse, error)
+	GetSellerRating(ctx context.Context, sellerID string) (*presenter.SellerRatingResponse, error)
+	OpenDispute(ctx context.Context, purchaseID string, req dtos.DisputeMessageRequest) (*presenter.DisputeResponse, error)
+	GetDispute(ctx context.Context, purchaseID string) (*presenter.DisputeResponse, error)
+	AddDisputeMessage(ctx context.Context, purchaseID string, req dtos.DisputeMessageRequest) (*presenter.DisputeMessageResponse, error)
+	EscalateDispute(ctx context.Context, purchaseID string) error
+	// ListDisputes, GetDisputeByID and ResolveDispute are for admins; the
+	// routes serving them check the caller is one
+	ListDisputes(ctx context.Context, status string, page, limit int) (*presenter.ListDisputesResponse, error)
+	GetDisputeByID(ctx context.Context, disputeID string) (*presenter.DisputeResponse, error)
+	ResolveDispute(ctx context.Context, disputeID string, req dtos.ResolveDisputeRequest) (*presenter.DisputeResponse, error)
 }
 
 type service struct {
-	repo         Repository
-	userClient   *http.Client
+	repo          Repository
+	userClient    *http.Client
 	productClient *http.Client
+	shippingRates map[string]money.Amount // Flat cost per seller of each shipping method
 }
 
-func NewService(repo Repository, userServiceURL, productServiceURL, internalSecret string) Service {
+func NewService(repo Repository, userServiceURL, productServiceURL, internalSecret string, shippingRates map[string]money.Amount) Service {
 	return &service{
 		repo:          repo,
 		userClient:    http.NewClient(userServiceURL, internalSecret),
 		productClient: http.NewClient(productServiceURL, internalSecret),
+		shippingRates: shippingRates,
 	}
 }
 
@@ -39,7 +99,7 @@ func (s *service) CreatePurchase(ctx context.Context, req dtos.CreatePurchaseReq
 	// Get authenticated user ID from context (set by gateway trust middleware)
 	userID, ok := ctx.Value("user_id").(string)
 	if !ok || userID == "" {
-		return nil, fmt.Errorf("user context not found")
+		return nil, ErrUnauthenticated
 	}
 
 	// Extract product IDs for batch fetching
@@ -51,25 +111,53 @@ func (s *service) CreatePurchase(ctx context.Context, req dtos.CreatePurchaseReq
 	// Fetch all products in parallel
 	products, err := s.productClient.GetProductDetails(ctx, productIDs, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch products: %w", err)
+		return nil, upstreamError(err, "failed to fetch products")
 	}
 
+	return s.placeOrder(ctx, userID, req, products, nil)
+}
+
+// placeOrder creates a purchase of the requested items, priced from the
+// fetched products. beforeCommit, when given, runs in the transaction that
+// writes the purchase, so its changes are committed or rolled back together.
+func (s *service) placeOrder(ctx context.Context, userID string, req dtos.CreatePurchaseRequest, products map[string]*presenter.ProductResponse, beforeCommit func(tx Repository) error) (*presenter.PurchaseResponse, error) {
 	// Validate all products exist and collect seller IDs
 	sellerIDs := make(map[string]bool)
+	currency := ""
 	for _, item := range req.PurchasedItems {
 		product, exists := products[item.ProductID]
 		if !exists {
-			return nil, fmt.Errorf("product %s not found", item.ProductID)
+			return nil, fmt.Errorf("product %s %w", item.ProductID, ErrNotFound)
 		}
 		sellerIDs[product.SellerID] = true
+
+		// A purchase is paid in a single currency
+		productCurrency := product.Currency
+		if productCurrency == "" {
+			productCurrency = money.DefaultCurrency
+		}
+		if currency == "" {
+			currency = productCurrency
+		} else if currency != productCurrency {
+			return nil, fmt.Errorf("%w: products must share a currency, got %s and %s", ErrInvalidInput, currency, productCurrency)
+		}
+	}
+
+	// Fetch all seller details in a single batch request
+	sellerIDList := make([]string, 0, len(sellerIDs))
+	for sellerID := range sellerIDs {
+		sellerIDList = append(sellerIDList, sellerID)
+	}
+	users, err := s.userClient.GetUserDetails(ctx, sellerIDList, userID)
+	if err != nil {
+		return nil, upstreamError(err, "failed to fetch sellers")
 	}
 
-	// Fetch seller details in parallel
 	sellerDetails := make(map[string]*presenter.SellerResponse)
 	for sellerID := range sellerIDs {
-		user, err := s.userClient.GetUserDetail(ctx, sellerID, userID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to fetch seller %s: %w", sellerID, err)
+		user, exists := users[sellerID]
+		if !exists {
+			return nil, fmt.Errorf("seller %s %w", sellerID, ErrNotFound)
 		}
 		// Convert ExternalUserResponse to SellerResponse
 		sellerDetails[sellerID] = &presenter.SellerResponse{
@@ -80,47 +168,92 @@ func (s *service) CreatePurchase(ctx context.Context, req dtos.CreatePurchaseReq
 		}
 	}
 
-	// Create purchase entity
-	purchase := &entities.Purchase{
-		UserID: uuid.MustParse(userID),
+	// Create purchase entity; the ID is allocated up front so items and sender
+	// can reference it before anything is written
+	purchaseID, err := uuid.NewV7()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate purchase ID: %w", err)
 	}
-	if err := s.repo.CreatePurchase(ctx, purchase); err != nil {
-		return nil, fmt.Errorf("failed to create purchase: %w", err)
+	purchase := &entities.Purchase{
+		ID:       purchaseID,
+		UserID:   uuid.MustParse(userID),
+		Status:   entities.PurchaseStatusPendingPayment,
+		Currency: currency,
 	}
 
 	// Create purchase items with copied product information
 	var purchaseItems []*entities.PurchaseItem
-	var totalPrice float64
-	sellerTotals := make(map[string]float64)
+	var totalPrice money.Amount
+	sellerTotals := make(map[string]money.Amount)
 
 	for _, item := range req.PurchasedItems {
 		product := products[item.ProductID]
-		
+		stockSnapshot := product.Qty
+		lineTotal := product.Price.Mul(item.Qty)
+
 		// Copy product information (source of truth)
 		purchaseItem := &entities.PurchaseItem{
-			PurchaseID:        purchase.ID,
-			ProductID:         product.ID,
-			Name:              product.Name,
-			Category:          product.Category,
-			Qty:               product.Qty, // Original quantity before purchase
-			Price:             product.Price,
-			SKU:               product.SKU,
-			FileID:            product.FileID,
-			FileURI:           product.FileURI,
-			FileThumbnailURI:  product.FileThumbnailURI,
+			PurchaseID:       purchase.ID,
+			ProductID:        product.ID,
+			SellerID:         uuid.MustParse(product.SellerID),
+			Name:             product.Name,
+			Category:         product.Category,
+			Qty:              item.Qty,
+			Price:            product.Price,
+			LineTotal:        lineTotal,
+			StockSnapshot:    &stockSnapshot,
+			SKU:              product.SKU,
+			FileID:           product.FileID,
+			FileURI:          product.FileURI,
+			FileThumbnailURI: product.FileThumbnailURI,
 		}
 
 		purchaseItems = append(purchaseItems, purchaseItem)
-		
+
 		// Calculate totals
-		itemTotal := product.Price * float64(item.Qty)
-		totalPrice += itemTotal
-		sellerTotals[product.SellerID] += itemTotal
+		totalPrice = totalPrice.Add(lineTotal)
+		sellerTotals[product.SellerID] = sellerTotals[product.SellerID].Add(lineTotal)
+	}
+	// A voucher is checked against the current totals here and again under a
+	// lock when the purchase is written
+	var voucher *entities.Voucher
+	discounts := make(map[string]money.Amount)
+	if req.VoucherCode != "" {
+		if voucher, err = getVoucherByCode(ctx, s.repo, req.VoucherCode); err != nil {
+			return nil, err
+		}
+		if discounts, err = voucherDiscount(voucher, currency, sellerTotals, time.Now()); err != nil {
+			return nil, err
+		}
+		if err := checkVoucherUsage(ctx, s.repo, voucher, userID); err != nil {
+			return nil, err
+		}
+		purchase.VoucherCode = voucher.Code
+		for _, discount := range discounts {
+			purchase.Discount = purchase.Discount.Add(discount)
+		}
 	}
+	purchase.TotalPrice = totalPrice.Sub(purchase.Discount)
 
-	// Save purchase items
-	if err := s.repo.CreatePurchaseItems(ctx, purchaseItems); err != nil {
-		return nil, fmt.Errorf("failed to create purchase items: %w", err)
+	// Purchases with a shipping address get a shipment from every seller,
+	// paid to the seller on top of their items
+	var shippingAddress *entities.PurchaseShippingAddress
+	var shipments []*entities.PurchaseShipment
+	shippingCosts := make(map[string]money.Amount)
+	if req.ShippingAddressID == "" && len(req.Shipping) > 0 {
+		return nil, fmt.Errorf("%w: shippingAddressId is required to pick a shipping method", ErrInvalidInput)
+	}
+	if req.ShippingAddressID != "" {
+		if shipments, err = s.planShipments(purchase, sellerIDs, req.Shipping); err != nil {
+			return nil, err
+		}
+		if shippingAddress, err = s.fetchShippingAddress(ctx, purchase, req.ShippingAddressID, userID); err != nil {
+			return nil, err
+		}
+		for _, shipment := range shipments {
+			shippingCosts[shipment.SellerID.String()] = shipment.Cost
+		}
+		purchase.TotalPrice = purchase.TotalPrice.Add(shippingCost(shipments))
 	}
 
 	// Create purchase sender
@@ -131,19 +264,20 @@ func (s *service) CreatePurchase(ctx context.Context, req dtos.CreatePurchaseReq
 		SenderContactDetail: req.SenderContactDetail,
 	}
 
-	if err := s.repo.CreatePurchaseSender(ctx, sender); err != nil {
-		return nil, fmt.Errorf("failed to create purchase sender: %w", err)
-	}
-
-	// Build payment details
-	var paymentDetails []presenter.PaymentDetail
+	// Snapshot each seller's bank account and subtotal so buyers keep seeing
+	// who to pay even if a seller later changes their bank details
+	var paymentDetails []*entities.PurchasePaymentDetail
 	for sellerID, total := range sellerTotals {
 		seller := sellerDetails[sellerID]
-		paymentDetails = append(paymentDetails, presenter.PaymentDetail{
+		paymentDetails = append(paymentDetails, &entities.PurchasePaymentDetail{
+			PurchaseID:        purchase.ID,
+			SellerID:          uuid.MustParse(sellerID),
 			BankAccountName:   seller.BankAccountName,
 			BankAccountHolder: seller.BankAccountHolder,
 			BankAccountNumber: seller.BankAccountNumber,
-			TotalPrice:        total,
+			TotalPrice:        total.Sub(discounts[sellerID]).Add(shippingCosts[sellerID]),
+			Discount:          discounts[sellerID],
+			Status:            entities.PurchaseStatusPendingPayment,
 		})
 	}
 
@@ -152,29 +286,66 @@ func (s *service) CreatePurchase(ctx context.Context, req dtos.CreatePurchaseReq
 		return paymentDetails[i].BankAccountName < paymentDetails[j].BankAccountName
 	})
 
-	// Build response
-	var purchasedItems []presenter.PurchaseItemResponse
-	for _, item := range purchaseItems {
-		purchasedItems = append(purchasedItems, presenter.PurchaseItemResponse{
-			ProductID:        item.ProductID,
-			Name:             item.Name,
-			Category:         item.Category,
-			Qty:              item.Qty,
-			Price:            item.Price,
-			SKU:              item.SKU,
-			FileID:           item.FileID,
-			FileURI:          item.FileURI,
-			FileThumbnailURI: item.FileThumbnailURI,
-			CreatedAt:        item.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:        item.UpdatedAt.Format(time.RFC3339),
-		})
+	// Hold stock for the ordered quantities before anything is written
+	quantities := make(map[string]int)
+	productIDs := make([]string, 0, len(req.PurchasedItems))
+	for _, item := range req.PurchasedItems {
+		quantities[item.ProductID] += item.Qty
+		productIDs = append(productIDs, item.ProductID)
+	}
+	if err := s.reserveStock(ctx, purchase.ID.String(), quantities, userID); err != nil {
+		return nil, err
+	}
+
+	// Persist the whole order atomically so a failure never leaves a purchase
+	// without its items or sender
+	if err := s.repo.WithTransaction(ctx, func(tx Repository) error {
+		if err := tx.CreatePurchase(ctx, purchase); err != nil {
+			return fmt.Errorf("failed to create purchase: %w", err)
+		}
+		if err := tx.CreatePurchaseItems(ctx, purchaseItems); err != nil {
+			return fmt.Errorf("failed to create purchase items: %w", err)
+		}
+		if err := tx.CreatePurchaseSender(ctx, sender); err != nil {
+			return fmt.Errorf("failed to create purchase sender: %w", err)
+		}
+		if err := tx.CreatePurchasePaymentDetails(ctx, paymentDetails); err != nil {
+			return fmt.Errorf("failed to create purchase payment details: %w", err)
+		}
+		if shippingAddress != nil {
+			if err := tx.CreatePurchaseShippingAddress(ctx, shippingAddress); err != nil {
+				return fmt.Errorf("failed to create purchase shipping address: %w", err)
+			}
+			if err := tx.CreatePurchaseShipments(ctx, shipments); err != nil {
+				return fmt.Errorf("failed to create purchase shipments: %w", err)
+			}
+		}
+		if voucher != nil {
+			if err := redeemVoucher(ctx, tx, voucher.ID.String(), purchase, sellerTotals); err != nil {
+				return err
+			}
+		}
+		if beforeCommit != nil {
+			return beforeCommit(tx)
+		}
+		return nil
+	}); err != nil {
+		s.releaseStock(ctx, purchase.ID.String(), productIDs, userID)
+		return nil, err
 	}
 
 	return &presenter.PurchaseResponse{
-		PurchaseID:     purchase.ID.String(),
-		PurchasedItems: purchasedItems,
-		TotalPrice:     totalPrice,
-		PaymentDetails: paymentDetails,
+		PurchaseID:      purchase.ID.String(),
+		Status:          string(purchase.Status),
+		PurchasedItems:  purchaseItemResponses(purchaseItems),
+		TotalPrice:      purchase.TotalPrice,
+		Discount:        purchase.Discount,
+		VoucherCode:     purchase.VoucherCode,
+		ShippingCost:    shippingCost(shipments),
+		Currency:        purchase.Currency,
+		PaymentDetails:  paymentDetailResponses(paymentDetails),
+		ShippingAddress: shippingAddressResponse(shippingAddress),
+		Shipments:       shipmentResponses(shipments),
 	}, nil
 }
 
@@ -182,224 +353,346 @@ func (s *service) UploadPaymentProof(ctx context.Context, purchaseID string, req
 	// Get authenticated user ID from context
 	userID, ok := ctx.Value("user_id").(string)
 	if !ok || userID == "" {
-		return fmt.Errorf("user context not found")
-	}
-
-	// Validate purchase ID format
-	if _, err := uuid.Parse(purchaseID); err != nil {
-		return fmt.Errorf("invalid purchase ID format")
+		return ErrUnauthenticated
 	}
 
 	// Get purchase to verify it exists and belongs to the user
-	purchase, err := s.repo.GetPurchaseByID(ctx, purchaseID)
+	purchase, err := s.getBuyerPurchase(ctx, s.repo, purchaseID, userID)
 	if err != nil {
-		return fmt.Errorf("purchase not found: %w", err)
+		return err
 	}
 
-	// Verify the purchase belongs to the authenticated user
-	if purchase.UserID.String() != userID {
-		return fmt.Errorf("unauthorized: purchase does not belong to user")
+	// Payment proof can only be submitted while the purchase awaits payment
+	if !CanTransition(purchase.Status, entities.PurchaseStatusProofUploaded) {
+		return &InvalidTransitionError{From: purchase.Status, To: entities.PurchaseStatusProofUploaded}
 	}
 
-	// Get purchase items to know which products to decrease
-	_, err = s.repo.GetPurchaseItemsByPurchaseID(ctx, purchaseID)
+	// Stock stays reserved from checkout; it is committed once the payment
+	// is confirmed and released if the purchase is cancelled or expires
+
+	// Each proof is for the transfer to one seller; a purchase from a single
+	// seller leaves no choice
+	paymentDetails, err := s.repo.GetPurchasePaymentDetailsByPurchaseID(ctx, purchaseID)
 	if err != nil {
-		return fmt.Errorf("failed to get purchase items: %w", err)
+		return fmt.Errorf("failed to get purchase payment details: %w", err)
+	}
+	var detail *entities.PurchasePaymentDetail
+	switch {
+	case req.SellerID != "":
+		if detail = findPaymentDetail(paymentDetails, req.SellerID); detail == nil {
+			return fmt.Errorf("%w: seller %s is not part of this purchase", ErrInvalidInput, req.SellerID)
+		}
+	case len(paymentDetails) == 1:
+		detail = paymentDetails[0]
+	default:
+		return fmt.Errorf("%w: sellerId is required for purchases from several sellers", ErrInvalidInput)
+	}
+	if detail.Status == entities.PurchaseStatusConfirmed {
+		return fmt.Errorf("%w: payment to seller %s is already confirmed", ErrConflict, detail.SellerID)
 	}
 
-	// Decrease product quantities for each item
-	// for _, item := range purchaseItems {
-	// 	err := s.productClient.DecreaseProductQuantity(ctx, item.ProductID, item.Qty, userID)
-	// 	if err != nil {
-	// 		return fmt.Errorf("failed to decrease quantity for product %s: %w", item.ProductID, err)
-	// 	}
-	// }
+	// Only files the buyer uploaded themselves can serve as proof
+	fileIDs := uniqueStrings(req.FileIds)
+	if err := s.verifyUserFiles(ctx, fileIDs, userID, "payment proof"); err != nil {
+		return err
+	}
 
-	// Convert file IDs to JSON string
-	fileIdsJSON, err := json.Marshal(req.FileIds)
-	if err != nil {
-		return fmt.Errorf("failed to marshal file IDs: %w", err)
+	sellerID := detail.SellerID.String()
+	proofs := make([]*entities.PurchasePaymentProof, 0, len(fileIDs))
+	for _, fileID := range fileIDs {
+		proofs = append(proofs, &entities.PurchasePaymentProof{
+			PurchaseID: purchase.ID,
+			SellerID:   &detail.SellerID,
+			FileID:     uuid.MustParse(fileID),
+			Status:     entities.PaymentProofStatusPending,
+		})
 	}
 
-	// Update purchase with payment proof
-	if err := s.repo.UpdatePurchasePaymentProof(ctx, purchaseID, string(fileIdsJSON)); err != nil {
-		return fmt.Errorf("failed to update purchase with payment proof: %w", err)
+	// Mark the payment to the seller as uploaded, replacing proofs still
+	// awaiting review, then let the purchase status follow
+	return s.repo.WithTransaction(ctx, func(tx Repository) error {
+		purchase, err := lockPayablePurchase(ctx, tx, purchaseID, entities.PurchaseStatusProofUploaded)
+		if err != nil {
+			return err
+		}
+
+		if err := tx.UpdatePaymentDetailStatus(ctx, purchaseID, sellerID, detail.Status, entities.PurchaseStatusProofUploaded, map[string]interface{}{
+			"proof_uploaded_at": time.Now(),
+			"status_reason":     "",
+		}); err != nil {
+			return fmt.Errorf("failed to update payment detail with payment proof: %w", err)
+		}
+		if err := tx.UpdatePaymentProofStatus(ctx, purchaseID, sellerID, entities.PaymentProofStatusPending, entities.PaymentProofStatusReplaced, nil); err != nil {
+			return fmt.Errorf("failed to replace payment proofs: %w", err)
+		}
+		if err := tx.CreatePaymentProofs(ctx, proofs); err != nil {
+			return fmt.Errorf("failed to create payment proofs: %w", err)
+		}
+		return s.syncPurchaseStatus(ctx, tx, purchase, nil)
+	})
+}
+
+// verifyUserFiles checks with the file store that every file exists and was
+// uploaded by the user. Kind names the files in error messages.
+func (s *service) verifyUserFiles(ctx context.Context, fileIDs []string, userID, kind string) error {
+	files, err := s.userClient.GetFileDetails(ctx, fileIDs, userID)
+	if err != nil {
+		return upstreamError(err, "failed to verify "+kind+" files")
 	}
 
+	for _, fileID := range fileIDs {
+		file, ok := files[fileID]
+		if !ok {
+			return fmt.Errorf("%w: %s file %s does not exist", ErrInvalidInput, kind, fileID)
+		}
+		if file.UserID != userID {
+			return fmt.Errorf("%w: %s file %s does not belong to user", ErrForbidden, kind, fileID)
+		}
+	}
 	return nil
 }
 
+// maxFileBatch is the most files the user service looks up in one request
+const maxFileBatch = 100
+
+// getFiles looks up files in the user service, which owns the file store,
+// keyed by file ID. Files that no longer exist are left out. The files only
+// fill in URIs for display, so a failed lookup is logged and the files it
+// asked for are left out too rather than failing the read.
+func (s *service) getFiles(ctx context.Context, fileIDs []string) map[string]*presenter.ExternalFileResponse {
+	userID, _ := ctx.Value("user_id").(string)
+	fileIDs = uniqueStrings(fileIDs)
+
+	files := make(map[string]*presenter.ExternalFileResponse, len(fileIDs))
+	for start := 0; start < len(fileIDs); start += maxFileBatch {
+		batch, err := s.userClient.GetFileDetails(ctx, fileIDs[start:min(start+maxFileBatch, len(fileIDs))], userID)
+		if err != nil {
+			log.Printf("failed to get files: %v", err)
+			continue
+		}
+		maps.Copy(files, batch)
+	}
+	return files
+}
+
+func (s *service) GetExistingPurchaseIDs(ctx context.Context, req dtos.PurchaseIDsRequest) (*presenter.PurchaseIDsResponse, error) {
+	ids, err := s.repo.GetExistingPurchaseIDs(ctx, req.PurchaseIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get purchases: %w", err)
+	}
+	return &presenter.PurchaseIDsResponse{PurchaseIDs: ids}, nil
+}
+
 func (s *service) GetPurchaseByID(ctx context.Context, purchaseID string) (*presenter.GetPurchaseResponse, error) {
 	// Get authenticated user ID from context
 	userID, ok := ctx.Value("user_id").(string)
 	if !ok || userID == "" {
-		return nil, fmt.Errorf("user context not found")
+		return nil, ErrUnauthenticated
 	}
 
-	// Validate purchase ID format
-	if _, err := uuid.Parse(purchaseID); err != nil {
-		return nil, fmt.Errorf("invalid purchase ID format")
+	// Get purchase and verify it belongs to the authenticated user
+	purchase, err := s.getBuyerPurchase(ctx, s.repo, purchaseID, userID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Get purchase
-	purchase, err := s.repo.GetPurchaseByID(ctx, purchaseID)
+	details, err := s.loadPurchaseDetails(ctx, []*entities.Purchase{purchase})
 	if err != nil {
-		return nil, fmt.Errorf("purchase not found: %w", err)
+		return nil, err
 	}
 
-	// Verify the purchase belongs to the authenticated user
-	if purchase.UserID.String() != userID {
-		return nil, fmt.Errorf("unauthorized: purchase does not belong to user")
+	response := getPurchaseResponse(purchase, details)
+	return &response, nil
+}
+
+func (s *service) ListPurchases(ctx context.Context, filter dtos.ListPurchasesFilter) (*presenter.ListPurchasesResponse, error) {
+	// Get authenticated user ID from context
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	// Set default page size
+	if filter.Limit <= 0 || filter.Limit > 100 {
+		filter.Limit = 10
+	}
+
+	if filter.Status != "" && !IsValidStatus(filter.Status) {
+		return nil, fmt.Errorf("%w: unknown status %s", ErrInvalidInput, filter.Status)
+	}
+
+	var after *cursor
+	if filter.Cursor != "" {
+		decoded, err := decodeCursor(filter.Cursor)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidInput, err)
+		}
+		after = decoded
 	}
 
-	// Get purchase items
-	purchaseItems, err := s.repo.GetPurchaseItemsByPurchaseID(ctx, purchaseID)
+	// Fetch one extra row to know whether another page follows
+	purchases, err := s.repo.GetPurchasesByUserID(ctx, userID, filter, after, filter.Limit+1)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get purchase items: %w", err)
+		return nil, fmt.Errorf("failed to get purchases: %w", err)
 	}
 
-	// Get purchase sender
-	sender, err := s.repo.GetPurchaseSenderByPurchaseID(ctx, purchaseID)
+	var nextCursor string
+	if len(purchases) > filter.Limit {
+		purchases = purchases[:filter.Limit]
+		last := purchases[len(purchases)-1]
+		nextCursor = encodeCursor(cursor{CreatedAt: last.CreatedAt, ID: last.ID})
+	}
+
+	// Load items, senders and payment details for the whole page at once
+	details, err := s.loadPurchaseDetails(ctx, purchases)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get purchase sender: %w", err)
-	}
-
-	// Parse payment proof IDs
-	var paymentProofIds []string
-	if purchase.PaymentProofIds != "" {
-		if err := json.Unmarshal([]byte(purchase.PaymentProofIds), &paymentProofIds); err != nil {
-			// If parsing fails, just use empty array
-			paymentProofIds = []string{}
-		}
-	}
-
-	// Build purchase items response
-	var purchasedItems []presenter.PurchaseItemResponse
-	var totalPrice float64
-	for _, item := range purchaseItems {
-		purchasedItems = append(purchasedItems, presenter.PurchaseItemResponse{
-			ProductID:         item.ProductID,
-			Name:              item.Name,
-			Category:          item.Category,
-			Qty:               item.Qty,
-			Price:             item.Price,
-			SKU:               item.SKU,
-			FileID:            item.FileID,
-			FileURI:           item.FileURI,
-			FileThumbnailURI:  item.FileThumbnailURI,
-			CreatedAt:         item.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:         item.UpdatedAt.Format(time.RFC3339),
-		})
-		totalPrice += item.Price * float64(item.Qty)
+		return nil, err
 	}
 
-	// Build sender info
-	senderInfo := presenter.SenderInfo{
-		SenderName:          sender.SenderName,
-		SenderContactType:   sender.SenderContactType,
-		SenderContactDetail: sender.SenderContactDetail,
+	purchaseResponses := make([]presenter.GetPurchaseResponse, 0, len(purchases))
+	for _, purchase := range purchases {
+		purchaseResponses = append(purchaseResponses, getPurchaseResponse(purchase, details))
 	}
 
-	return &presenter.GetPurchaseResponse{
-		PurchaseID:      purchase.ID.String(),
-		UserID:          purchase.UserID.String(),
-		PaymentProofIds: paymentProofIds,
-		PurchasedItems:  purchasedItems,
-		TotalPrice:      totalPrice,
-		PaymentDetails:  []presenter.PaymentDetail{}, // This would need to be fetched from external services
-		SenderInfo:      senderInfo,
-		CreatedAt:       purchase.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:       purchase.UpdatedAt.Format(time.RFC3339),
+	return &presenter.ListPurchasesResponse{
+		Purchases:  purchaseResponses,
+		NextCursor: nextCursor,
+		Limit:      filter.Limit,
 	}, nil
 }
 
-func (s *service) ListPurchases(ctx context.Context, page, limit int) (*presenter.ListPurchasesResponse, error) {
-	// Get authenticated user ID from context
-	userID, ok := ctx.Value("user_id").(string)
-	if !ok || userID == "" {
-		return nil, fmt.Errorf("user context not found")
+// getBuyerPurchase loads a purchase, verifying it was made by the given user
+func (s *service) getBuyerPurchase(ctx context.Context, repo Repository, purchaseID, userID string) (*entities.Purchase, error) {
+	purchase, err := getPurchase(ctx, repo, purchaseID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Set default pagination values
-	if page <= 0 {
-		page = 1
+	if purchase.UserID.String() != userID {
+		return nil, fmt.Errorf("%w: purchase does not belong to user", ErrForbidden)
 	}
-	if limit <= 0 || limit > 100 {
-		limit = 10
+	return purchase, nil
+}
+
+// getPurchase loads a purchase, reporting malformed and unknown IDs as not found
+func getPurchase(ctx context.Context, repo Repository, purchaseID string) (*entities.Purchase, error) {
+	if _, err := uuid.Parse(purchaseID); err != nil {
+		return nil, ErrPurchaseNotFound
 	}
 
-	// Get purchases from repository
-	purchases, total, err := s.repo.GetPurchasesByUserID(ctx, userID, page, limit)
+	purchase, err := repo.GetPurchaseByID(ctx, purchaseID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrPurchaseNotFound
+	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get purchases: %w", err)
+		return nil, fmt.Errorf("failed to get purchase: %w", err)
 	}
+	return purchase, nil
+}
 
-	// Convert to response DTOs
-	var purchaseResponses []presenter.GetPurchaseResponse
-	for _, purchase := range purchases {
-		// Get purchase items
-		purchaseItems, err := s.repo.GetPurchaseItemsByPurchaseID(ctx, purchase.ID.String())
-		if err != nil {
-			continue // Skip this purchase if we can't get items
-		}
+// transitionStatus validates and persists a status change, stamping the time the
+// new status was entered unless the caller sets that column itself. Extra column
+// updates are applied in the same statement.
+func (s *service) transitionStatus(ctx context.Context, repo Repository, purchase *entities.Purchase, to entities.PurchaseStatus, fields map[string]interface{}) error {
+	if !CanTransition(purchase.Status, to) {
+		return &InvalidTransitionError{From: purchase.Status, To: to}
+	}
+	return s.setStatus(ctx, repo, purchase, to, fields)
+}
 
-		// Get purchase sender
-		sender, err := s.repo.GetPurchaseSenderByPurchaseID(ctx, purchase.ID.String())
-		if err != nil {
-			continue // Skip this purchase if we can't get sender
+// setStatus persists a status change like transitionStatus, without checking
+// that it is a legal transition. Only admin dispute resolutions may skip it.
+func (s *service) setStatus(ctx context.Context, repo Repository, purchase *entities.Purchase, to entities.PurchaseStatus, fields map[string]interface{}) error {
+	if fields == nil {
+		fields = make(map[string]interface{})
+	}
+	now := time.Now()
+	if column := statusTimestampColumn(to); column != "" {
+		if _, ok := fields[column]; !ok {
+			fields[column] = now
 		}
+	}
+	// A reason only describes the transition it came with
+	if _, ok := fields["status_reason"]; !ok {
+		fields["status_reason"] = ""
+	}
 
-		// Parse payment proof IDs
-		var paymentProofIds []string
-		if purchase.PaymentProofIds != "" {
-			if err := json.Unmarshal([]byte(purchase.PaymentProofIds), &paymentProofIds); err != nil {
-				paymentProofIds = []string{}
-			}
-		}
+	if err := repo.UpdatePurchaseStatus(ctx, purchase.ID.String(), purchase.Status, to, fields); err != nil {
+		return err
+	}
+
+	purchase.Status = to
+	return nil
+}
 
-		// Build purchase items response
-		var purchasedItems []presenter.PurchaseItemResponse
-		var totalPrice float64
-		for _, item := range purchaseItems {
-			purchasedItems = append(purchasedItems, presenter.PurchaseItemResponse{
-				ProductID:         item.ProductID,
-				Name:              item.Name,
-				Category:          item.Category,
-				Qty:               item.Qty,
-				Price:             item.Price,
-				SKU:               item.SKU,
-				FileID:            item.FileID,
-				FileURI:           item.FileURI,
-				FileThumbnailURI:  item.FileThumbnailURI,
-				CreatedAt:         item.CreatedAt.Format(time.RFC3339),
-				UpdatedAt:         item.UpdatedAt.Format(time.RFC3339),
-			})
-			totalPrice += item.Price * float64(item.Qty)
-		}
-
-		// Build sender info
-		senderInfo := presenter.SenderInfo{
-			SenderName:          sender.SenderName,
-			SenderContactType:   sender.SenderContactType,
-			SenderContactDetail: sender.SenderContactDetail,
-		}
-
-		purchaseResponses = append(purchaseResponses, presenter.GetPurchaseResponse{
-			PurchaseID:      purchase.ID.String(),
-			PaymentProofIds: paymentProofIds,
-			PurchasedItems:  purchasedItems,
-			TotalPrice:      totalPrice,
-			PaymentDetails:  []presenter.PaymentDetail{}, // This would need to be fetched from external services
-			SenderInfo:      senderInfo,
-			CreatedAt:       purchase.CreatedAt.Format(time.RFC3339),
-			UpdatedAt:       purchase.UpdatedAt.Format(time.RFC3339),
+// purchaseItemResponses converts stored purchase items to their API representation
+func purchaseItemResponses(items []*entities.PurchaseItem) []presenter.PurchaseItemResponse {
+	responses := make([]presenter.PurchaseItemResponse, 0, len(items))
+	for _, item := range items {
+		responses = append(responses, presenter.PurchaseItemResponse{
+			ProductID:        item.ProductID,
+			Name:             item.Name,
+			Category:         item.Category,
+			Qty:              item.Qty,
+			Price:            item.Price,
+			LineTotal:        item.LineTotal,
+			SKU:              item.SKU,
+			FileID:           item.FileID,
+			FileURI:          item.FileURI,
+			FileThumbnailURI: item.FileThumbnailURI,
+			CreatedAt:        item.CreatedAt.Format(time.RFC3339),
+			UpdatedAt:        item.UpdatedAt.Format(time.RFC3339),
 		})
 	}
+	return responses
+}
 
-	return &presenter.ListPurchasesResponse{
-		Purchases: purchaseResponses,
-		Total:     int(total),
-		Page:      page,
-		Limit:     limit,
-	}, nil
+// paymentDetailResponses converts stored payment details to their API representation
+func paymentDetailResponses(details []*entities.PurchasePaymentDetail) []presenter.PaymentDetail {
+	responses := make([]presenter.PaymentDetail, 0, len(details))
+	for _, detail := range details {
+		response := presenter.PaymentDetail{
+			SellerID:          detail.SellerID.String(),
+			BankAccountName:   detail.BankAccountName,
+			BankAccountHolder: detail.BankAccountHolder,
+			BankAccountNumber: detail.BankAccountNumber,
+			TotalPrice:        detail.TotalPrice,
+			Discount:          detail.Discount,
+			Status:            string(detail.Status),
+			StatusReason:      detail.StatusReason,
+			RefundStatus:      string(detail.RefundStatus),
+			RefundReason:      detail.RefundReason,
+		}
+		if detail.ProofUploadedAt != nil {
+			proofUploadedAt := detail.ProofUploadedAt.Format(time.RFC3339)
+			response.ProofUploadedAt = &proofUploadedAt
+		}
+		if detail.ConfirmedAt != nil {
+			confirmedAt := detail.ConfirmedAt.Format(time.RFC3339)
+			response.ConfirmedAt = &confirmedAt
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+// statusTimestamps formats the lifecycle timestamps of a purchase
+func statusTimestamps(purchase *entities.Purchase) presenter.StatusTimestamps {
+	format := func(t *time.Time) *string {
+		if t == nil {
+			return nil
+		}
+		formatted := t.Format(time.RFC3339)
+		return &formatted
+	}
+
+	return presenter.StatusTimestamps{
+		ProofUploadedAt:   format(purchase.ProofUploadedAt),
+		ConfirmedAt:       format(purchase.ConfirmedAt),
+		CompletedAt:       format(purchase.CompletedAt),
+		CancelledAt:       format(purchase.CancelledAt),
+		ExpiredAt:         format(purchase.ExpiredAt),
+		RefundRequestedAt: format(purchase.RefundRequestedAt),
+		RefundedAt:        format(purchase.RefundedAt),
+	}
 }
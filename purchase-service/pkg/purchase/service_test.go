@@ -0,0 +1,220 @@
+package purchase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	nethttp "net/http"
+	"net/http/httptest"
+	"purchase-service/api/presenter"
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/entities"
+	"purchase-service/pkg/http"
+	"purchase-service/pkg/money"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+var errInjected = errors.New("injected failure")
+
+// failingRepository fails one of the purchase writes made inside
+// WithTransaction and passes everything else on to the wrapped repository
+type failingRepository struct {
+	Repository
+	failOn string // Name of the write method that fails
+}
+
+func (r *failingRepository) WithTransaction(ctx context.Context, fn func(tx Repository) error) error {
+	return r.Repository.WithTransaction(ctx, func(tx Repository) error {
+		return fn(&failingRepository{Repository: tx, failOn: r.failOn})
+	})
+}
+
+func (r *failingRepository) CreatePurchaseItems(ctx context.Context, items []*entities.PurchaseItem) error {
+	if r.failOn == "CreatePurchaseItems" {
+		return errInjected
+	}
+	return r.Repository.CreatePurchaseItems(ctx, items)
+}
+
+func (r *failingRepository) CreatePurchasePaymentDetails(ctx context.Context, details []*entities.PurchasePaymentDetail) error {
+	if r.failOn == "CreatePurchasePaymentDetails" {
+		return errInjected
+	}
+	return r.Repository.CreatePurchasePaymentDetails(ctx, details)
+}
+
+// fakeProductService answers reservation calls and records the products of each
+type fakeProductService struct {
+	mu        sync.Mutex
+	reserved  []string
+	committed []string
+	released  []string
+}
+
+func (f *fakeProductService) ServeHTTP(w nethttp.ResponseWriter, r *nethttp.Request) {
+	// Paths are /product/:productId/reserve and /product/:productId/reservations/:purchaseId/{commit,release}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	if len(parts) < 3 || parts[0] != "product" {
+		nethttp.NotFound(w, r)
+		return
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch parts[len(parts)-1] {
+	case "reserve":
+		f.reserved = append(f.reserved, parts[1])
+	case "commit":
+		f.committed = append(f.committed, parts[1])
+	case "release":
+		f.released = append(f.released, parts[1])
+	default:
+		nethttp.NotFound(w, r)
+		return
+	}
+	w.WriteHeader(nethttp.StatusOK)
+}
+
+func newUserService(t *testing.T, sellers ...string) *httptest.Server {
+	t.Helper()
+	return httptest.NewServer(nethttp.HandlerFunc(func(w nethttp.ResponseWriter, r *nethttp.Request) {
+		if r.URL.Path != "/user/batch" {
+			nethttp.NotFound(w, r)
+			return
+		}
+		var response presenter.ExternalUsersResponse
+		for _, sellerID := range sellers {
+			response.Users = append(response.Users, presenter.ExternalUserResponse{
+				ID:                sellerID,
+				BankAccountName:   "Bank " + sellerID[:8],
+				BankAccountHolder: "Seller",
+				BankAccountNumber: "1234567890",
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(response); err != nil {
+			t.Errorf("failed to encode users: %v", err)
+		}
+	}))
+}
+
+// countRows counts the rows of the purchase tables written by placeOrder
+func countRows(t *testing.T, db *gorm.DB) map[string]int64 {
+	t.Helper()
+	counts := make(map[string]int64)
+	for table, model := range map[string]interface{}{
+		"purchases":       &entities.Purchase{},
+		"items":           &entities.PurchaseItem{},
+		"senders":         &entities.PurchaseSender{},
+		"payment details": &entities.PurchasePaymentDetail{},
+	} {
+		var count int64
+		if err := db.Model(model).Count(&count).Error; err != nil {
+			t.Fatalf("failed to count %s: %v", table, err)
+		}
+		counts[table] = count
+	}
+	return counts
+}
+
+func TestPlaceOrderRollsBackOnFailedWrite(t *testing.T) {
+	db := openTestDB(t)
+
+	const (
+		buyerID   = "0199a3f2-0000-7000-8000-000000000001"
+		sellerA   = "0199a3f2-0000-7000-8000-0000000000a1"
+		sellerB   = "0199a3f2-0000-7000-8000-0000000000b1"
+		productA  = "0199a3f2-0000-7000-8000-00000000aa01"
+		productB  = "0199a3f2-0000-7000-8000-00000000bb01"
+		productB2 = "0199a3f2-0000-7000-8000-00000000bb02"
+	)
+	products := map[string]*presenter.ProductResponse{
+		productA:  {ID: productA, Name: "Keyboard", Category: "electronics", Qty: 10, Price: money.Amount(150000), SellerID: sellerA},
+		productB:  {ID: productB, Name: "Mouse", Category: "electronics", Qty: 5, Price: money.Amount(75000), SellerID: sellerB},
+		productB2: {ID: productB2, Name: "Mouse pad", Category: "accessories", Qty: 8, Price: money.Amount(20000), SellerID: sellerB},
+	}
+	req := dtos.CreatePurchaseRequest{
+		PurchasedItems: []dtos.PurchaseItemRequest{
+			{ProductID: productA, Qty: 1},
+			{ProductID: productB, Qty: 2},
+			{ProductID: productB2, Qty: 1},
+		},
+		SenderName:          "Jane Buyer",
+		SenderContactType:   "email",
+		SenderContactDetail: "jane@example.com",
+	}
+
+	// The successful order runs last, so the failed ones must leave no rows
+	tests := []struct {
+		name   string
+		failOn string
+	}{
+		{name: "item insert fails", failOn: "CreatePurchaseItems"},
+		{name: "payment detail insert fails", failOn: "CreatePurchasePaymentDetails"},
+		{name: "all writes succeed"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userService := newUserService(t, sellerA, sellerB)
+			defer userService.Close()
+			productHandler := &fakeProductService{}
+			productService := httptest.NewServer(productHandler)
+			defer productService.Close()
+
+			s := &service{
+				repo:          &failingRepository{Repository: NewGormRepository(db), failOn: tt.failOn},
+				userClient:    http.NewClient(userService.URL, "secret"),
+				productClient: http.NewClient(productService.URL, "secret"),
+			}
+
+			response, err := s.placeOrder(context.Background(), buyerID, req, products, nil)
+
+			counts := countRows(t, db)
+			if tt.failOn == "" {
+				if err != nil {
+					t.Fatalf("placeOrder() error = %v", err)
+				}
+				want := map[string]int64{"purchases": 1, "items": 3, "senders": 1, "payment details": 2}
+				if !reflect.DeepEqual(counts, want) {
+					t.Fatalf("committed %v, want %v", counts, want)
+				}
+				if response.TotalPrice != money.Amount(320000) {
+					t.Errorf("TotalPrice = %v, want 320000", response.TotalPrice)
+				}
+				if len(productHandler.released) != 0 {
+					t.Errorf("released %v, want no released reservations", productHandler.released)
+				}
+				return
+			}
+
+			if !errors.Is(err, errInjected) {
+				t.Fatalf("placeOrder() error = %v, want the injected failure", err)
+			}
+			for table, count := range counts {
+				if count != 0 {
+					t.Errorf("left %d %s behind, want none", count, table)
+				}
+			}
+
+			// Every reservation made for the order is handed back
+			released := make(map[string]bool)
+			for _, productID := range productHandler.released {
+				released[productID] = true
+			}
+			for _, productID := range productHandler.reserved {
+				if !released[productID] {
+					t.Errorf("reservation of product %s was not released", productID)
+				}
+			}
+			if len(productHandler.reserved) != len(products) {
+				t.Errorf("reserved %d products, want %d", len(productHandler.reserved), len(products))
+			}
+		})
+	}
+}
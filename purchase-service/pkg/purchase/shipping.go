@@ -0,0 +1,317 @@
+package purchase
+
+import (
+	"context"
+	"fmt"
+	"purchase-service/api/presenter"
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/entities"
+	"purchase-service/pkg/money"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultShippingMethod ships the items of sellers the buyer picked no method for
+const DefaultShippingMethod = "regular"
+
+// ParseShippingRates reads a list of shipping methods and their flat cost per
+// seller, such as "regular=15000,express=30000". Costs are in the default
+// currency.
+func ParseShippingRates(s string) (map[string]money.Amount, error) {
+	rates := make(map[string]money.Amount)
+	for _, entry := range strings.Split(s, ",") {
+		if strings.TrimSpace(entry) == "" {
+			continue
+		}
+		method, cost, ok := strings.Cut(entry, "=")
+		method = strings.ToLower(strings.TrimSpace(method))
+		if !ok || method == "" {
+			return nil, fmt.Errorf("shipping rate %q is not method=cost", entry)
+		}
+		amount, err := money.Parse(cost)
+		if err != nil {
+			return nil, fmt.Errorf("shipping rate of %s: %w", method, err)
+		}
+		if amount < 0 {
+			return nil, fmt.Errorf("shipping rate of %s is negative", method)
+		}
+		rates[method] = amount
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("no shipping rates configured")
+	}
+	return rates, nil
+}
+
+// planShipments picks a shipping method for every seller of a purchase and
+// prices it. Sellers the buyer did not pick a method for get the default one.
+func (s *service) planShipments(purchase *entities.Purchase, sellerIDs map[string]bool, choices []dtos.ShippingRequest) ([]*entities.PurchaseShipment, error) {
+	if purchase.Currency != money.DefaultCurrency {
+		return nil, fmt.Errorf("%w: shipping is only available for purchases in %s", ErrInvalidInput, money.DefaultCurrency)
+	}
+
+	methods := make(map[string]string, len(choices))
+	for _, choice := range choices {
+		if !sellerIDs[choice.SellerID] {
+			return nil, fmt.Errorf("%w: seller %s is not part of this purchase", ErrInvalidInput, choice.SellerID)
+		}
+		methods[choice.SellerID] = strings.ToLower(choice.Method)
+	}
+
+	shipments := make([]*entities.PurchaseShipment, 0, len(sellerIDs))
+	for sellerID := range sellerIDs {
+		method, ok := methods[sellerID]
+		if !ok {
+			method = DefaultShippingMethod
+		}
+		cost, ok := s.shippingRates[method]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown shipping method %s", ErrInvalidInput, method)
+		}
+		shipments = append(shipments, &entities.PurchaseShipment{
+			PurchaseID: purchase.ID,
+			SellerID:   uuid.MustParse(sellerID),
+			Method:     method,
+			Cost:       cost,
+			Status:     entities.ShipmentStatusPending,
+		})
+	}
+
+	// Sort shipments by seller for consistency
+	sort.Slice(shipments, func(i, j int) bool {
+		return shipments[i].SellerID.String() < shipments[j].SellerID.String()
+	})
+	return shipments, nil
+}
+
+// fetchShippingAddress snapshots an entry of the buyer's address book
+func (s *service) fetchShippingAddress(ctx context.Context, purchase *entities.Purchase, addressID, userID string) (*entities.PurchaseShippingAddress, error) {
+	address, err := s.userClient.GetAddressDetail(ctx, addressID, userID)
+	if err != nil {
+		return nil, upstreamError(err, "failed to fetch shipping address")
+	}
+	// Someone else's address is reported the same as a missing one
+	if address.UserID != userID {
+		return nil, fmt.Errorf("shipping address %s %w", addressID, ErrNotFound)
+	}
+
+	return &entities.PurchaseShippingAddress{
+		PurchaseID:    purchase.ID,
+		AddressID:     uuid.MustParse(addressID),
+		RecipientName: address.RecipientName,
+		Phone:         address.Phone,
+		AddressLine1:  address.AddressLine1,
+		AddressLine2:  address.AddressLine2,
+		City:          address.City,
+		Province:      address.Province,
+		PostalCode:    address.PostalCode,
+		Country:       address.Country,
+	}, nil
+}
+
+// ShipOrder records that the calling seller handed their items of a confirmed
+// purchase to the courier, and lets the buyer know how to track them
+func (s *service) ShipOrder(ctx context.Context, purchaseID string, req dtos.ShipOrderRequest) error {
+	sellerID, ok := ctx.Value("user_id").(string)
+	if !ok || sellerID == "" {
+		return ErrUnauthenticated
+	}
+
+	return s.repo.WithTransaction(ctx, func(tx Repository) error {
+		if _, _, err := s.getSellerPurchase(ctx, tx, purchaseID, sellerID); err != nil {
+			return err
+		}
+
+		purchase, err := tx.LockPurchaseByID(ctx, purchaseID)
+		if err != nil {
+			return fmt.Errorf("failed to lock purchase: %w", err)
+		}
+		// Items are only shipped once every seller has been paid
+		if purchase.Status != entities.PurchaseStatusConfirmed {
+			return fmt.Errorf("%w: purchase is %s, only confirmed purchases can be shipped", ErrConflict, purchase.Status)
+		}
+
+		shipments, err := tx.LockShipmentsByPurchaseID(ctx, purchaseID)
+		if err != nil {
+			return fmt.Errorf("failed to lock shipments: %w", err)
+		}
+		shipment := findShipment(shipments, sellerID)
+		if shipment == nil {
+			return fmt.Errorf("%w: purchase has no shipment from seller %s", ErrNotFound, sellerID)
+		}
+		if shipment.Status != entities.ShipmentStatusPending {
+			return fmt.Errorf("%w: shipment is already %s", ErrConflict, shipment.Status)
+		}
+
+		if err := tx.UpdateShipmentStatus(ctx, purchaseID, sellerID, shipment.Status, entities.ShipmentStatusShipped, map[string]interface{}{
+			"tracking_number": req.TrackingNumber,
+			"shipped_at":      time.Now(),
+		}); err != nil {
+			return fmt.Errorf("failed to ship order: %w", err)
+		}
+
+		return tx.CreateNotification(ctx, &entities.PurchaseNotification{
+			UserID:     purchase.UserID,
+			PurchaseID: purchase.ID,
+			Type:       entities.NotificationOrderShipped,
+			Message:    fmt.Sprintf("Your order was shipped via %s, tracking number %s", shipment.Method, req.TrackingNumber),
+		})
+	})
+}
+
+// ConfirmReceipt records that the buyer received one seller's parcel and lets
+// the seller know. The purchase is completed once every parcel is received, or
+// right away when it has no parcels.
+func (s *service) ConfirmReceipt(ctx context.Context, purchaseID string, req dtos.ConfirmReceiptRequest) error {
+	userID, ok := ctx.Value("user_id").(string)
+	if !ok || userID == "" {
+		return ErrUnauthenticated
+	}
+
+	return s.repo.WithTransaction(ctx, func(tx Repository) error {
+		if _, err := s.getBuyerPurchase(ctx, tx, purchaseID, userID); err != nil {
+			return err
+		}
+
+		purchase, err := tx.LockPurchaseByID(ctx, purchaseID)
+		if err != nil {
+			return fmt.Errorf("failed to lock purchase: %w", err)
+		}
+		if purchase.Status != entities.PurchaseStatusConfirmed {
+			return fmt.Errorf("%w: purchase is %s, only confirmed purchases can be received", ErrConflict, purchase.Status)
+		}
+
+		// A purchase from a single seller leaves no choice of parcel. Parcels
+		// of sellers whose part was called off are never delivered.
+		locked, err := tx.LockShipmentsByPurchaseID(ctx, purchaseID)
+		if err != nil {
+			return fmt.Errorf("failed to lock shipments: %w", err)
+		}
+		shipments := make([]*entities.PurchaseShipment, 0, len(locked))
+		for _, shipment := range locked {
+			if shipment.Status != entities.ShipmentStatusCancelled {
+				shipments = append(shipments, shipment)
+			}
+		}
+		// Purchases checked out without a shipping address have no parcels
+		// to track, so the buyer's confirmation completes them outright
+		if len(shipments) == 0 {
+			if req.SellerID != "" {
+				return fmt.Errorf("%w: purchase has no shipment from seller %s", ErrInvalidInput, req.SellerID)
+			}
+			if err := s.transitionStatus(ctx, tx, purchase, entities.PurchaseStatusCompleted, nil); err != nil {
+				return fmt.Errorf("failed to update purchase status: %w", err)
+			}
+			items, err := tx.GetPurchaseItemsByPurchaseID(ctx, purchaseID)
+			if err != nil {
+				return fmt.Errorf("failed to get purchase items: %w", err)
+			}
+			return notifySellers(ctx, tx, purchase, items, entities.NotificationOrderReceived, "The buyer received your order")
+		}
+
+		var shipment *entities.PurchaseShipment
+		switch {
+		case req.SellerID != "":
+			if shipment = findShipment(shipments, req.SellerID); shipment == nil {
+				return fmt.Errorf("%w: purchase has no shipment from seller %s", ErrInvalidInput, req.SellerID)
+			}
+		case len(shipments) == 1:
+			shipment = shipments[0]
+		default:
+			return fmt.Errorf("%w: sellerId is required for purchases from several sellers", ErrInvalidInput)
+		}
+		if shipment.Status != entities.ShipmentStatusShipped {
+			return fmt.Errorf("%w: shipment is %s, only shipped parcels can be received", ErrConflict, shipment.Status)
+		}
+
+		if err := tx.UpdateShipmentStatus(ctx, purchaseID, shipment.SellerID.String(), shipment.Status, entities.ShipmentStatusReceived, map[string]interface{}{
+			"received_at": time.Now(),
+		}); err != nil {
+			return fmt.Errorf("failed to confirm receipt: %w", err)
+		}
+		shipment.Status = entities.ShipmentStatusReceived
+
+		if err := tx.CreateNotification(ctx, &entities.PurchaseNotification{
+			UserID:     shipment.SellerID,
+			PurchaseID: purchase.ID,
+			Type:       entities.NotificationOrderReceived,
+			Message:    "The buyer received your shipment",
+		}); err != nil {
+			return fmt.Errorf("failed to notify seller %s: %w", shipment.SellerID, err)
+		}
+
+		for _, other := range shipments {
+			if other.Status != entities.ShipmentStatusReceived {
+				return nil
+			}
+		}
+		if err := s.transitionStatus(ctx, tx, purchase, entities.PurchaseStatusCompleted, nil); err != nil {
+			return fmt.Errorf("failed to update purchase status: %w", err)
+		}
+		return nil
+	})
+}
+
+func findShipment(shipments []*entities.PurchaseShipment, sellerID string) *entities.PurchaseShipment {
+	for _, shipment := range shipments {
+		if shipment.SellerID.String() == sellerID {
+			return shipment
+		}
+	}
+	return nil
+}
+
+// shippingCost adds up the cost of all shipments of a purchase
+func shippingCost(shipments []*entities.PurchaseShipment) money.Amount {
+	var cost money.Amount
+	for _, shipment := range shipments {
+		cost = cost.Add(shipment.Cost)
+	}
+	return cost
+}
+
+// shippingAddressResponse converts a stored shipping address to its API representation
+func shippingAddressResponse(address *entities.PurchaseShippingAddress) *presenter.ShippingAddress {
+	if address == nil {
+		return nil
+	}
+
+	return &presenter.ShippingAddress{
+		AddressID:     address.AddressID.String(),
+		RecipientName: address.RecipientName,
+		Phone:         address.Phone,
+		AddressLine1:  address.AddressLine1,
+		AddressLine2:  address.AddressLine2,
+		City:          address.City,
+		Province:      address.Province,
+		PostalCode:    address.PostalCode,
+		Country:       address.Country,
+	}
+}
+
+// shipmentResponses converts stored shipments to their API representation
+func shipmentResponses(shipments []*entities.PurchaseShipment) []presenter.ShipmentResponse {
+	responses := make([]presenter.ShipmentResponse, 0, len(shipments))
+	for _, shipment := range shipments {
+		response := presenter.ShipmentResponse{
+			SellerID:       shipment.SellerID.String(),
+			Method:         shipment.Method,
+			Cost:           shipment.Cost,
+			TrackingNumber: shipment.TrackingNumber,
+			Status:         string(shipment.Status),
+		}
+		if shipment.ShippedAt != nil {
+			shippedAt := shipment.ShippedAt.Format(time.RFC3339)
+			response.ShippedAt = &shippedAt
+		}
+		if shipment.ReceivedAt != nil {
+			receivedAt := shipment.ReceivedAt.Format(time.RFC3339)
+			response.ReceivedAt = &receivedAt
+		}
+		responses = append(responses, response)
+	}
+	return responses
+}
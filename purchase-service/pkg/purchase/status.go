@@ -0,0 +1,102 @@
+package purchase
+
+import (
+	"fmt"
+	"purchase-service/pkg/entities"
+)
+
+// transitions lists, for every status, the statuses it may move to
+var transitions = map[entities.PurchaseStatus][]entities.PurchaseStatus{
+	entities.PurchaseStatusPendingPayment: {
+		entities.PurchaseStatusProofUploaded,
+		entities.PurchaseStatusConfirmed, // unpaid sellers called off, the rest confirmed
+		entities.PurchaseStatusCancelled,
+		entities.PurchaseStatusExpired,
+	},
+	entities.PurchaseStatusProofUploaded: {
+		entities.PurchaseStatusProofUploaded,  // buyer re-submits proof
+		entities.PurchaseStatusPendingPayment, // proof rejected
+		entities.PurchaseStatusConfirmed,
+		entities.PurchaseStatusCancelled,
+	},
+	entities.PurchaseStatusConfirmed: {
+		entities.PurchaseStatusCompleted,
+		entities.PurchaseStatusRefundRequested,
+	},
+	entities.PurchaseStatusRefundRequested: {
+		entities.PurchaseStatusRefunded,
+		entities.PurchaseStatusConfirmed, // refund rejected
+	},
+}
+
+// InvalidTransitionError is returned when a purchase cannot move to the requested status
+type InvalidTransitionError struct {
+	From entities.PurchaseStatus
+	To   entities.PurchaseStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("cannot change purchase status from %s to %s", e.From, e.To)
+}
+
+// StatusConflictError is returned when the purchase status changed concurrently
+type StatusConflictError struct {
+	PurchaseID string
+	Expected   entities.PurchaseStatus
+}
+
+func (e *StatusConflictError) Error() string {
+	return fmt.Sprintf("purchase %s is no longer %s", e.PurchaseID, e.Expected)
+}
+
+// CanTransition reports whether a purchase may move from one status to another
+func CanTransition(from, to entities.PurchaseStatus) bool {
+	for _, next := range transitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// IsFinalStatus reports whether a purchase in the given status can no longer change
+func IsFinalStatus(status entities.PurchaseStatus) bool {
+	return len(transitions[status]) == 0
+}
+
+// IsValidStatus reports whether the given string is a known purchase status
+func IsValidStatus(status string) bool {
+	switch entities.PurchaseStatus(status) {
+	case entities.PurchaseStatusPendingPayment,
+		entities.PurchaseStatusProofUploaded,
+		entities.PurchaseStatusConfirmed,
+		entities.PurchaseStatusCompleted,
+		entities.PurchaseStatusCancelled,
+		entities.PurchaseStatusExpired,
+		entities.PurchaseStatusRefundRequested,
+		entities.PurchaseStatusRefunded:
+		return true
+	}
+	return false
+}
+
+// statusTimestampColumn returns the column recording when a status was entered
+func statusTimestampColumn(status entities.PurchaseStatus) string {
+	switch status {
+	case entities.PurchaseStatusProofUploaded:
+		return "proof_uploaded_at"
+	case entities.PurchaseStatusConfirmed:
+		return "confirmed_at"
+	case entities.PurchaseStatusCompleted:
+		return "completed_at"
+	case entities.PurchaseStatusCancelled:
+		return "cancelled_at"
+	case entities.PurchaseStatusExpired:
+		return "expired_at"
+	case entities.PurchaseStatusRefundRequested:
+		return "refund_requested_at"
+	case entities.PurchaseStatusRefunded:
+		return "refunded_at"
+	}
+	return ""
+}
@@ -0,0 +1,65 @@
+package purchase
+
+import (
+	"purchase-service/pkg/entities"
+	"testing"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to entities.PurchaseStatus
+		want     bool
+	}{
+		{entities.PurchaseStatusPendingPayment, entities.PurchaseStatusProofUploaded, true},
+		{entities.PurchaseStatusPendingPayment, entities.PurchaseStatusConfirmed, true},
+		{entities.PurchaseStatusPendingPayment, entities.PurchaseStatusCancelled, true},
+		{entities.PurchaseStatusPendingPayment, entities.PurchaseStatusExpired, true},
+		{entities.PurchaseStatusPendingPayment, entities.PurchaseStatusCompleted, false},
+		{entities.PurchaseStatusPendingPayment, entities.PurchaseStatusRefundRequested, false},
+		{entities.PurchaseStatusProofUploaded, entities.PurchaseStatusProofUploaded, true},
+		{entities.PurchaseStatusProofUploaded, entities.PurchaseStatusPendingPayment, true},
+		{entities.PurchaseStatusProofUploaded, entities.PurchaseStatusConfirmed, true},
+		{entities.PurchaseStatusProofUploaded, entities.PurchaseStatusCancelled, true},
+		// Once a proof is in, the seller decides and the deadline no longer applies
+		{entities.PurchaseStatusProofUploaded, entities.PurchaseStatusExpired, false},
+		{entities.PurchaseStatusConfirmed, entities.PurchaseStatusCompleted, true},
+		{entities.PurchaseStatusConfirmed, entities.PurchaseStatusRefundRequested, true},
+		{entities.PurchaseStatusConfirmed, entities.PurchaseStatusCancelled, false},
+		{entities.PurchaseStatusConfirmed, entities.PurchaseStatusPendingPayment, false},
+		{entities.PurchaseStatusRefundRequested, entities.PurchaseStatusRefunded, true},
+		{entities.PurchaseStatusRefundRequested, entities.PurchaseStatusConfirmed, true},
+		{entities.PurchaseStatusRefundRequested, entities.PurchaseStatusCompleted, false},
+		{entities.PurchaseStatusExpired, entities.PurchaseStatusPendingPayment, false},
+		{entities.PurchaseStatusCancelled, entities.PurchaseStatusConfirmed, false},
+		{entities.PurchaseStatusCompleted, entities.PurchaseStatusRefundRequested, false},
+		{entities.PurchaseStatusRefunded, entities.PurchaseStatusConfirmed, false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestIsFinalStatus(t *testing.T) {
+	tests := []struct {
+		status entities.PurchaseStatus
+		want   bool
+	}{
+		{entities.PurchaseStatusPendingPayment, false},
+		{entities.PurchaseStatusProofUploaded, false},
+		{entities.PurchaseStatusConfirmed, false},
+		{entities.PurchaseStatusRefundRequested, false},
+		{entities.PurchaseStatusCompleted, true},
+		{entities.PurchaseStatusCancelled, true},
+		{entities.PurchaseStatusExpired, true},
+		{entities.PurchaseStatusRefunded, true},
+	}
+
+	for _, tt := range tests {
+		if got := IsFinalStatus(tt.status); got != tt.want {
+			t.Errorf("IsFinalStatus(%s) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
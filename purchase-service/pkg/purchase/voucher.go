@@ -0,0 +1,392 @@
+package purchase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"purchase-service/api/presenter"
+	"purchase-service/pkg/dtos"
+	"purchase-service/pkg/entities"
+	"purchase-service/pkg/money"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// CreateVoucher creates a discount code for the calling seller's items
+func (s *service) CreateVoucher(ctx context.Context, req dtos.CreateVoucherRequest) (*presenter.VoucherResponse, error) {
+	sellerID, ok := ctx.Value("user_id").(string)
+	if !ok || sellerID == "" {
+		return nil, ErrUnauthenticated
+	}
+	parsedSellerID, err := uuid.Parse(sellerID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seller ID: %w", err)
+	}
+
+	return s.createVoucher(ctx, &parsedSellerID, req)
+}
+
+// CreatePlatformVoucher creates a discount code for whole purchases, on an
+// admin's request
+func (s *service) CreatePlatformVoucher(ctx context.Context, req dtos.CreateVoucherRequest) (*presenter.VoucherResponse, error) {
+	if adminID, ok := ctx.Value("user_id").(string); !ok || adminID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	return s.createVoucher(ctx, nil, req)
+}
+
+// createVoucher validates and stores a voucher of the seller, or a platform
+// voucher when sellerID is nil
+func (s *service) createVoucher(ctx context.Context, sellerID *uuid.UUID, req dtos.CreateVoucherRequest) (*presenter.VoucherResponse, error) {
+	voucher := &entities.Voucher{
+		Code:         normalizeVoucherCode(req.Code),
+		SellerID:     sellerID,
+		Type:         entities.VoucherType(req.Type),
+		Percentage:   req.Percentage,
+		Amount:       req.Amount,
+		MaxDiscount:  req.MaxDiscount,
+		Currency:     req.Currency,
+		MinSpend:     req.MinSpend,
+		UsageLimit:   req.UsageLimit,
+		PerUserLimit: req.PerUserLimit,
+		StartsAt:     req.StartsAt,
+		EndsAt:       req.EndsAt,
+	}
+	if voucher.Currency == "" {
+		voucher.Currency = money.DefaultCurrency
+	}
+
+	switch voucher.Type {
+	case entities.VoucherTypePercentage:
+		if voucher.Percentage < 1 || voucher.Percentage > 100 {
+			return nil, fmt.Errorf("%w: percentage must be between 1 and 100", ErrInvalidInput)
+		}
+		if voucher.Amount != 0 {
+			return nil, fmt.Errorf("%w: amount only applies to fixed vouchers", ErrInvalidInput)
+		}
+		if voucher.MaxDiscount != nil && *voucher.MaxDiscount <= 0 {
+			return nil, fmt.Errorf("%w: maxDiscount must be positive", ErrInvalidInput)
+		}
+	case entities.VoucherTypeFixed:
+		if voucher.Amount <= 0 {
+			return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidInput)
+		}
+		if voucher.Percentage != 0 || voucher.MaxDiscount != nil {
+			return nil, fmt.Errorf("%w: percentage and maxDiscount only apply to percentage vouchers", ErrInvalidInput)
+		}
+	default:
+		return nil, fmt.Errorf("%w: unknown voucher type %s", ErrInvalidInput, req.Type)
+	}
+	if voucher.MinSpend < 0 {
+		return nil, fmt.Errorf("%w: minSpend must not be negative", ErrInvalidInput)
+	}
+	if voucher.StartsAt != nil && voucher.EndsAt != nil && !voucher.StartsAt.Before(*voucher.EndsAt) {
+		return nil, fmt.Errorf("%w: startsAt must be before endsAt", ErrInvalidInput)
+	}
+
+	created, err := s.repo.CreateVoucher(ctx, voucher)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create voucher: %w", err)
+	}
+	if !created {
+		return nil, fmt.Errorf("%w: voucher code %s is already taken", ErrConflict, voucher.Code)
+	}
+
+	response := voucherResponse(voucher)
+	return &response, nil
+}
+
+// ListSellerVouchers lists the calling seller's vouchers, newest first
+func (s *service) ListSellerVouchers(ctx context.Context, page, limit int) (*presenter.ListVouchersResponse, error) {
+	sellerID, ok := ctx.Value("user_id").(string)
+	if !ok || sellerID == "" {
+		return nil, ErrUnauthenticated
+	}
+
+	if page < 1 {
+		page = 1
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 10
+	}
+
+	vouchers, total, err := s.repo.GetVouchersBySellerID(ctx, sellerID, page, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get vouchers: %w", err)
+	}
+
+	responses := make([]presenter.VoucherResponse, 0, len(vouchers))
+	for _, voucher := range vouchers {
+		responses = append(responses, voucherResponse(voucher))
+	}
+
+	return &presenter.ListVouchersResponse{
+		Vouchers: responses,
+		Total:    int(total),
+		Page:     page,
+		Limit:    limit,
+	}, nil
+}
+
+// DisableVoucher stops a seller's voucher from being redeemed. Purchases that
+// already used it keep their discount.
+func (s *service) DisableVoucher(ctx context.Context, voucherID string) error {
+	sellerID, ok := ctx.Value("user_id").(string)
+	if !ok || sellerID == "" {
+		return ErrUnauthenticated
+	}
+
+	voucher, err := getVoucher(ctx, s.repo, voucherID)
+	if err != nil {
+		return err
+	}
+	if voucher.SellerID == nil || voucher.SellerID.String() != sellerID {
+		return fmt.Errorf("%w: voucher does not belong to seller", ErrForbidden)
+	}
+
+	if err := s.repo.DisableVoucher(ctx, voucherID, time.Now()); err != nil {
+		return fmt.Errorf("failed to disable voucher: %w", err)
+	}
+	return nil
+}
+
+// DisablePlatformVoucher stops a platform voucher from being redeemed, on an
+// admin's request. Sellers' vouchers are left to their sellers.
+func (s *service) DisablePlatformVoucher(ctx context.Context, voucherID string) error {
+	if adminID, ok := ctx.Value("user_id").(string); !ok || adminID == "" {
+		return ErrUnauthenticated
+	}
+
+	voucher, err := getVoucher(ctx, s.repo, voucherID)
+	if err != nil {
+		return err
+	}
+	if voucher.SellerID != nil {
+		return fmt.Errorf("%w: voucher belongs to a seller", ErrForbidden)
+	}
+
+	if err := s.repo.DisableVoucher(ctx, voucherID, time.Now()); err != nil {
+		return fmt.Errorf("failed to disable voucher: %w", err)
+	}
+	return nil
+}
+
+// getVoucher loads a voucher by ID, reporting malformed and unknown IDs as not found
+func getVoucher(ctx context.Context, repo Repository, voucherID string) (*entities.Voucher, error) {
+	errVoucherNotFound := fmt.Errorf("voucher %w", ErrNotFound)
+	if _, err := uuid.Parse(voucherID); err != nil {
+		return nil, errVoucherNotFound
+	}
+	voucher, err := repo.GetVoucherByID(ctx, voucherID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, errVoucherNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get voucher: %w", err)
+	}
+	return voucher, nil
+}
+
+// normalizeVoucherCode makes codes case-insensitive; they are stored in upper case
+func normalizeVoucherCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// getVoucherByCode loads a voucher, reporting unknown codes as not found
+func getVoucherByCode(ctx context.Context, repo Repository, code string) (*entities.Voucher, error) {
+	voucher, err := repo.GetVoucherByCode(ctx, normalizeVoucherCode(code))
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("voucher %s %w", code, ErrNotFound)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get voucher: %w", err)
+	}
+	return voucher, nil
+}
+
+// voucherDiscount works out what a voucher takes off a purchase, split
+// between the sellers and keyed by seller ID. It checks everything about the
+// voucher except its usage limits.
+func voucherDiscount(voucher *entities.Voucher, currency string, sellerTotals map[string]money.Amount, now time.Time) (map[string]money.Amount, error) {
+	switch {
+	case voucher.DisabledAt != nil:
+		return nil, fmt.Errorf("%w: voucher %s is no longer available", ErrInvalidInput, voucher.Code)
+	case voucher.StartsAt != nil && now.Before(*voucher.StartsAt):
+		return nil, fmt.Errorf("%w: voucher %s is not valid yet", ErrInvalidInput, voucher.Code)
+	case voucher.EndsAt != nil && !now.Before(*voucher.EndsAt):
+		return nil, fmt.Errorf("%w: voucher %s has expired", ErrInvalidInput, voucher.Code)
+	case voucher.Currency != currency:
+		return nil, fmt.Errorf("%w: voucher %s is for purchases in %s", ErrInvalidInput, voucher.Code, voucher.Currency)
+	}
+
+	// A seller's voucher only discounts that seller's items
+	eligible := sellerTotals
+	if voucher.SellerID != nil {
+		sellerID := voucher.SellerID.String()
+		total, ok := sellerTotals[sellerID]
+		if !ok {
+			return nil, fmt.Errorf("%w: voucher %s does not apply to any item in the purchase", ErrInvalidInput, voucher.Code)
+		}
+		eligible = map[string]money.Amount{sellerID: total}
+	}
+
+	var subtotal money.Amount
+	for _, total := range eligible {
+		subtotal = subtotal.Add(total)
+	}
+	if subtotal < voucher.MinSpend {
+		return nil, fmt.Errorf("%w: voucher %s needs a minimum spend of %s %s", ErrInvalidInput, voucher.Code, voucher.Currency, voucher.MinSpend)
+	}
+
+	var discount money.Amount
+	switch voucher.Type {
+	case entities.VoucherTypePercentage:
+		// Rounded down, so the discount never exceeds the percentage
+		discount = money.Amount(subtotal.Minor() * int64(voucher.Percentage) / 100)
+		if voucher.MaxDiscount != nil && discount > *voucher.MaxDiscount {
+			discount = *voucher.MaxDiscount
+		}
+	case entities.VoucherTypeFixed:
+		discount = voucher.Amount
+	default:
+		return nil, fmt.Errorf("unknown type %s of voucher %s", voucher.Type, voucher.Code)
+	}
+	if discount > subtotal {
+		discount = subtotal
+	}
+
+	return allocateDiscount(discount, eligible), nil
+}
+
+// allocateDiscount splits a discount between sellers in proportion to their
+// totals. The minor units left over by rounding down go to the largest
+// remainders, so the shares always add up to the discount.
+func allocateDiscount(discount money.Amount, totals map[string]money.Amount) map[string]money.Amount {
+	sellerIDs := make([]string, 0, len(totals))
+	var sum money.Amount
+	for sellerID, total := range totals {
+		sellerIDs = append(sellerIDs, sellerID)
+		sum = sum.Add(total)
+	}
+	sort.Strings(sellerIDs)
+
+	shares := make(map[string]money.Amount, len(totals))
+	if sum <= 0 {
+		return shares
+	}
+
+	// discount * total can overflow int64 for large amounts
+	remainders := make(map[string]int64, len(totals))
+	var allocated money.Amount
+	for _, sellerID := range sellerIDs {
+		product := new(big.Int).Mul(big.NewInt(discount.Minor()), big.NewInt(totals[sellerID].Minor()))
+		share, remainder := new(big.Int).QuoRem(product, big.NewInt(sum.Minor()), new(big.Int))
+		shares[sellerID] = money.Amount(share.Int64())
+		remainders[sellerID] = remainder.Int64()
+		allocated = allocated.Add(shares[sellerID])
+	}
+
+	sort.SliceStable(sellerIDs, func(i, j int) bool {
+		return remainders[sellerIDs[i]] > remainders[sellerIDs[j]]
+	})
+	for i := 0; allocated < discount; i++ {
+		shares[sellerIDs[i]] = shares[sellerIDs[i]].Add(1)
+		allocated = allocated.Add(1)
+	}
+	return shares
+}
+
+// checkVoucherUsage enforces a voucher's global and per-user usage limits
+func checkVoucherUsage(ctx context.Context, repo Repository, voucher *entities.Voucher, userID string) error {
+	if voucher.UsageLimit != nil && voucher.UsedCount >= *voucher.UsageLimit {
+		return fmt.Errorf("%w: voucher %s has been fully redeemed", ErrConflict, voucher.Code)
+	}
+	if voucher.PerUserLimit != nil {
+		used, err := repo.CountVoucherRedemptions(ctx, voucher.ID.String(), userID)
+		if err != nil {
+			return fmt.Errorf("failed to count voucher redemptions: %w", err)
+		}
+		if used >= int64(*voucher.PerUserLimit) {
+			return fmt.Errorf("%w: voucher %s can only be used %d times per user", ErrConflict, voucher.Code, *voucher.PerUserLimit)
+		}
+	}
+	return nil
+}
+
+// redeemVoucher records the use of a voucher on a purchase being written. The
+// voucher row is locked and checked again, so concurrent purchases cannot
+// redeem it past its limits; a voucher changed since the purchase was priced
+// is a conflict.
+func redeemVoucher(ctx context.Context, tx Repository, voucherID string, purchase *entities.Purchase, sellerTotals map[string]money.Amount) error {
+	voucher, err := tx.LockVoucherByID(ctx, voucherID)
+	if err != nil {
+		return fmt.Errorf("failed to lock voucher: %w", err)
+	}
+
+	discounts, err := voucherDiscount(voucher, purchase.Currency, sellerTotals, time.Now())
+	if err != nil {
+		return err
+	}
+	var discount money.Amount
+	for _, share := range discounts {
+		discount = discount.Add(share)
+	}
+	if discount != purchase.Discount {
+		return fmt.Errorf("%w: voucher %s changed while the purchase was placed, try again", ErrConflict, voucher.Code)
+	}
+	if err := checkVoucherUsage(ctx, tx, voucher, purchase.UserID.String()); err != nil {
+		return err
+	}
+
+	if err := tx.CreateVoucherRedemption(ctx, &entities.VoucherRedemption{
+		VoucherID:  voucher.ID,
+		PurchaseID: purchase.ID,
+		UserID:     purchase.UserID,
+		Discount:   discount,
+	}); err != nil {
+		return fmt.Errorf("failed to redeem voucher: %w", err)
+	}
+	if err := tx.IncrementVoucherUsage(ctx, voucherID); err != nil {
+		return fmt.Errorf("failed to redeem voucher: %w", err)
+	}
+	return nil
+}
+
+// voucherResponse converts a stored voucher to its API representation
+func voucherResponse(voucher *entities.Voucher) presenter.VoucherResponse {
+	format := func(t *time.Time) *string {
+		if t == nil {
+			return nil
+		}
+		formatted := t.Format(time.RFC3339)
+		return &formatted
+	}
+
+	response := presenter.VoucherResponse{
+		VoucherID:    voucher.ID.String(),
+		Code:         voucher.Code,
+		Type:         string(voucher.Type),
+		Percentage:   voucher.Percentage,
+		Amount:       voucher.Amount,
+		MaxDiscount:  voucher.MaxDiscount,
+		Currency:     voucher.Currency,
+		MinSpend:     voucher.MinSpend,
+		UsageLimit:   voucher.UsageLimit,
+		PerUserLimit: voucher.PerUserLimit,
+		UsedCount:    voucher.UsedCount,
+		StartsAt:     format(voucher.StartsAt),
+		EndsAt:       format(voucher.EndsAt),
+		DisabledAt:   format(voucher.DisabledAt),
+		CreatedAt:    voucher.CreatedAt.Format(time.RFC3339),
+	}
+	if voucher.SellerID != nil {
+		response.SellerID = voucher.SellerID.String()
+	}
+	return response
+}
@@ -0,0 +1,150 @@
+package purchase
+
+import (
+	"errors"
+	"purchase-service/pkg/entities"
+	"purchase-service/pkg/money"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestVoucherDiscount(t *testing.T) {
+	now := time.Date(2025, time.September, 10, 12, 0, 0, 0, time.UTC)
+	sellerA := uuid.New()
+	sellerB := uuid.New()
+	otherSeller := uuid.New()
+	a, b := sellerA.String(), sellerB.String()
+	totals := map[string]money.Amount{a: mustAmount(t, "150.00"), b: mustAmount(t, "100.00")}
+
+	maxDiscount := mustAmount(t, "20.00")
+	yesterday := now.Add(-24 * time.Hour)
+	tomorrow := now.Add(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		voucher entities.Voucher
+		want    map[string]money.Amount
+		wantErr bool
+	}{
+		{
+			name:    "percentage split by seller totals",
+			voucher: entities.Voucher{Type: entities.VoucherTypePercentage, Percentage: 10, Currency: "IDR"},
+			want:    map[string]money.Amount{a: mustAmount(t, "15.00"), b: mustAmount(t, "10.00")},
+		},
+		{
+			name:    "percentage capped",
+			voucher: entities.Voucher{Type: entities.VoucherTypePercentage, Percentage: 10, MaxDiscount: &maxDiscount, Currency: "IDR"},
+			want:    map[string]money.Amount{a: mustAmount(t, "12.00"), b: mustAmount(t, "8.00")},
+		},
+		{
+			name:    "fixed amount above the subtotal takes it all",
+			voucher: entities.Voucher{Type: entities.VoucherTypeFixed, Amount: mustAmount(t, "500.00"), Currency: "IDR"},
+			want:    map[string]money.Amount{a: mustAmount(t, "150.00"), b: mustAmount(t, "100.00")},
+		},
+		{
+			name:    "seller voucher only discounts that seller",
+			voucher: entities.Voucher{SellerID: &sellerB, Type: entities.VoucherTypeFixed, Amount: mustAmount(t, "30.00"), MinSpend: mustAmount(t, "100.00"), Currency: "IDR"},
+			want:    map[string]money.Amount{b: mustAmount(t, "30.00")},
+		},
+		{
+			name:    "seller voucher without that seller's items",
+			voucher: entities.Voucher{SellerID: &otherSeller, Type: entities.VoucherTypeFixed, Amount: mustAmount(t, "30.00"), Currency: "IDR"},
+			wantErr: true,
+		},
+		{
+			name:    "minimum spend counts only the eligible items",
+			voucher: entities.Voucher{SellerID: &sellerB, Type: entities.VoucherTypeFixed, Amount: mustAmount(t, "30.00"), MinSpend: mustAmount(t, "100.01"), Currency: "IDR"},
+			wantErr: true,
+		},
+		{
+			name:    "disabled",
+			voucher: entities.Voucher{Type: entities.VoucherTypeFixed, Amount: mustAmount(t, "10.00"), Currency: "IDR", DisabledAt: &yesterday},
+			wantErr: true,
+		},
+		{
+			name:    "not started",
+			voucher: entities.Voucher{Type: entities.VoucherTypeFixed, Amount: mustAmount(t, "10.00"), Currency: "IDR", StartsAt: &tomorrow},
+			wantErr: true,
+		},
+		{
+			name:    "ended",
+			voucher: entities.Voucher{Type: entities.VoucherTypeFixed, Amount: mustAmount(t, "10.00"), Currency: "IDR", EndsAt: &now},
+			wantErr: true,
+		},
+		{
+			name:    "other currency",
+			voucher: entities.Voucher{Type: entities.VoucherTypeFixed, Amount: mustAmount(t, "10.00"), Currency: "USD"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.voucher.Code = "SAVE"
+			got, err := voucherDiscount(&tt.voucher, "IDR", totals, now)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidInput) {
+					t.Fatalf("voucherDiscount() error = %v, want ErrInvalidInput", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("voucherDiscount() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("voucherDiscount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAllocateDiscount(t *testing.T) {
+	tests := []struct {
+		name     string
+		discount money.Amount
+		totals   map[string]money.Amount
+		want     map[string]money.Amount
+	}{
+		{
+			name:     "in proportion to the totals",
+			discount: 300,
+			totals:   map[string]money.Amount{"a": 2000, "b": 1000},
+			want:     map[string]money.Amount{"a": 200, "b": 100},
+		},
+		{
+			name:     "leftover units go to the largest remainders",
+			discount: 100,
+			totals:   map[string]money.Amount{"a": 100, "b": 200},
+			want:     map[string]money.Amount{"a": 33, "b": 67},
+		},
+		{
+			name:     "ties go in seller order",
+			discount: 10,
+			totals:   map[string]money.Amount{"c": 1, "a": 1, "b": 1},
+			want:     map[string]money.Amount{"a": 4, "b": 3, "c": 3},
+		},
+		{
+			name:     "no total to share",
+			discount: 100,
+			totals:   map[string]money.Amount{"a": 0},
+			want:     map[string]money.Amount{},
+		},
+		{
+			name:     "large amounts do not overflow",
+			discount: money.Amount(9_000_000_000_000_000),
+			totals:   map[string]money.Amount{"a": 3_000_000_000_000_000, "b": 6_000_000_000_000_000},
+			want:     map[string]money.Amount{"a": 3_000_000_000_000_000, "b": 6_000_000_000_000_000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := allocateDiscount(tt.discount, tt.totals); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("allocateDiscount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}